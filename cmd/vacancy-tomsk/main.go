@@ -3,22 +3,92 @@ package main
 import (
 	"auth/internal/config"
 	"auth/internal/domain/models"
+	"auth/internal/grpc-server/authserver"
+	"auth/internal/grpc-server/extauthz"
+	"auth/internal/grpc-server/interceptors"
+	"auth/internal/grpc-server/userserver"
+	"auth/internal/http-server/apispec"
+	"auth/internal/http-server/handlers/url/approveemployer"
 	"auth/internal/http-server/handlers/url/deleteuser"
 	"auth/internal/http-server/handlers/url/forgotpassword"
+	"auth/internal/http-server/handlers/url/grantpermission"
+	"auth/internal/http-server/handlers/url/jwks"
 	"auth/internal/http-server/handlers/url/login"
+	"auth/internal/http-server/handlers/url/loginmfa"
+	"auth/internal/http-server/handlers/url/logout"
+	"auth/internal/http-server/handlers/url/logoutall"
+	"auth/internal/http-server/handlers/url/me"
+	"auth/internal/http-server/handlers/url/oauthauthorize"
+	"auth/internal/http-server/handlers/url/oauthclients"
+	"auth/internal/http-server/handlers/url/oauthconsent"
+	"auth/internal/http-server/handlers/url/oauthintrospect"
+	"auth/internal/http-server/handlers/url/oauthrevoke"
+	"auth/internal/http-server/handlers/url/oauthtoken"
+	"auth/internal/http-server/handlers/url/openapidocument"
+	"auth/internal/http-server/handlers/url/openidconfiguration"
+	"auth/internal/http-server/handlers/url/pendingemployers"
+	"auth/internal/http-server/handlers/url/permissions"
+	"auth/internal/http-server/handlers/url/refreshtokens"
 	"auth/internal/http-server/handlers/url/register"
+	"auth/internal/http-server/handlers/url/registeremployer"
+	"auth/internal/http-server/handlers/url/registeroauthclient"
+	"auth/internal/http-server/handlers/url/registerserviceaccount"
+	"auth/internal/http-server/handlers/url/rejectemployer"
+	"auth/internal/http-server/handlers/url/resendverification"
 	"auth/internal/http-server/handlers/url/restorepassword"
 	"auth/internal/http-server/handlers/url/restoreuser"
+	"auth/internal/http-server/handlers/url/revokepermission"
+	"auth/internal/http-server/handlers/url/revokesession"
+	"auth/internal/http-server/handlers/url/rolepermissions"
+	"auth/internal/http-server/handlers/url/sendphonecode"
+	"auth/internal/http-server/handlers/url/serviceaccountpermissions"
+	"auth/internal/http-server/handlers/url/sessions"
+	"auth/internal/http-server/handlers/url/totpconfirm"
+	"auth/internal/http-server/handlers/url/totpenroll"
+	"auth/internal/http-server/handlers/url/unlockuser"
+	"auth/internal/http-server/handlers/url/updateme"
 	"auth/internal/http-server/handlers/url/updateuser"
+	"auth/internal/http-server/handlers/url/user"
+	"auth/internal/http-server/handlers/url/userinfo"
+	"auth/internal/http-server/handlers/url/verify"
+	"auth/internal/http-server/handlers/url/verifyemail"
+	"auth/internal/http-server/handlers/url/verifyphone"
+	"auth/internal/http-server/middleware/authentication"
 	"auth/internal/http-server/middleware/authorization"
 	"auth/internal/http-server/middleware/logger"
+	ratelimitMiddleware "auth/internal/http-server/middleware/ratelimit"
+	"auth/internal/http-server/middleware/realip"
+	"auth/internal/http-server/middleware/requestvalidation"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/email"
 	"auth/internal/lib/logger/sl"
+	"auth/internal/lib/ratelimit"
+	"auth/internal/lib/sealer"
+	"auth/internal/lib/sms"
 	authService "auth/internal/services/auth"
+	authnService "auth/internal/services/authn"
+	emailVerificationService "auth/internal/services/emailverification"
+	introspectionService "auth/internal/services/introspection"
+	keysService "auth/internal/services/keys"
 	linksService "auth/internal/services/links"
+	lockoutService "auth/internal/services/lockout"
+	loginService "auth/internal/services/login"
+	mfaService "auth/internal/services/mfa"
+	moderationService "auth/internal/services/moderation"
+	oauthService "auth/internal/services/oauth"
+	permissionsService "auth/internal/services/permissions"
+	phoneVerificationService "auth/internal/services/phoneverification"
+	sessionsService "auth/internal/services/sessions"
+	tokensService "auth/internal/services/tokens"
+	verificationService "auth/internal/services/verification"
 	"auth/internal/storage/postgres"
+	authv1 "auth/pkg/api/auth/v1"
+	"fmt"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 )
@@ -28,6 +98,35 @@ const (
 	envProd  = "prod"
 )
 
+const (
+	rateLimitMemory   = "memory"
+	rateLimitPostgres = "postgres"
+)
+
+type routerDeps struct {
+	client            *http.Client
+	storage           *postgres.Storage
+	keys              *keysService.Service
+	auth              *authService.Service
+	links             *linksService.Service
+	userSessions      *sessionsService.Service
+	rbac              *permissionsService.Service
+	authenticator     *authnService.Service
+	tokens            *tokensService.Service
+	mfa               *mfaService.Service
+	loginGuard        *lockoutService.Service
+	login             *loginService.Service
+	moderation        *moderationService.Service
+	oauth             *oauthService.Service
+	introspection     *introspectionService.Service
+	emailVerification *emailVerificationService.Service
+	phoneVerification *phoneVerificationService.Service
+	verification      *verificationService.Service
+	ipResolver        *clientip.Resolver
+	rateLimit         func(route string) func(http.Handler) http.Handler
+	forwardAuthPolicy *authorization.Policy
+}
+
 func main() {
 	// Config init
 	cfg := config.MustLoad()
@@ -46,37 +145,143 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Signing keys init
+	keysSealer, err := sealer.New(cfg.EncryptionKey)
+	if err != nil {
+		log.Error("failed to init signing keys encryption", sl.Err(err))
+		os.Exit(1)
+	}
+
+	keys, err := keysService.New(storage, keysSealer, cfg.Algorithm, cfg.RotationInterval, cfg.RefreshInterval, cfg.PublishDelay, cfg.KeyTtl, max(cfg.TokenTtl, cfg.MfaChallengeTtl))
+	if err != nil {
+		log.Error("failed to init signing keys", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if err := keys.Load(); err != nil {
+		log.Error("failed to load signing keys", sl.Err(err))
+		os.Exit(1)
+	}
+
+	go keys.Run(log)
+
 	// Services init
 	auth := authService.New(storage, cfg.TokenTtl)
 	links := linksService.New(storage)
+	userSessions := sessionsService.New(storage, cfg.SessionTouchInterval)
+	rbac := permissionsService.New(storage, cfg.PermissionsCacheTtl)
+	authenticator := authnService.New(keys, userSessions, storage, rbac)
+	tokens := tokensService.New(storage, auth, rbac, keys, cfg.TokenTtl, cfg.RefreshTokenTtl)
+	mfa := mfaService.New(storage, keys, cfg.TotpIssuer, cfg.MfaChallengeTtl, cfg.RecoveryCodesCount)
+	loginGuard := lockoutService.New(storage, cfg.MaxFailedAttempts, cfg.IpMaxFailedAttempts, cfg.FailureWindow, cfg.LockoutDuration, cfg.ProgressiveDelay)
+	emailSender := email.NewSender(client, cfg.ApiKey, cfg.Name, cfg.Email)
+	moderation := moderationService.New(storage, auth, emailSender)
+	oauth := oauthService.New(storage, auth, tokens, keys, cfg.OAuthIssuer, cfg.OAuthCodeTtl, cfg.TokenTtl)
+	introspection := introspectionService.New(storage, oauth, userSessions, keys)
+
+	emailVerification, err := emailVerificationService.New(storage, emailSender, cfg.EmailVerificationUrl, cfg.EmailVerificationTtl, cfg.EmailResendInterval, cfg.EmailRequiredForLogin, cfg.EmailRequiredForRoles)
+	if err != nil {
+		log.Error("failed to init email verification", sl.Err(err))
+		os.Exit(1)
+	}
+
+	smsSender, err := sms.New(cfg.SmsProvider, cfg.SmsFilePath, log)
+	if err != nil {
+		log.Error("failed to init sms sender", sl.Err(err))
+		os.Exit(1)
+	}
+
+	login := loginService.New(log, auth, tokens, mfa, emailVerification, loginGuard)
+	phoneVerification := phoneVerificationService.New(storage, smsSender, cfg.PhoneCodeTtl, cfg.PhoneResendInterval, cfg.PhoneCodeMaxAttempts, cfg.PhoneAttemptsWindow)
+	verification := verificationService.New(log, emailVerification, phoneVerification)
+
+	// Rate limits init
+	limiter, rateLimitRoutes, err := setupRateLimit(cfg.RateLimit, storage)
+	if err != nil {
+		log.Error("failed to init rate limits", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// Forward auth init
+	forwardAuthPolicy, err := setupForwardAuth(cfg.ForwardAuth)
+	if err != nil {
+		log.Error("failed to init forward auth rules", sl.Err(err))
+		os.Exit(1)
+	}
 
 	// Router init
-	router := chi.NewRouter()
+	ipResolver, err := clientip.New(cfg.TrustedProxies)
+	if err != nil {
+		log.Error("failed to init trusted proxies", sl.Err(err))
+		os.Exit(1)
+	}
 
-	// middlewares
-	router.Use(middleware.RequestID)
-	router.Use(logger.New(log))
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.URLFormat)
+	router := setupRouter(log, cfg, routerDeps{
+		client:            client,
+		storage:           storage,
+		keys:              keys,
+		auth:              auth,
+		links:             links,
+		userSessions:      userSessions,
+		rbac:              rbac,
+		authenticator:     authenticator,
+		tokens:            tokens,
+		mfa:               mfa,
+		loginGuard:        loginGuard,
+		login:             login,
+		moderation:        moderation,
+		oauth:             oauth,
+		introspection:     introspection,
+		emailVerification: emailVerification,
+		phoneVerification: phoneVerification,
+		verification:      verification,
+		ipResolver:        ipResolver,
+		rateLimit:         routeRateLimit(log, limiter, cfg.RateLimitFailOpen, rateLimitRoutes),
+		forwardAuthPolicy: forwardAuthPolicy,
+	})
 
-	// Handlers
-	registerHandler := register.New(log, auth)
-	loginHandler := login.New(log, auth, cfg.TokenTtl, cfg.SecretKey)
-	restorePasswordHandler := restorepassword.New(log, auth, storage)
-	forgotPasswordHandler := forgotpassword.New(log, links, auth, client, cfg.LinkTtl, cfg.ApiKey, cfg.Name, cfg.Email)
-	updateUserHandler := authorization.New(updateuser.New(log, auth), log, cfg.SecretKey, auth, []models.UserRole{models.JobSeeker, models.Admin})
-	deleteUserHandler := authorization.New(deleteuser.New(log, auth), log, cfg.SecretKey, auth, []models.UserRole{models.JobSeeker, models.Admin})
-	restoreUserHandler := authorization.New(restoreuser.New(log, auth), log, cfg.SecretKey, auth, []models.UserRole{models.JobSeeker, models.Admin})
-
-	// TODO: по-хорошему надо сделать отдельный хэндлер регистрации для работодателя
-	router.Post("/api/auth/register", registerHandler)
-	router.Get("/api/auth/login", loginHandler)
-	router.Put("/api/auth/restore-password", restorePasswordHandler)
-	router.Post("/api/auth/forgot-password", forgotPasswordHandler)
-	router.Post("/api/auth/refresh-tokens", restorepassword.New(log, auth, storage))
-	router.Put("/api/auth/update-user", updateUserHandler)
-	router.Put("/api/auth/delete-user", deleteUserHandler)
-	router.Put("/api/auth/restore-user", restoreUserHandler)
+	// gRPC server init
+	gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptors.RequestID(),
+		interceptors.ClientIp(ipResolver),
+		interceptors.Logger(log),
+		interceptors.Recoverer(log),
+		interceptors.Authentication(log, authenticator,
+			authv1.AuthService_Register_FullMethodName,
+			authv1.AuthService_Login_FullMethodName,
+			authv1.AuthService_RefreshTokens_FullMethodName,
+			authv1.AuthService_ValidateToken_FullMethodName,
+			extauthz.CheckFullMethodName,
+		),
+		interceptors.RateLimit(log, limiter, cfg.RateLimitFailOpen, map[string][]ratelimitMiddleware.Policy{
+			authv1.AuthService_Register_FullMethodName:      rateLimitRoutes["register"],
+			authv1.AuthService_Login_FullMethodName:         rateLimitRoutes["login"],
+			authv1.AuthService_RefreshTokens_FullMethodName: rateLimitRoutes["refresh_tokens"],
+		}),
+		interceptors.Authorization(log, map[string]interceptors.Rule{
+			authv1.UserService_GetUser_FullMethodName: {
+				Resolver:    interceptors.FromUserId(),
+				Permissions: []string{models.PermissionUsersReadSelf, models.PermissionUsersReadAny},
+			},
+		}),
+	))
+	extauthz.Register(gRPCServer, log, authenticator, cfg.ForwardAuthCookieName, forwardAuthPolicy)
+	authserver.Register(gRPCServer, log, auth, login, emailVerification, authenticator)
+	userserver.Register(gRPCServer, log, auth)
+
+	listener, err := net.Listen("tcp", cfg.GrpcAddress)
+	if err != nil {
+		log.Error("failed to listen grpc address", sl.Err(err))
+		os.Exit(1)
+	}
+
+	go func() {
+		log.Info("starting grpc server", slog.String("address", cfg.GrpcAddress))
+
+		if err := gRPCServer.Serve(listener); err != nil {
+			log.Error("failed to start grpc server", sl.Err(err))
+		}
+	}()
 
 	log.Info("starting server", slog.String("address", cfg.Address))
 
@@ -94,8 +299,191 @@ func main() {
 	}
 
 	log.Error("server stopped")
+}
+
+func setupRouter(log *slog.Logger, cfg *config.Config, deps routerDeps) *chi.Mux {
+	spec := apispec.New()
+
+	router := chi.NewRouter()
+
+	// middlewares
+	router.Use(middleware.RequestID)
+	router.Use(realip.New(deps.ipResolver))
+	router.Use(logger.New(log))
+	router.Use(middleware.Recoverer)
+	router.Use(requestvalidation.New(log, spec))
+
+	// Handlers
+	registerHandler := register.New(log, deps.auth, deps.emailVerification)
+	registerEmployerHandler := registeremployer.New(log, deps.auth, deps.emailVerification)
+	loginHandler := login.New(log, deps.login)
+	loginMfaHandler := loginmfa.New(log, deps.login)
+	refreshTokensHandler := refreshtokens.New(log, deps.login)
+	restorePasswordHandler := restorepassword.New(log, deps.auth, deps.storage, deps.loginGuard)
+	forgotPasswordHandler := forgotpassword.New(log, deps.links, deps.auth, deps.client, cfg.LinkTtl, cfg.ApiKey, cfg.Name, cfg.Email)
+	userHandler := user.New(log, deps.auth)
+	updateUserHandler := updateuser.New(log, deps.auth, deps.verification)
+	meHandler := me.New(log, deps.auth)
+	updateMeHandler := updateme.New(log, deps.auth, deps.verification)
+	deleteUserHandler := deleteuser.New(log, deps.auth)
+	restoreUserHandler := restoreuser.New(log, deps.auth)
+	logoutHandler := logout.New(log, deps.userSessions)
+	logoutAllHandler := logoutall.New(log, deps.userSessions)
+	sessionsHandler := sessions.New(log, deps.userSessions)
+	revokeSessionHandler := revokesession.New(log, deps.userSessions)
+	jwksHandler := jwks.New(log, deps.keys)
+	totpEnrollHandler := totpenroll.New(log, deps.auth, deps.mfa)
+	totpConfirmHandler := totpconfirm.New(log, deps.mfa)
+	verifyEmailHandler := verifyemail.New(log, deps.emailVerification)
+	resendVerificationHandler := resendverification.New(log, deps.auth, deps.emailVerification)
+	sendPhoneCodeHandler := sendphonecode.New(log, deps.auth, deps.phoneVerification)
+	verifyPhoneHandler := verifyphone.New(log, deps.phoneVerification)
+	unlockUserHandler := unlockuser.New(log, deps.loginGuard)
+	pendingEmployersHandler := pendingemployers.New(log, deps.moderation)
+	approveEmployerHandler := approveemployer.New(log, deps.moderation)
+	rejectEmployerHandler := rejectemployer.New(log, deps.moderation)
+	oauthAuthorizeHandler := oauthauthorize.New(log, deps.oauth, deps.authenticator, cfg.ForwardAuthCookieName, cfg.OAuthLoginUrl, cfg.OAuthConsentUrl)
+	oauthConsentHandler := oauthconsent.New(log, deps.oauth)
+	oauthTokenHandler := oauthtoken.New(log, deps.oauth)
+	oauthIntrospectHandler := oauthintrospect.New(log, deps.introspection)
+	oauthRevokeHandler := oauthrevoke.New(log, deps.introspection)
+	registerOAuthClientHandler := registeroauthclient.New(log, deps.oauth)
+	oauthClientsHandler := oauthclients.New(log, deps.oauth)
+	registerServiceAccountHandler := registerserviceaccount.New(log, deps.oauth)
+	serviceAccountPermissionsHandler := serviceaccountpermissions.New(log, deps.rbac)
+	openIdConfigurationHandler := openidconfiguration.New(log, cfg.OAuthIssuer, cfg.Algorithm)
+	userInfoHandler := userinfo.New(log, deps.authenticator, deps.oauth)
+	openApiDocumentHandler := openapidocument.New(log, spec)
+	verifyHandler := verify.New(log, deps.authenticator, cfg.ForwardAuthCookieName, deps.forwardAuthPolicy)
+	permissionsHandler := permissions.New(log, deps.rbac)
+	rolePermissionsHandler := rolepermissions.New(log, deps.rbac)
+	grantPermissionHandler := grantpermission.New(log, deps.rbac)
+	revokePermissionHandler := revokepermission.New(log, deps.rbac)
+
+	router.With(deps.rateLimit("register")).Post("/api/auth/register", registerHandler)
+	router.With(deps.rateLimit("register_employer")).Post("/api/auth/register-employer", registerEmployerHandler)
+	router.With(deps.rateLimit("login")).Get("/api/auth/login", loginHandler)
+	router.With(deps.rateLimit("login_mfa")).Post("/api/auth/login/mfa", loginMfaHandler)
+	router.With(deps.rateLimit("restore_password")).Put("/api/auth/restore-password", restorePasswordHandler)
+	router.With(deps.rateLimit("forgot_password")).Post("/api/auth/forgot-password", forgotPasswordHandler)
+	router.With(deps.rateLimit("verify_email")).Post("/api/auth/verify-email", verifyEmailHandler)
+	router.With(deps.rateLimit("verify_email_resend")).Post("/api/auth/verify-email/resend", resendVerificationHandler)
+	router.With(deps.rateLimit("refresh_tokens")).Post("/api/auth/refresh-tokens", refreshTokensHandler)
+	router.Get("/.well-known/jwks.json", jwksHandler)
+	router.With(deps.rateLimit("oauth_token")).Post("/oauth/token", oauthTokenHandler)
+	router.With(deps.rateLimit("oauth_introspect")).Post("/oauth/introspect", oauthIntrospectHandler)
+	router.With(deps.rateLimit("oauth_revoke")).Post("/oauth/revoke", oauthRevokeHandler)
+	router.Get("/.well-known/openid-configuration", openIdConfigurationHandler)
+	router.Get("/userinfo", userInfoHandler)
+	router.Post("/userinfo", userInfoHandler)
+	router.Get("/oauth/authorize", oauthAuthorizeHandler)
+	router.HandleFunc("/api/auth/verify", verifyHandler)
+	router.Get("/api/auth/openapi.json", openApiDocumentHandler)
+
+	router.Group(func(r chi.Router) {
+		r.Use(authentication.New(log, deps.authenticator))
+
+		r.Group(func(r chi.Router) {
+			r.Use(authentication.RequireUser(log))
+
+			r.Post("/oauth/authorize", oauthConsentHandler)
+			r.Post("/api/auth/logout", logoutHandler)
+			r.Post("/api/auth/logout-all", logoutAllHandler)
+			r.Get("/api/auth/sessions", sessionsHandler)
+			r.Delete("/api/auth/sessions/{session_id}", revokeSessionHandler)
+			r.Post("/api/auth/mfa/totp/enroll", totpEnrollHandler)
+			r.Post("/api/auth/mfa/totp/confirm", totpConfirmHandler)
+			r.With(deps.rateLimit("verify_phone_send")).Post("/api/auth/verify-phone/send", sendPhoneCodeHandler)
+			r.With(deps.rateLimit("verify_phone")).Post("/api/auth/verify-phone", verifyPhoneHandler)
+			r.With(authorization.New(log, authorization.None(), models.PermissionUsersReadSelf)).Get("/api/auth/me", meHandler)
+			r.With(authorization.New(log, authorization.None(), models.PermissionUsersUpdateSelf)).Patch("/api/auth/me", updateMeHandler)
+		})
+
+		r.With(authorization.New(log, authorization.FromURLParam(user.ParameterUserIdName), models.PermissionUsersReadSelf, models.PermissionUsersReadAny)).Get("/api/auth/users/{user_id}", userHandler)
+		r.With(authorization.New(log, authorization.FromBody("user_id"), models.PermissionUsersUpdateSelf, models.PermissionUsersUpdateAny)).Put("/api/auth/update-user", updateUserHandler)
+		r.With(authorization.New(log, authorization.FromBody("user_id"), models.PermissionUsersDeleteSelf, models.PermissionUsersDeleteAny)).Put("/api/auth/delete-user", deleteUserHandler)
+		r.With(authorization.New(log, authorization.FromBody("user_id"), models.PermissionUsersRestoreSelf, models.PermissionUsersRestoreAny)).Put("/api/auth/restore-user", restoreUserHandler)
+	})
+
+	router.Group(func(r chi.Router) {
+		r.Use(authentication.New(log, deps.authenticator))
+
+		r.With(authorization.New(log, authorization.None(), models.PermissionUsersUnlockAny)).Post("/api/auth/admin/unlock-user", unlockUserHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionEmployersModerateAny)).Get("/api/auth/admin/employers/pending", pendingEmployersHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionEmployersModerateAny)).Post("/api/auth/admin/employers/{user_id}/approve", approveEmployerHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionEmployersModerateAny)).Post("/api/auth/admin/employers/{user_id}/reject", rejectEmployerHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionOAuthClientsManageAny)).Post("/api/auth/admin/oauth/clients", registerOAuthClientHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionOAuthClientsManageAny)).Get("/api/auth/admin/oauth/clients", oauthClientsHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionServiceAccountsManageAny)).Post("/api/auth/admin/service-accounts", registerServiceAccountHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionServiceAccountsManageAny)).Put("/api/auth/admin/service-accounts/{client_id}/permissions", serviceAccountPermissionsHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionPermissionsManageAny)).Get("/api/auth/admin/permissions", permissionsHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionPermissionsManageAny)).Get("/api/auth/admin/roles/{role}/permissions", rolePermissionsHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionPermissionsManageAny)).Put("/api/auth/admin/roles/{role}/permissions/{permission}", grantPermissionHandler)
+		r.With(authorization.New(log, authorization.None(), models.PermissionPermissionsManageAny)).Delete("/api/auth/admin/roles/{role}/permissions/{permission}", revokePermissionHandler)
+	})
+
+	return router
+}
+
+func setupRateLimit(cfg config.RateLimit, store ratelimit.Store) (ratelimitMiddleware.Limiter, map[string][]ratelimitMiddleware.Policy, error) {
+	var limiter ratelimitMiddleware.Limiter
+
+	switch cfg.RateLimitBackend {
+	case rateLimitMemory:
+		limiter = ratelimit.NewMemory()
+	case rateLimitPostgres:
+		limiter = ratelimit.NewPostgres(store)
+	default:
+		return nil, nil, fmt.Errorf("unknown rate limit backend: %s", cfg.RateLimitBackend)
+	}
+
+	routes := make(map[string][]ratelimitMiddleware.Policy, len(cfg.RateLimitRoutes))
+	for route, routePolicies := range cfg.RateLimitRoutes {
+		policies := make([]ratelimitMiddleware.Policy, 0, len(routePolicies))
+		for _, routePolicy := range routePolicies {
+			policy, err := ratelimitMiddleware.NewPolicy(route, routePolicy.Key, routePolicy.Requests, routePolicy.Period)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			policies = append(policies, policy)
+		}
+
+		routes[route] = policies
+	}
+
+	return limiter, routes, nil
+}
+
+func routeRateLimit(log *slog.Logger, limiter ratelimitMiddleware.Limiter, failOpen bool, routes map[string][]ratelimitMiddleware.Policy) func(route string) func(http.Handler) http.Handler {
+	middlewares := make(map[string]func(http.Handler) http.Handler, len(routes))
+	for route, policies := range routes {
+		middlewares[route] = ratelimitMiddleware.New(log, limiter, failOpen, policies...)
+	}
+
+	return func(route string) func(http.Handler) http.Handler {
+		if rateLimit, ok := middlewares[route]; ok {
+			return rateLimit
+		}
+
+		return func(next http.Handler) http.Handler {
+			return next
+		}
+	}
+}
+
+func setupForwardAuth(cfg config.ForwardAuth) (*authorization.Policy, error) {
+	rules := make([]authorization.Rule, 0, len(cfg.ForwardAuthRules))
+	for _, configRule := range cfg.ForwardAuthRules {
+		rule, err := authorization.NewRule(configRule.Path, configRule.Methods, configRule.Roles, configRule.Permissions, configRule.Scopes, configRule.Public)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
 
-	// TODO: сделать подтверждение телефона и почты
+	return authorization.NewPolicy(rules, cfg.ForwardAuthUnmatched)
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"auth/internal/config"
+	"auth/internal/http-server/apispec"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var allMethods = []string{
+	http.MethodConnect,
+	http.MethodDelete,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPatch,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodTrace,
+}
+
+func noRateLimit(route string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return next
+	}
+}
+
+func TestRoutesMatchOpenAPI(t *testing.T) {
+	router := setupRouter(slogdiscard.NewDiscardLogger(), &config.Config{}, routerDeps{rateLimit: noRateLimit})
+
+	routes := make(map[string]map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, apispec.Prefix) {
+			return nil
+		}
+
+		if routes[route] == nil {
+			routes[route] = make(map[string]bool)
+		}
+		routes[route][method] = true
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := apispec.New().Document().Paths
+
+	for route, methods := range routes {
+		if anyMethod(methods) {
+			if len(documented[route]) == 0 {
+				t.Errorf("route %s is not documented", route)
+			}
+
+			continue
+		}
+
+		for method := range methods {
+			if documented[route][strings.ToLower(method)] == nil {
+				t.Errorf("route %s %s is not documented", method, route)
+			}
+		}
+	}
+
+	for path, operations := range documented {
+		for method := range operations {
+			if !routes[path][strings.ToUpper(method)] {
+				t.Errorf("documented operation %s %s has no route", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func anyMethod(methods map[string]bool) bool {
+	for _, method := range allMethods {
+		if !methods[method] {
+			return false
+		}
+	}
+
+	return true
+}
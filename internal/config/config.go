@@ -4,28 +4,70 @@ import (
 	"flag"
 	"fmt"
 	"github.com/ilyakaznacheev/cleanenv"
+	"log/slog"
 	"os"
 	"time"
 )
 
+const redacted = "[REDACTED]"
+
 type Config struct {
-	Env         string `yaml:"env" env-required:"true"`
-	Migrations  `yaml:"migrations"`
-	Auth        `yaml:"auth"`
-	HttpServer  `yaml:"http_server"`
-	EmailSender `yaml:"email_sender"`
+	Env               string `yaml:"env" env-required:"true"`
+	Migrations        `yaml:"migrations"`
+	Auth              `yaml:"auth"`
+	HttpServer        `yaml:"http_server"`
+	GrpcServer        `yaml:"grpc_server"`
+	Keys              `yaml:"keys"`
+	Mfa               `yaml:"mfa"`
+	EmailSender       `yaml:"email_sender"`
+	EmailVerification `yaml:"email_verification"`
+	Sms               `yaml:"sms"`
+	PhoneVerification `yaml:"phone_verification"`
+	RateLimit         `yaml:"rate_limit"`
+	Rbac              `yaml:"rbac"`
+	OAuth             `yaml:"oauth"`
+	ForwardAuth       `yaml:"forward_auth"`
 }
 
 type HttpServer struct {
 	Address     string        `yaml:"address" env-required:"true"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
-	SecretKey   string        `yaml:"secret_key" env-required:"true"`
+
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+type GrpcServer struct {
+	GrpcAddress string `yaml:"address" env-default:"localhost:9001"`
+}
+
+type Keys struct {
+	Algorithm        string        `yaml:"algorithm" env-default:"RS256"`
+	RotationInterval time.Duration `yaml:"rotation_interval" env-default:"720h"`
+	KeyTtl           time.Duration `yaml:"key_ttl" env-default:"1440h"`
+	RefreshInterval  time.Duration `yaml:"refresh_interval" env-default:"1m"`
+	PublishDelay     time.Duration `yaml:"publish_delay" env-default:"10m"`
+	EncryptionKey    string        `yaml:"encryption_key" env:"KEYS_ENCRYPTION_KEY" env-required:"true"`
 }
 
 type Auth struct {
-	LinkTtl  time.Duration `yaml:"link_ttl" env-required:"true"`
-	TokenTtl time.Duration `yaml:"token_ttl" env-required:"true"`
+	LinkTtl         time.Duration `yaml:"link_ttl" env-required:"true"`
+	TokenTtl        time.Duration `yaml:"token_ttl" env-required:"true"`
+	RefreshTokenTtl time.Duration `yaml:"refresh_token_ttl" env-required:"true"`
+
+	SessionTouchInterval time.Duration `yaml:"session_touch_interval" env-default:"1m"`
+
+	MaxFailedAttempts   int           `yaml:"max_failed_attempts" env-default:"5"`
+	IpMaxFailedAttempts int           `yaml:"ip_max_failed_attempts" env-default:"50"`
+	FailureWindow       time.Duration `yaml:"failure_window" env-default:"15m"`
+	LockoutDuration     time.Duration `yaml:"lockout_duration" env-default:"15m"`
+	ProgressiveDelay    time.Duration `yaml:"progressive_delay" env-default:"1s"`
+}
+
+type Mfa struct {
+	TotpIssuer         string        `yaml:"totp_issuer" env-default:"vacancy-tomsk"`
+	MfaChallengeTtl    time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+	RecoveryCodesCount int           `yaml:"recovery_codes_count" env-default:"10"`
 }
 
 type EmailSender struct {
@@ -34,6 +76,64 @@ type EmailSender struct {
 	Email  string `yaml:"email" env-required:"true"`
 }
 
+type EmailVerification struct {
+	EmailVerificationUrl  string        `yaml:"url" env-default:"http://vacancy/verify-email"`
+	EmailVerificationTtl  time.Duration `yaml:"ttl" env-default:"24h"`
+	EmailResendInterval   time.Duration `yaml:"resend_interval" env-default:"1m"`
+	EmailRequiredForLogin bool          `yaml:"required_for_login" env-default:"false"`
+	EmailRequiredForRoles []string      `yaml:"required_for_roles"`
+}
+
+type Sms struct {
+	SmsProvider string `yaml:"provider" env-default:"log"`
+	SmsFilePath string `yaml:"file_path" env-default:"./sms.log"`
+}
+
+type PhoneVerification struct {
+	PhoneCodeTtl         time.Duration `yaml:"code_ttl" env-default:"5m"`
+	PhoneResendInterval  time.Duration `yaml:"resend_interval" env-default:"1m"`
+	PhoneCodeMaxAttempts int           `yaml:"max_attempts" env-default:"5"`
+	PhoneAttemptsWindow  time.Duration `yaml:"attempts_window" env-default:"1h"`
+}
+
+type RateLimit struct {
+	RateLimitBackend  string                       `yaml:"backend" env-default:"memory"`
+	RateLimitFailOpen bool                         `yaml:"fail_open" env-default:"false"`
+	RateLimitRoutes   map[string][]RateLimitPolicy `yaml:"routes"`
+}
+
+type RateLimitPolicy struct {
+	Key      string        `yaml:"key"`
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+}
+
+type Rbac struct {
+	PermissionsCacheTtl time.Duration `yaml:"permissions_cache_ttl" env-default:"1m"`
+}
+
+type OAuth struct {
+	OAuthIssuer     string        `yaml:"issuer" env-default:"http://localhost:8082"`
+	OAuthCodeTtl    time.Duration `yaml:"code_ttl" env-default:"5m"`
+	OAuthLoginUrl   string        `yaml:"login_url" env-default:"http://vacancy/login"`
+	OAuthConsentUrl string        `yaml:"consent_url" env-default:"http://vacancy/oauth/consent"`
+}
+
+type ForwardAuth struct {
+	ForwardAuthCookieName string            `yaml:"cookie_name" env-default:"access_token"`
+	ForwardAuthUnmatched  string            `yaml:"unmatched" env-default:"deny"`
+	ForwardAuthRules      []ForwardAuthRule `yaml:"rules"`
+}
+
+type ForwardAuthRule struct {
+	Path        string   `yaml:"path"`
+	Methods     []string `yaml:"methods"`
+	Roles       []string `yaml:"roles"`
+	Permissions []string `yaml:"permissions"`
+	Scopes      []string `yaml:"scopes"`
+	Public      bool     `yaml:"public"`
+}
+
 type Migrations struct {
 	Path string `yaml:"path"`
 }
@@ -58,6 +158,21 @@ func MustLoad() *Config {
 	return &cfg
 }
 
+func (c Config) LogValue() slog.Value {
+	type plain Config
+
+	safe := plain(c)
+	if safe.EncryptionKey != "" {
+		safe.EncryptionKey = redacted
+	}
+
+	if safe.ApiKey != "" {
+		safe.ApiKey = redacted
+	}
+
+	return slog.AnyValue(safe)
+}
+
 func fetchConfigPath() string {
 	var res string
 
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValueRedactsSecrets(t *testing.T) {
+	cfg := &Config{
+		Env:         "local",
+		Keys:        Keys{Algorithm: "RS256", EncryptionKey: "super-secret-encryption-key"},
+		EmailSender: EmailSender{ApiKey: "super-secret-api-key", Email: "noreply@example.com"},
+	}
+
+	for name, handler := range map[string]func(*bytes.Buffer) slog.Handler{
+		"json": func(buf *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(buf, nil) },
+		"text": func(buf *bytes.Buffer) slog.Handler { return slog.NewTextHandler(buf, nil) },
+	} {
+		var buf bytes.Buffer
+		slog.New(handler(&buf)).Info("start", slog.Any("config", cfg))
+
+		out := buf.String()
+		if strings.Contains(out, "super-secret") {
+			t.Errorf("%s: log contains a secret: %s", name, out)
+		}
+
+		if !strings.Contains(out, redacted) || !strings.Contains(out, "RS256") || !strings.Contains(out, "noreply@example.com") {
+			t.Errorf("%s: log = %s, want redacted secrets and other fields", name, out)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type CompanyStatus string
+
+const (
+	CompanyPending  CompanyStatus = "pending"
+	CompanyApproved CompanyStatus = "approved"
+	CompanyRejected CompanyStatus = "rejected"
+)
+
+type Company struct {
+	Id              int64
+	UserId          int64
+	Name            string
+	Inn             string
+	Ogrn            string
+	LegalAddress    string
+	ContactPerson   string
+	Status          CompanyStatus
+	RejectionReason string
+	CreatedAt       time.Time
+	ReviewedAt      *time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type EmailVerification struct {
+	Id         int64
+	UserId     int64
+	Expiration time.Time
+	CreatedAt  time.Time
+}
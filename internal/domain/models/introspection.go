@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	TokenTypeAccess  = "access_token"
+	TokenTypeRefresh = "refresh_token"
+)
+
+type Introspection struct {
+	Active    bool
+	TokenType string
+	Id        string
+	Subject   string
+	UserId    int64
+	Role      UserRole
+	ClientId  string
+	Scope     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+type OAuthClient struct {
+	Id             string
+	SecretHash     []byte
+	Name           string
+	RedirectUris   []string
+	Scopes         []string
+	Public         bool
+	ServiceAccount bool
+	Permissions    []string
+	CreatedAt      time.Time
+}
+
+type OAuthConsent struct {
+	UserId    int64
+	ClientId  string
+	Scopes    []string
+	CreatedAt time.Time
+}
+
+type AuthorizationCode struct {
+	CodeHash            string
+	ClientId            string
+	UserId              int64
+	RedirectUri         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+	Expiration          time.Time
+	Used                bool
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+const (
+	PermissionUsersReadSelf            = "users:read:self"
+	PermissionUsersReadAny             = "users:read:any"
+	PermissionUsersUpdateSelf          = "users:update:self"
+	PermissionUsersUpdateAny           = "users:update:any"
+	PermissionUsersDeleteSelf          = "users:delete:self"
+	PermissionUsersDeleteAny           = "users:delete:any"
+	PermissionUsersRestoreSelf         = "users:restore:self"
+	PermissionUsersRestoreAny          = "users:restore:any"
+	PermissionUsersUnlockAny           = "users:unlock:any"
+	PermissionEmployersModerateAny     = "employers:moderate:any"
+	PermissionPermissionsManageAny     = "permissions:manage:any"
+	PermissionOAuthClientsManageAny    = "oauth_clients:manage:any"
+	PermissionServiceAccountsManageAny = "service_accounts:manage:any"
+	PermissionTokensIntrospectAny      = "tokens:introspect:any"
+)
+
+type Permission struct {
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
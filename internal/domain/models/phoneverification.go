@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type PhoneVerification struct {
+	UserId          int64
+	CodeHash        string
+	Expiration      time.Time
+	Attempts        int
+	WindowStartedAt time.Time
+	CreatedAt       time.Time
+}
@@ -0,0 +1,26 @@
+package models
+
+type Principal struct {
+	UserId         int64
+	Role           UserRole
+	SessionId      string
+	EmployerStatus CompanyStatus
+	ClientId       string
+	Scope          string
+	ServiceAccount bool
+	Permissions    []string
+}
+
+func (p *Principal) HasPermission(permission string) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Principal) Delegated() bool {
+	return p.ClientId != "" && !p.ServiceAccount
+}
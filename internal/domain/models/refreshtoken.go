@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	Id         int64
+	TokenHash  string
+	UserId     int64
+	FamilyId   string
+	ClientId   string
+	Scope      string
+	Expiration time.Time
+	Used       bool
+	Revoked    bool
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	IdToken      string
+	ExpiresIn    time.Duration
+	Scope        string
+}
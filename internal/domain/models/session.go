@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Session struct {
+	Id         string
+	UserId     int64
+	Device     string
+	Ip         string
+	Revoked    bool
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type SigningKey struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type TotpSecret struct {
+	UserId       int64
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+}
+
+type MfaChallenge struct {
+	Id        string
+	UserId    int64
+	ExpiresAt time.Time
+}
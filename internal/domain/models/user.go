@@ -1,10 +1,32 @@
 package models
 
+import "time"
+
 type User struct {
-	Id         int64
-	PassHash   []byte
-	Role       UserRole
-	RoleString string
-	Email      string
-	Deleted    bool
+	Id              int64
+	FullName        string
+	PassHash        []byte
+	Role            UserRole
+	RoleString      string
+	Email           string
+	EmailVerifiedAt *time.Time
+	Phone           string
+	PhoneVerifiedAt *time.Time
+	PendingEmail    string
+	PendingPhone    string
+	EmployerStatus  CompanyStatus
+	Deleted         bool
+	Version         int64
+}
+
+type ProfileUpdate struct {
+	FullName *string
+	Email    *string
+	Phone    *string
+}
+
+type ProfileChanges struct {
+	FullName bool
+	Email    bool
+	Phone    bool
 }
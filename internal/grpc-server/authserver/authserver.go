@@ -0,0 +1,219 @@
+package authserver
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/handlers/url/login"
+	"auth/internal/http-server/handlers/url/refreshtokens"
+	"auth/internal/http-server/handlers/url/register"
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	loginService "auth/internal/services/login"
+	authv1 "auth/pkg/api/auth/v1"
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"log/slog"
+	"net"
+)
+
+type RegistrationService interface {
+	RegisterUser(fullName, password, phone, email string, userRole string) (int64, error)
+}
+
+type LoginService interface {
+	Login(contactInfo, password, device, ip string) (*loginService.Result, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+}
+
+type EmailVerifier interface {
+	Send(user *models.User) error
+}
+
+type Server struct {
+	authv1.UnimplementedAuthServiceServer
+	log                 *slog.Logger
+	registrationService RegistrationService
+	loginService        LoginService
+	emailVerifier       EmailVerifier
+	authenticator       authentication.Authenticator
+}
+
+func Register(gRPC *grpc.Server, log *slog.Logger, registrationService RegistrationService, loginService LoginService, emailVerifier EmailVerifier, authenticator authentication.Authenticator) {
+	authv1.RegisterAuthServiceServer(gRPC, &Server{
+		log:                 log,
+		registrationService: registrationService,
+		loginService:        loginService,
+		emailVerifier:       emailVerifier,
+		authenticator:       authenticator,
+	})
+}
+
+func (s *Server) Register(ctx context.Context, in *authv1.RegisterRequest) (*authv1.RegisterResponse, error) {
+	const op = "grpc.authserver.Register"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	log.Info("registering user")
+
+	req := register.Request{
+		FullName: in.GetFullName(),
+		Password: in.GetPassword(),
+		Phone:    in.GetPhone(),
+		Email:    in.GetEmail(),
+		RoleId:   in.GetUserRole(),
+	}
+
+	if err := validation.Struct(req); err != nil {
+		log.Error("invalid request", sl.Err(err))
+
+		return nil, rpcstatus.ValidationError(err)
+	}
+
+	userId, err := s.registrationService.RegisterUser(req.FullName, req.Password, req.Phone, req.Email, req.RoleId)
+	if err != nil {
+		return nil, rpcstatus.FromProblem(register.Problem(log, err))
+	}
+
+	log.Info("user added")
+
+	if err := s.emailVerifier.Send(&models.User{Id: userId, Email: req.Email}); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+	}
+
+	return &authv1.RegisterResponse{UserId: userId}, nil
+}
+
+func (s *Server) Login(ctx context.Context, in *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	const op = "grpc.authserver.Login"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	req := login.Request{
+		ContactInfo: in.GetContactInfo(),
+		Password:    in.GetPassword(),
+	}
+
+	if err := validation.Struct(req); err != nil {
+		log.Error("invalid request", sl.Err(err))
+
+		return nil, rpcstatus.ValidationError(err)
+	}
+
+	result, err := s.loginService.Login(req.ContactInfo, req.Password, userAgent(ctx), clientIp(ctx))
+	if err != nil {
+		return nil, rpcstatus.FromProblem(login.Problem(log, err))
+	}
+
+	if result.MfaToken != "" {
+		log.Info("mfa required")
+
+		return &authv1.LoginResponse{MfaRequired: true, MfaToken: result.MfaToken}, nil
+	}
+
+	log.Info("user logged in successfully")
+
+	return &authv1.LoginResponse{AccessToken: result.Tokens.AccessToken, RefreshToken: result.Tokens.RefreshToken}, nil
+}
+
+func (s *Server) RefreshTokens(ctx context.Context, in *authv1.RefreshTokensRequest) (*authv1.RefreshTokensResponse, error) {
+	const op = "grpc.authserver.RefreshTokens"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	req := refreshtokens.Request{RefreshToken: in.GetRefreshToken()}
+
+	if err := validation.Struct(req); err != nil {
+		log.Error("invalid request", sl.Err(err))
+
+		return nil, rpcstatus.ValidationError(err)
+	}
+
+	pair, err := s.loginService.Refresh(req.RefreshToken)
+	if err != nil {
+		return nil, rpcstatus.FromProblem(refreshtokens.Problem(log, err))
+	}
+
+	return &authv1.RefreshTokensResponse{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+func (s *Server) ValidateToken(ctx context.Context, in *authv1.ValidateTokenRequest) (*authv1.ValidateTokenResponse, error) {
+	const op = "grpc.authserver.ValidateToken"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	if in.GetToken() == "" {
+		log.Error("invalid request", sl.Err(authentication.ErrTokenNotFound))
+
+		return nil, rpcstatus.Error(codes.InvalidArgument, resp.CodeInvalidRequest, "token is required")
+	}
+
+	principal, err := s.authenticator.Authenticate(in.GetToken())
+	if err != nil {
+		log.Info("invalid token", sl.Err(err))
+
+		return nil, rpcstatus.Error(codes.Unauthenticated, resp.CodeInvalidToken, "invalid token")
+	}
+
+	return &authv1.ValidateTokenResponse{
+		UserId:         principal.UserId,
+		UserRole:       enums.RoleConvertToString(principal.Role),
+		SessionId:      principal.SessionId,
+		EmployerStatus: string(principal.EmployerStatus),
+		ClientId:       principal.ClientId,
+		Scope:          principal.Scope,
+		ServiceAccount: principal.ServiceAccount,
+		Permissions:    principal.Permissions,
+	}, nil
+}
+
+func clientIp(ctx context.Context) string {
+	if ip := clientip.FromContext(ctx); ip != "" {
+		return ip
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+func userAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("user-agent")
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,247 @@
+package authserver
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/lockout"
+	loginService "auth/internal/services/login"
+	"auth/internal/services/mfa"
+	"auth/internal/storage"
+	authv1 "auth/pkg/api/auth/v1"
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+var errInvalidPassword = errors.New("invalid password")
+
+type fakeUsers struct {
+	users map[string]*models.User
+}
+
+func (u *fakeUsers) RegisterUser(fullName, password, phone, email string, userRole string) (int64, error) {
+	return 0, storage.ErrUserExist
+}
+
+func (u *fakeUsers) UserByContactInfo(contactInfo string) (*models.User, error) {
+	user, ok := u.users[contactInfo]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (u *fakeUsers) UserByUserId(userId int64) (*models.User, error) {
+	return nil, storage.ErrUserNotFound
+}
+
+func (u *fakeUsers) CheckContactInfo(user *models.User, contactInfo string) error {
+	return nil
+}
+
+func (u *fakeUsers) Authorize(user *models.User, password string) error {
+	if string(user.PassHash) != password {
+		return errInvalidPassword
+	}
+
+	return nil
+}
+
+type fakeTokens struct{}
+
+func (fakeTokens) Issue(user *models.User, device, ip string) (*models.TokenPair, error) {
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (fakeTokens) Refresh(refreshToken string) (*models.TokenPair, error) {
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+type fakeMfa struct {
+	enabled bool
+}
+
+func (m fakeMfa) Enabled(userId int64) (bool, error) {
+	return m.enabled, nil
+}
+
+func (fakeMfa) Challenge(userId int64) (string, error) {
+	return "mfa", nil
+}
+
+func (fakeMfa) ResolveChallenge(mfaToken string) (*models.MfaChallenge, error) {
+	return nil, mfa.ErrInvalidMfaToken
+}
+
+func (fakeMfa) RedeemChallenge(challenge *models.MfaChallenge) error {
+	return mfa.ErrInvalidMfaToken
+}
+
+func (fakeMfa) VerifyCode(userId int64, code string) error {
+	return mfa.ErrInvalidCode
+}
+
+func (fakeMfa) VerifyRecoveryCode(userId int64, recoveryCode string) error {
+	return mfa.ErrInvalidCode
+}
+
+type fakeEmailVerifier struct{}
+
+func (fakeEmailVerifier) Send(user *models.User) error {
+	return nil
+}
+
+func (fakeEmailVerifier) CheckPolicy(user *models.User) error {
+	return nil
+}
+
+type fakeGuard struct {
+	failures  int
+	successes int
+	locked    bool
+}
+
+func (g *fakeGuard) Check(userId int64, ip string) error {
+	if g.locked {
+		return &lockout.LockedError{Err: lockout.ErrTooManyAttempts, RetryAfter: time.Minute}
+	}
+
+	return nil
+}
+
+func (g *fakeGuard) RegisterFailure(userId int64, ip string) error {
+	g.failures++
+
+	return nil
+}
+
+func (g *fakeGuard) RegisterSuccess(userId int64) error {
+	g.successes++
+
+	return nil
+}
+
+func newServer(mfaService fakeMfa, guard *fakeGuard) *Server {
+	log := slogdiscard.NewDiscardLogger()
+	users := &fakeUsers{users: map[string]*models.User{
+		"user@example.com": {Id: 1, Email: "user@example.com", PassHash: []byte("secret")},
+	}}
+
+	return &Server{
+		log:                 log,
+		registrationService: users,
+		loginService:        loginService.New(log, users, fakeTokens{}, mfaService, fakeEmailVerifier{}, guard),
+		emailVerifier:       fakeEmailVerifier{},
+	}
+}
+
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+
+	return ""
+}
+
+func TestLoginHidesUnknownContacts(t *testing.T) {
+	guard := &fakeGuard{}
+	server := newServer(fakeMfa{}, guard)
+
+	tests := []struct {
+		name        string
+		contactInfo string
+		password    string
+	}{
+		{name: "unknown contact", contactInfo: "other@example.com", password: "secret"},
+		{name: "wrong password", contactInfo: "user@example.com", password: "wrong"},
+	}
+
+	for _, tt := range tests {
+		_, err := server.Login(context.Background(), &authv1.LoginRequest{ContactInfo: tt.contactInfo, Password: tt.password})
+
+		if got := status.Code(err); got != codes.Unauthenticated {
+			t.Errorf("%s: code = %s, want %s", tt.name, got, codes.Unauthenticated)
+		}
+
+		if got := errorReason(err); got != resp.CodeInvalidCredentials {
+			t.Errorf("%s: reason = %q, want %q", tt.name, got, resp.CodeInvalidCredentials)
+		}
+	}
+
+	if guard.failures != 2 {
+		t.Errorf("failures = %d, want 2", guard.failures)
+	}
+}
+
+func TestLoginIssuesTokens(t *testing.T) {
+	server := newServer(fakeMfa{}, &fakeGuard{})
+
+	res, err := server.Login(context.Background(), &authv1.LoginRequest{ContactInfo: "user@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if res.GetAccessToken() != "access" || res.GetRefreshToken() != "refresh" {
+		t.Errorf("Login() = %+v, want issued tokens", res)
+	}
+}
+
+func TestLoginDefersSuccessUntilMfa(t *testing.T) {
+	guard := &fakeGuard{}
+	server := newServer(fakeMfa{enabled: true}, guard)
+
+	res, err := server.Login(context.Background(), &authv1.LoginRequest{ContactInfo: "user@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if !res.GetMfaRequired() || res.GetMfaToken() != "mfa" || res.GetAccessToken() != "" {
+		t.Errorf("Login() = %+v, want mfa challenge", res)
+	}
+
+	if guard.successes != 0 {
+		t.Errorf("successes = %d, want 0", guard.successes)
+	}
+}
+
+func TestLoginRespectsLockout(t *testing.T) {
+	server := newServer(fakeMfa{}, &fakeGuard{locked: true})
+
+	_, err := server.Login(context.Background(), &authv1.LoginRequest{ContactInfo: "user@example.com", Password: "secret"})
+
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("code = %s, want %s", got, codes.ResourceExhausted)
+	}
+
+	if got := errorReason(err); got != resp.CodeTooManyAttempts {
+		t.Errorf("reason = %q, want %q", got, resp.CodeTooManyAttempts)
+	}
+}
+
+func TestRegisterMapsExistingUser(t *testing.T) {
+	server := newServer(fakeMfa{}, &fakeGuard{})
+
+	_, err := server.Register(context.Background(), &authv1.RegisterRequest{
+		FullName: "User",
+		Password: "secret",
+		Phone:    "+79990000000",
+		Email:    "user@example.com",
+		UserRole: "applicant",
+	})
+
+	if got := status.Code(err); got != codes.AlreadyExists {
+		t.Errorf("code = %s, want %s", got, codes.AlreadyExists)
+	}
+
+	if got := errorReason(err); got != resp.CodeUserExists {
+		t.Errorf("reason = %q, want %q", got, resp.CodeUserExists)
+	}
+}
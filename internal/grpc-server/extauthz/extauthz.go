@@ -0,0 +1,151 @@
+package extauthz
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	"context"
+	"errors"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const CheckFullMethodName = "/envoy.service.auth.v3.Authorization/Check"
+
+const (
+	HeaderUserId   = "x-user-id"
+	HeaderUserRole = "x-user-role"
+	HeaderClientId = "x-client-id"
+)
+
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+	log           *slog.Logger
+	authenticator authentication.Authenticator
+	cookieName    string
+	policy        *authorization.Policy
+}
+
+func Register(gRPC *grpc.Server, log *slog.Logger, authenticator authentication.Authenticator, cookieName string, policy *authorization.Policy) {
+	authv3.RegisterAuthorizationServer(gRPC, &Server{
+		log:           log,
+		authenticator: authenticator,
+		cookieName:    cookieName,
+		policy:        policy,
+	})
+}
+
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	const op = "grpc.extauthz.Check"
+
+	httpRequest := req.GetAttributes().GetRequest().GetHttp()
+
+	method := httpRequest.GetMethod()
+	path, ok := requestPath(httpRequest.GetPath())
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", httpRequest.GetId()),
+		slog.String("method", method),
+		slog.String("path", path),
+	)
+
+	if !ok {
+		log.Info("original request path is missing or invalid", sl.Err(authorization.ErrInvalidPath))
+
+		return denied(codes.InvalidArgument, typev3.StatusCode_BadRequest, ""), nil
+	}
+
+	r := &http.Request{Header: make(http.Header, len(httpRequest.GetHeaders()))}
+	for name, value := range httpRequest.GetHeaders() {
+		r.Header.Set(name, value)
+	}
+
+	principal, err := authentication.AuthenticateWithCookie(r, s.authenticator, s.cookieName)
+	if err != nil {
+		log.Debug("request is not authenticated", sl.Err(err))
+
+		principal = nil
+	}
+
+	principal, err = s.policy.Verify(method, path, principal)
+	if errors.Is(err, authorization.ErrUnauthenticated) {
+		log.Info("authentication required")
+
+		return denied(codes.Unauthenticated, typev3.StatusCode_Unauthorized, `Bearer error="invalid_token"`), nil
+	}
+
+	if err != nil {
+		log.Info("access not allowed", sl.Err(err))
+
+		return denied(codes.PermissionDenied, typev3.StatusCode_Forbidden, ""), nil
+	}
+
+	return allowed(principal), nil
+}
+
+func allowed(principal *models.Principal) *authv3.CheckResponse {
+	okResponse := &authv3.OkHttpResponse{
+		HeadersToRemove: []string{HeaderUserId, HeaderUserRole, HeaderClientId},
+	}
+
+	if principal != nil {
+		if principal.UserId != 0 {
+			okResponse.Headers = append(okResponse.Headers, header(HeaderUserId, strconv.FormatInt(principal.UserId, 10)))
+		}
+
+		if role := enums.RoleConvertToString(principal.Role); role != "" {
+			okResponse.Headers = append(okResponse.Headers, header(HeaderUserRole, role))
+		}
+
+		if principal.ClientId != "" {
+			okResponse.Headers = append(okResponse.Headers, header(HeaderClientId, principal.ClientId))
+		}
+	}
+
+	return &authv3.CheckResponse{
+		Status:       &status.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: okResponse},
+	}
+}
+
+func denied(code codes.Code, httpCode typev3.StatusCode, challenge string) *authv3.CheckResponse {
+	deniedResponse := &authv3.DeniedHttpResponse{
+		Status: &typev3.HttpStatus{Code: httpCode},
+	}
+
+	if challenge != "" {
+		deniedResponse.Headers = append(deniedResponse.Headers, header("www-authenticate", challenge))
+	}
+
+	return &authv3.CheckResponse{
+		Status:       &status.Status{Code: int32(code)},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: deniedResponse},
+	}
+}
+
+func header(key string, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:       &corev3.HeaderValue{Key: key, Value: value},
+		AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
+
+func requestPath(uri string) (string, bool) {
+	parsed, err := url.ParseRequestURI(uri)
+	if err != nil || parsed.Path == "" {
+		return "", false
+	}
+
+	return parsed.Path, true
+}
@@ -0,0 +1,152 @@
+package extauthz
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"context"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc/codes"
+	"testing"
+)
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(token string) (*models.Principal, error) {
+	switch token {
+	case "employer":
+		return &models.Principal{UserId: 2, Role: models.Employer}, nil
+	case "delegated":
+		return &models.Principal{UserId: 2, Role: models.Employer, ClientId: "partner", Scope: "openid vacancies:read"}, nil
+	default:
+		return nil, authentication.ErrTokenNotFound
+	}
+}
+
+func newServer(t *testing.T) *Server {
+	t.Helper()
+
+	public, err := authorization.NewRule("/api/vacancies/**", []string{"GET"}, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employers, err := authorization.NewRule("/api/vacancies/**", []string{"POST"}, []string{"employer"}, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partners, err := authorization.NewRule("/api/partner/**", []string{"GET"}, nil, nil, []string{"vacancies:read"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := authorization.NewPolicy([]authorization.Rule{public, employers, partners}, authorization.UnmatchedDeny)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Server{
+		log:           slogdiscard.NewDiscardLogger(),
+		authenticator: fakeAuthenticator{},
+		cookieName:    "access_token",
+		policy:        policy,
+	}
+}
+
+func checkRequest(method, path string, headers map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Method:  method,
+					Path:    path,
+					Headers: headers,
+				},
+			},
+		},
+	}
+}
+
+func responseHeaders(res *authv3.CheckResponse) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range res.GetOkResponse().GetHeaders() {
+		headers[header.GetHeader().GetKey()] = header.GetHeader().GetValue()
+	}
+
+	return headers
+}
+
+func TestCheck(t *testing.T) {
+	server := newServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		headers  map[string]string
+		code     codes.Code
+		httpCode typev3.StatusCode
+		userId   string
+	}{
+		{name: "missing path", method: "GET", code: codes.InvalidArgument, httpCode: typev3.StatusCode_BadRequest},
+		{name: "invalid path", method: "GET", path: "vacancies", code: codes.InvalidArgument, httpCode: typev3.StatusCode_BadRequest},
+		{name: "public", method: "GET", path: "/api/vacancies/1?page=2", code: codes.OK},
+		{name: "anonymous", method: "POST", path: "/api/vacancies", code: codes.Unauthenticated, httpCode: typev3.StatusCode_Unauthorized},
+		{name: "unmatched", method: "GET", path: "/api/internal", headers: map[string]string{"authorization": "Bearer employer"}, code: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden},
+		{name: "employer", method: "POST", path: "/api/vacancies", headers: map[string]string{"authorization": "Bearer employer"}, code: codes.OK, userId: "2"},
+		{name: "delegated on role rule", method: "POST", path: "/api/vacancies", headers: map[string]string{"authorization": "Bearer delegated"}, code: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden},
+		{name: "delegated on public rule", method: "GET", path: "/api/vacancies/1", headers: map[string]string{"authorization": "Bearer delegated"}, code: codes.OK},
+		{name: "delegated with rule scope", method: "GET", path: "/api/partner/vacancies", headers: map[string]string{"authorization": "Bearer delegated"}, code: codes.OK, userId: "2"},
+	}
+
+	for _, tt := range tests {
+		res, err := server.Check(context.Background(), checkRequest(tt.method, tt.path, tt.headers))
+		if err != nil {
+			t.Fatalf("%s: Check() error = %v", tt.name, err)
+		}
+
+		if got := codes.Code(res.GetStatus().GetCode()); got != tt.code {
+			t.Errorf("%s: code = %s, want %s", tt.name, got, tt.code)
+		}
+
+		if got := res.GetDeniedResponse().GetStatus().GetCode(); got != tt.httpCode {
+			t.Errorf("%s: http status = %s, want %s", tt.name, got, tt.httpCode)
+		}
+
+		headers := responseHeaders(res)
+		if got := headers[HeaderUserId]; got != tt.userId {
+			t.Errorf("%s: %s = %q, want %q", tt.name, HeaderUserId, got, tt.userId)
+		}
+
+		if _, ok := headers[HeaderUserRole]; ok && tt.userId == "" {
+			t.Errorf("%s: %s is set for a request without forwarded identity", tt.name, HeaderUserRole)
+		}
+	}
+}
+
+func TestCheckRemovesSpoofedHeaders(t *testing.T) {
+	server := newServer(t)
+
+	res, err := server.Check(context.Background(), checkRequest("GET", "/api/vacancies", map[string]string{HeaderUserId: "1"}))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	removed := make(map[string]bool)
+	for _, name := range res.GetOkResponse().GetHeadersToRemove() {
+		removed[name] = true
+	}
+
+	for _, name := range []string{HeaderUserId, HeaderUserRole, HeaderClientId} {
+		if !removed[name] {
+			t.Errorf("header %s is not removed", name)
+		}
+	}
+
+	if got, ok := responseHeaders(res)[HeaderUserId]; ok {
+		t.Errorf("%s = %q for anonymous request, want none", HeaderUserId, got)
+	}
+}
@@ -0,0 +1,58 @@
+package interceptors
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"auth/internal/lib/logger/sl"
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"log/slog"
+	"strings"
+)
+
+const MetadataAuthorization = "authorization"
+
+func Authentication(log *slog.Logger, authenticator authentication.Authenticator, publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "interceptors.Authentication"
+
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+		)
+
+		tokenString, ok := strings.CutPrefix(firstValue(ctx, MetadataAuthorization), authentication.BearerSchema)
+		if !ok || tokenString == "" {
+			log.Error("failed to authenticate", sl.Err(authentication.ErrTokenNotFound))
+
+			return nil, rpcstatus.Error(codes.Unauthenticated, resp.CodeUnauthorized, "failed to authentication")
+		}
+
+		principal, err := authenticator.Authenticate(tokenString)
+		if err != nil {
+			log.Error("failed to authenticate", sl.Err(err))
+
+			return nil, rpcstatus.Error(codes.Unauthenticated, resp.CodeUnauthorized, "failed to authentication")
+		}
+
+		if principal.Delegated() {
+			log.Error("failed to authenticate", slog.String("client_id", principal.ClientId), sl.Err(authentication.ErrDelegatedToken))
+
+			return nil, rpcstatus.Error(codes.PermissionDenied, resp.CodeAccessDenied, "access not allowed")
+		}
+
+		return handler(authentication.WithPrincipal(ctx, principal), req)
+	}
+}
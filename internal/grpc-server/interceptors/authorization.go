@@ -0,0 +1,75 @@
+package interceptors
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"auth/internal/lib/logger/sl"
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"log/slog"
+)
+
+type Resolver func(req any, principal *models.Principal) (int64, error)
+
+type Rule struct {
+	Resolver    Resolver
+	Permissions []string
+}
+
+func Authorization(log *slog.Logger, rules map[string]Rule) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "interceptors.Authorization"
+
+		rule, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(ctx)
+		if !ok {
+			log.Error("principal not found in context")
+
+			return nil, rpcstatus.Error(codes.Unauthenticated, resp.CodeUnauthorized, "failed to authentication")
+		}
+
+		targetId := principal.UserId
+		if rule.Resolver != nil {
+			var err error
+			targetId, err = rule.Resolver(req, principal)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
+
+				return nil, rpcstatus.Error(codes.InvalidArgument, resp.CodeInvalidRequest, "invalid request")
+			}
+		}
+
+		if err := authorization.Authorize(principal, targetId, rule.Permissions); err != nil {
+			log.Error("access not allowed", slog.Int64("user_id", principal.UserId), slog.Int64("target_id", targetId), sl.Err(err))
+
+			return nil, rpcstatus.Error(codes.PermissionDenied, resp.CodeAccessDenied, "access not allowed")
+		}
+
+		return handler(authorization.WithTarget(ctx, targetId), req)
+	}
+}
+
+func FromUserId() Resolver {
+	return func(req any, _ *models.Principal) (int64, error) {
+		target, ok := req.(interface{ GetUserId() int64 })
+		if !ok || target.GetUserId() == 0 {
+			return 0, authorization.ErrNoTarget
+		}
+
+		return target.GetUserId(), nil
+	}
+}
@@ -0,0 +1,28 @@
+package interceptors
+
+import (
+	"auth/internal/lib/clientip"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"strings"
+)
+
+func ClientIp(resolver *clientip.Resolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+
+		var forwardedFor []string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			forwardedFor = md.Get(clientip.HeaderForwardedFor)
+		}
+
+		ip := resolver.Resolve(remoteAddr, strings.Join(forwardedFor, ","), firstValue(ctx, clientip.HeaderRealIp))
+
+		return handler(clientip.NewContext(ctx, ip), req)
+	}
+}
@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"time"
+)
+
+func Logger(log *slog.Logger) grpc.UnaryServerInterceptor {
+	log = log.With(slog.String("component", "interceptors/logger"))
+
+	log.Info("logger interceptor enabled")
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		entry := log.With(
+			slog.String("method", info.FullMethod),
+			slog.String("remote_addr", remoteAddr(ctx)),
+			slog.String("user_agent", firstValue(ctx, "user-agent")),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+		)
+
+		t1 := time.Now()
+		res, err := handler(ctx, req)
+
+		entry.Info("request completed",
+			slog.String("code", status.Code(err).String()),
+			slog.String("duration", time.Since(t1).String()),
+		)
+
+		return res, err
+	}
+}
+
+func remoteAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	return p.Addr.String()
+}
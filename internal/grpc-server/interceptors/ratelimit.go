@@ -0,0 +1,100 @@
+package interceptors
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	ratelimitMiddleware "auth/internal/http-server/middleware/ratelimit"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/logger/sl"
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+func RateLimit(log *slog.Logger, limiter ratelimitMiddleware.Limiter, failOpen bool, methods map[string][]ratelimitMiddleware.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "interceptors.RateLimit"
+
+		policies, ok := methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(ctx)),
+		)
+
+		for _, policy := range policies {
+			key := rateLimitKey(ctx, req, policy.KeyName)
+			if key == "" {
+				continue
+			}
+
+			allowed, retryAfter, err := limiter.Allow(policy.Name+":"+key, policy.Rate, policy.Burst)
+			if err != nil {
+				log.Error("failed to check rate limit", sl.Err(err), slog.String("policy", policy.Name))
+
+				if failOpen {
+					continue
+				}
+
+				return nil, rpcstatus.Error(codes.Unavailable, resp.CodeUnavailable, "rate limiter is unavailable")
+			}
+
+			if !allowed {
+				log.Info("rate limit exceeded", slog.String("policy", policy.Name), slog.Duration("retry_after", retryAfter))
+
+				return nil, rpcstatus.RetryError(codes.ResourceExhausted, resp.CodeRateLimited, "too many requests", retryAfter)
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func rateLimitKey(ctx context.Context, req any, name string) string {
+	switch name {
+	case ratelimitMiddleware.KeyIp:
+		return clientip.FromContext(ctx)
+	case ratelimitMiddleware.KeyUserId:
+		principal, ok := authentication.PrincipalFromContext(ctx)
+		if !ok {
+			return ""
+		}
+
+		return strconv.FormatInt(principal.UserId, 10)
+	case ratelimitMiddleware.KeyClientId:
+		return ""
+	default:
+		return strings.ToLower(strings.TrimSpace(messageField(req, name)))
+	}
+}
+
+func messageField(req any, name string) string {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+
+	reflected := message.ProtoReflect()
+	fields := reflected.Descriptor().Fields()
+
+	field := fields.ByName(protoreflect.Name(name))
+	if field == nil {
+		field = fields.ByJSONName(name)
+	}
+
+	if field == nil || field.Kind() != protoreflect.StringKind || field.IsList() {
+		return ""
+	}
+
+	return reflected.Get(field).String()
+}
@@ -0,0 +1,120 @@
+package interceptors
+
+import (
+	ratelimitMiddleware "auth/internal/http-server/middleware/ratelimit"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	authv1 "auth/pkg/api/auth/v1"
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeLimiter struct {
+	err  error
+	used map[string]int
+}
+
+func (l *fakeLimiter) Allow(key string, rate float64, burst int) (bool, time.Duration, error) {
+	if l.err != nil {
+		return false, 0, l.err
+	}
+
+	l.used[key]++
+
+	return l.used[key] <= burst, time.Second, nil
+}
+
+func newPolicy(t *testing.T, route, key string) ratelimitMiddleware.Policy {
+	t.Helper()
+
+	policy, err := ratelimitMiddleware.NewPolicy(route, key, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return policy
+}
+
+func call(interceptor grpc.UnaryServerInterceptor, method, ip string, req any) error {
+	ctx := clientip.NewContext(context.Background(), ip)
+
+	_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+
+	return err
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := &fakeLimiter{used: make(map[string]int)}
+	interceptor := RateLimit(slogdiscard.NewDiscardLogger(), limiter, false, map[string][]ratelimitMiddleware.Policy{
+		authv1.AuthService_Login_FullMethodName: {newPolicy(t, "login", "contact_info")},
+	})
+
+	login := &authv1.LoginRequest{ContactInfo: " User@Example.com ", Password: "secret"}
+
+	if err := call(interceptor, authv1.AuthService_Login_FullMethodName, "203.0.113.5", login); err != nil {
+		t.Fatalf("first call: error = %v", err)
+	}
+
+	err := call(interceptor, authv1.AuthService_Login_FullMethodName, "203.0.113.6", &authv1.LoginRequest{ContactInfo: "user@example.com"})
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("same contact: code = %s, want %s", got, codes.ResourceExhausted)
+	}
+
+	if limiter.used["login:contact_info:user@example.com"] != 2 {
+		t.Errorf("used = %v, want normalized contact key", limiter.used)
+	}
+
+	if err := call(interceptor, authv1.AuthService_Login_FullMethodName, "203.0.113.5", &authv1.LoginRequest{ContactInfo: "other@example.com"}); err != nil {
+		t.Errorf("other contact: error = %v", err)
+	}
+
+	if err := call(interceptor, authv1.AuthService_ValidateToken_FullMethodName, "203.0.113.5", &authv1.ValidateTokenRequest{}); err != nil {
+		t.Errorf("unlimited method: error = %v", err)
+	}
+}
+
+func TestRateLimitSharesBucketsWithHttp(t *testing.T) {
+	limiter := &fakeLimiter{used: make(map[string]int)}
+	policy := newPolicy(t, "login", ratelimitMiddleware.KeyIp)
+
+	handler := ratelimitMiddleware.New(slogdiscard.NewDiscardLogger(), limiter, false, policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{}`))
+	req.RemoteAddr = "203.0.113.5:1000"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	interceptor := RateLimit(slogdiscard.NewDiscardLogger(), limiter, false, map[string][]ratelimitMiddleware.Policy{
+		authv1.AuthService_Login_FullMethodName: {policy},
+	})
+
+	err := call(interceptor, authv1.AuthService_Login_FullMethodName, "203.0.113.5", &authv1.LoginRequest{})
+	if got := status.Code(err); got != codes.ResourceExhausted {
+		t.Errorf("code = %s, want %s", got, codes.ResourceExhausted)
+	}
+}
+
+func TestRateLimitFailure(t *testing.T) {
+	limiter := &fakeLimiter{err: errors.New("store is down")}
+	methods := map[string][]ratelimitMiddleware.Policy{
+		authv1.AuthService_Register_FullMethodName: {newPolicy(t, "register", ratelimitMiddleware.KeyIp)},
+	}
+
+	closed := RateLimit(slogdiscard.NewDiscardLogger(), limiter, false, methods)
+	if got := status.Code(call(closed, authv1.AuthService_Register_FullMethodName, "203.0.113.5", &authv1.RegisterRequest{})); got != codes.Unavailable {
+		t.Errorf("fail closed: code = %s, want %s", got, codes.Unavailable)
+	}
+
+	open := RateLimit(slogdiscard.NewDiscardLogger(), limiter, true, methods)
+	if err := call(open, authv1.AuthService_Register_FullMethodName, "203.0.113.5", &authv1.RegisterRequest{}); err != nil {
+		t.Errorf("fail open: error = %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package interceptors
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"log/slog"
+	"runtime/debug"
+)
+
+func Recoverer(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer func() {
+			if rvr := recover(); rvr != nil {
+				log.Error("panic recovered",
+					slog.String("method", info.FullMethod),
+					slog.String("request_id", middleware.GetReqID(ctx)),
+					slog.Any("panic", rvr),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				err = rpcstatus.Error(codes.Internal, resp.CodeInternal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
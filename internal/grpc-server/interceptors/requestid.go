@@ -0,0 +1,38 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const MetadataRequestId = "x-request-id"
+
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requestId := firstValue(ctx, MetadataRequestId)
+		if requestId == "" {
+			requestId = uuid.NewString()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestId, requestId))
+
+		return handler(context.WithValue(ctx, middleware.RequestIDKey, requestId), req)
+	}
+}
+
+func firstValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,65 @@
+package userserver
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/rpcstatus"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/storage"
+	authv1 "auth/pkg/api/auth/v1"
+	"context"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"log/slog"
+)
+
+type UserService interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type Server struct {
+	authv1.UnimplementedUserServiceServer
+	log         *slog.Logger
+	userService UserService
+}
+
+func Register(gRPC *grpc.Server, log *slog.Logger, userService UserService) {
+	authv1.RegisterUserServiceServer(gRPC, &Server{
+		log:         log,
+		userService: userService,
+	})
+}
+
+func (s *Server) GetUser(ctx context.Context, in *authv1.GetUserRequest) (*authv1.GetUserResponse, error) {
+	const op = "grpc.userserver.GetUser"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(ctx)),
+	)
+
+	user, err := s.userService.UserByUserId(in.GetUserId())
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, rpcstatus.Error(codes.NotFound, resp.CodeUserNotFound, "user not found")
+	}
+
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+
+		return nil, rpcstatus.Error(codes.Internal, resp.CodeInternal, "failed to get user")
+	}
+
+	return &authv1.GetUserResponse{
+		User: &authv1.User{
+			Id:            user.Id,
+			FullName:      user.FullName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			Phone:         user.Phone,
+			PhoneVerified: user.PhoneVerifiedAt != nil,
+			UserRole:      user.RoleString,
+		},
+	}, nil
+}
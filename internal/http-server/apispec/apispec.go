@@ -0,0 +1,378 @@
+package apispec
+
+import (
+	"auth/internal/http-server/handlers/url/approveemployer"
+	"auth/internal/http-server/handlers/url/deleteuser"
+	"auth/internal/http-server/handlers/url/forgotpassword"
+	"auth/internal/http-server/handlers/url/grantpermission"
+	"auth/internal/http-server/handlers/url/login"
+	"auth/internal/http-server/handlers/url/loginmfa"
+	"auth/internal/http-server/handlers/url/logout"
+	"auth/internal/http-server/handlers/url/logoutall"
+	"auth/internal/http-server/handlers/url/me"
+	"auth/internal/http-server/handlers/url/oauthclients"
+	"auth/internal/http-server/handlers/url/pendingemployers"
+	"auth/internal/http-server/handlers/url/permissions"
+	"auth/internal/http-server/handlers/url/refreshtokens"
+	"auth/internal/http-server/handlers/url/register"
+	"auth/internal/http-server/handlers/url/registeremployer"
+	"auth/internal/http-server/handlers/url/registeroauthclient"
+	"auth/internal/http-server/handlers/url/registerserviceaccount"
+	"auth/internal/http-server/handlers/url/rejectemployer"
+	"auth/internal/http-server/handlers/url/resendverification"
+	"auth/internal/http-server/handlers/url/restorepassword"
+	"auth/internal/http-server/handlers/url/restoreuser"
+	"auth/internal/http-server/handlers/url/revokepermission"
+	"auth/internal/http-server/handlers/url/revokesession"
+	"auth/internal/http-server/handlers/url/rolepermissions"
+	"auth/internal/http-server/handlers/url/sendphonecode"
+	"auth/internal/http-server/handlers/url/serviceaccountpermissions"
+	"auth/internal/http-server/handlers/url/sessions"
+	"auth/internal/http-server/handlers/url/totpconfirm"
+	"auth/internal/http-server/handlers/url/totpenroll"
+	"auth/internal/http-server/handlers/url/unlockuser"
+	"auth/internal/http-server/handlers/url/updateme"
+	"auth/internal/http-server/handlers/url/updateuser"
+	"auth/internal/http-server/handlers/url/user"
+	"auth/internal/http-server/handlers/url/verifyemail"
+	"auth/internal/http-server/handlers/url/verifyphone"
+	"auth/internal/lib/api/etag"
+	"auth/internal/lib/api/openapi"
+	"net/http"
+)
+
+const (
+	Title   = "vacancy-tomsk auth"
+	Version = "1.0.0"
+	Prefix  = "/api/auth/"
+)
+
+func New() *openapi.Spec {
+	spec := openapi.New(Title, Version)
+
+	spec.Add(
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/register",
+			Summary:  "Register a job seeker",
+			Tag:      "auth",
+			Request:  register.Request{},
+			Response: register.Response{},
+			Status:   http.StatusCreated,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/register-employer",
+			Summary:  "Register an employer with company requisites",
+			Tag:      "auth",
+			Request:  registeremployer.Request{},
+			Response: registeremployer.Response{},
+			Status:   http.StatusCreated,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/login",
+			Summary:  "Log in with contact info and password",
+			Tag:      "auth",
+			Request:  login.Request{},
+			Response: login.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/login/mfa",
+			Summary:  "Complete login with a second factor",
+			Tag:      "auth",
+			Request:  loginmfa.Request{},
+			Response: loginmfa.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/restore-password",
+			Summary:  "Set a new password using a restore link",
+			Tag:      "auth",
+			Request:  restorepassword.Request{},
+			Response: restorepassword.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/forgot-password",
+			Summary:  "Send a password restore link",
+			Tag:      "auth",
+			Request:  forgotpassword.Request{},
+			Response: forgotpassword.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/verify-email",
+			Summary:  "Confirm an email address",
+			Tag:      "verification",
+			Request:  verifyemail.Request{},
+			Response: verifyemail.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/verify-email/resend",
+			Summary:  "Resend the email verification link",
+			Tag:      "verification",
+			Request:  resendverification.Request{},
+			Response: resendverification.Response{},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/refresh-tokens",
+			Summary:  "Rotate the refresh token",
+			Tag:      "auth",
+			Request:  refreshtokens.Request{},
+			Response: refreshtokens.Response{},
+		},
+		openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/api/auth/openapi.json",
+			Summary: "OpenAPI document",
+			Tag:     "meta",
+		},
+		openapi.Operation{
+			Method:  http.MethodGet,
+			Path:    "/api/auth/verify",
+			Summary: "Forward-auth check for reverse proxies",
+			Tag:     "auth",
+			Secured: true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/logout",
+			Summary:  "Log out of the current session",
+			Tag:      "sessions",
+			Response: logout.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/logout-all",
+			Summary:  "Log out of all sessions",
+			Tag:      "sessions",
+			Response: logoutall.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/sessions",
+			Summary:  "List active sessions",
+			Tag:      "sessions",
+			Response: sessions.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodDelete,
+			Path:     "/api/auth/sessions/{session_id}",
+			Summary:  "Revoke a session",
+			Tag:      "sessions",
+			Response: revokesession.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/mfa/totp/enroll",
+			Summary:  "Start TOTP enrollment",
+			Tag:      "mfa",
+			Response: totpenroll.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/mfa/totp/confirm",
+			Summary:  "Confirm TOTP enrollment",
+			Tag:      "mfa",
+			Request:  totpconfirm.Request{},
+			Response: totpconfirm.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/verify-phone/send",
+			Summary:  "Send a phone verification code",
+			Tag:      "verification",
+			Response: sendphonecode.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/verify-phone",
+			Summary:  "Confirm a phone number",
+			Tag:      "verification",
+			Request:  verifyphone.Request{},
+			Response: verifyphone.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/me",
+			Summary:  "Get the current user's profile",
+			Tag:      "users",
+			Response: me.Response{},
+			Secured:  true,
+
+			ResponseHeaders: []string{etag.HeaderETag},
+		},
+		openapi.Operation{
+			Method:   http.MethodPatch,
+			Path:     "/api/auth/me",
+			Summary:  "Update the current user's profile",
+			Tag:      "users",
+			Request:  updateme.Request{},
+			Response: me.Response{},
+			Secured:  true,
+
+			RequestHeaders:  []string{etag.HeaderIfMatch},
+			ResponseHeaders: []string{etag.HeaderETag},
+		},
+		openapi.Operation{
+			Method:     http.MethodGet,
+			Path:       "/api/auth/users/{user_id}",
+			Summary:    "Get a user",
+			Tag:        "users",
+			Response:   user.Response{},
+			Secured:    true,
+			PathParams: map[string]any{user.ParameterUserIdName: int64(0)},
+
+			ResponseHeaders: []string{etag.HeaderETag},
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/update-user",
+			Summary:  "Update a user",
+			Tag:      "users",
+			Request:  updateuser.Request{},
+			Response: updateuser.Response{},
+			Secured:  true,
+
+			RequestHeaders:  []string{etag.HeaderIfMatch},
+			ResponseHeaders: []string{etag.HeaderETag},
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/delete-user",
+			Summary:  "Delete a user",
+			Tag:      "users",
+			Request:  deleteuser.Request{},
+			Response: deleteuser.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/restore-user",
+			Summary:  "Restore a deleted user",
+			Tag:      "users",
+			Request:  restoreuser.Request{},
+			Response: restoreuser.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/admin/unlock-user",
+			Summary:  "Unlock a locked account",
+			Tag:      "admin",
+			Request:  unlockuser.Request{},
+			Response: unlockuser.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/admin/employers/pending",
+			Summary:  "List employers awaiting moderation",
+			Tag:      "admin",
+			Response: pendingemployers.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:     http.MethodPost,
+			Path:       "/api/auth/admin/employers/{user_id}/approve",
+			Summary:    "Approve an employer",
+			Tag:        "admin",
+			Response:   approveemployer.Response{},
+			Secured:    true,
+			PathParams: map[string]any{approveemployer.ParameterUserIdName: int64(0)},
+		},
+		openapi.Operation{
+			Method:     http.MethodPost,
+			Path:       "/api/auth/admin/employers/{user_id}/reject",
+			Summary:    "Reject an employer",
+			Tag:        "admin",
+			Request:    rejectemployer.Request{},
+			Response:   rejectemployer.Response{},
+			Secured:    true,
+			PathParams: map[string]any{rejectemployer.ParameterUserIdName: int64(0)},
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/admin/oauth/clients",
+			Summary:  "Register an OAuth client",
+			Tag:      "admin",
+			Request:  registeroauthclient.Request{},
+			Response: registeroauthclient.Response{},
+			Status:   http.StatusCreated,
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/admin/oauth/clients",
+			Summary:  "List OAuth clients",
+			Tag:      "admin",
+			Response: oauthclients.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPost,
+			Path:     "/api/auth/admin/service-accounts",
+			Summary:  "Register a service account",
+			Tag:      "admin",
+			Request:  registerserviceaccount.Request{},
+			Response: registerserviceaccount.Response{},
+			Status:   http.StatusCreated,
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/admin/service-accounts/{client_id}/permissions",
+			Summary:  "Set service account permissions",
+			Tag:      "admin",
+			Request:  serviceaccountpermissions.Request{},
+			Response: serviceaccountpermissions.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/admin/permissions",
+			Summary:  "List permissions",
+			Tag:      "admin",
+			Response: permissions.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodGet,
+			Path:     "/api/auth/admin/roles/{role}/permissions",
+			Summary:  "List role permissions",
+			Tag:      "admin",
+			Response: rolepermissions.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodPut,
+			Path:     "/api/auth/admin/roles/{role}/permissions/{permission}",
+			Summary:  "Grant a permission to a role",
+			Tag:      "admin",
+			Response: grantpermission.Response{},
+			Secured:  true,
+		},
+		openapi.Operation{
+			Method:   http.MethodDelete,
+			Path:     "/api/auth/admin/roles/{role}/permissions/{permission}",
+			Summary:  "Revoke a permission from a role",
+			Tag:      "admin",
+			Response: revokepermission.Response{},
+			Secured:  true,
+		},
+	)
+
+	return spec
+}
@@ -0,0 +1,75 @@
+package approveemployer
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/moderation"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	ParameterUserIdName = "user_id"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type ModerationService interface {
+	Approve(userId int64) error
+}
+
+func New(log *slog.Logger, moderationService ModerationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.approveemployer.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userId, err := strconv.ParseInt(chi.URLParam(r, ParameterUserIdName), 10, 64)
+		if err != nil {
+			log.Error("invalid user id", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		err = moderationService.Approve(userId)
+		if errors.Is(err, moderation.ErrCompanyNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeEmployerNotFound, "employer not found")
+
+			return
+		}
+
+		if errors.Is(err, moderation.ErrAlreadyReviewed) {
+			resp.Error(w, r, http.StatusConflict, resp.CodeAlreadyReviewed, "employer is already reviewed")
+
+			return
+		}
+
+		if errors.Is(err, moderation.ErrNotificationFailed) {
+			log.Error("failed to notify employer", sl.Err(err))
+		} else if err != nil {
+			log.Error("failed to approve employer", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to approve employer")
+
+			return
+		}
+
+		log.Info("employer approved", slog.Int64("user_id", userId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
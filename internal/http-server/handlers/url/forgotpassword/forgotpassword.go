@@ -3,14 +3,15 @@ package forgotpassword
 import (
 	"auth/internal/domain/models"
 	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
 	"auth/internal/lib/email"
 	"auth/internal/lib/logger/sl"
+	"auth/internal/services/auth"
 	"auth/internal/storage"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
 	"github.com/google/uuid"
 	"log/slog"
 	"net/http"
@@ -31,6 +32,7 @@ type LinkProvider interface {
 
 type UserService interface {
 	UserByContactInfo(contactInfo string) (*models.User, error)
+	CheckContactInfo(user *models.User, contactInfo string) error
 }
 
 const (
@@ -51,17 +53,17 @@ func New(log *slog.Logger, linkProvider LinkProvider, userService UserService, c
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
 
 			return
 		}
@@ -70,7 +72,7 @@ func New(log *slog.Logger, linkProvider LinkProvider, userService UserService, c
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Info("user not found", slog.String("contact_info", req.ContactInfo))
 
-			render.JSON(w, r, resp.Error("user not found"))
+			resp.Error(w, r, http.StatusNotFound, resp.CodeUserNotFound, "user not found")
 
 			return
 		}
@@ -78,7 +80,15 @@ func New(log *slog.Logger, linkProvider LinkProvider, userService UserService, c
 		if err != nil {
 			log.Info("failed to get user", slog.String("contact_info", req.ContactInfo))
 
-			render.JSON(w, r, resp.Error("failed to get user"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		if errors.Is(userService.CheckContactInfo(user, req.ContactInfo), auth.ErrPhoneNotVerified) {
+			log.Info("phone is not verified", slog.String("contact_info", req.ContactInfo))
+
+			resp.Error(w, r, http.StatusForbidden, resp.CodePhoneNotVerified, "phone is not verified")
 
 			return
 		}
@@ -88,7 +98,7 @@ func New(log *slog.Logger, linkProvider LinkProvider, userService UserService, c
 		if err != nil {
 			log.Error("failed to get link", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to get link"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get link")
 
 			return
 		}
@@ -99,7 +109,7 @@ func New(log *slog.Logger, linkProvider LinkProvider, userService UserService, c
 		if err != nil || response.StatusCode != http.StatusOK {
 			log.Error("failed to send email", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to send email"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to send email")
 
 			return
 		}
@@ -0,0 +1,75 @@
+package grantpermission
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/permissions"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterRoleName       = "role"
+	ParameterPermissionName = "permission"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PermissionService interface {
+	Grant(role models.UserRole, permission string) error
+}
+
+func New(log *slog.Logger, permissionService PermissionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.grantpermission.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		role := chi.URLParam(r, ParameterRoleName)
+		permission := chi.URLParam(r, ParameterPermissionName)
+
+		err := permissionService.Grant(enums.RoleConvertFromString(role), permission)
+		if errors.Is(err, permissions.ErrUnknownRole) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeRoleNotFound, "unknown role")
+
+			return
+		}
+
+		if errors.Is(err, permissions.EmptyPermissionErr) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		if errors.Is(err, permissions.ErrPermissionNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodePermissionNotFound, "permission not found")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to grant permission", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to grant permission")
+
+			return
+		}
+
+		log.Info("permission granted", slog.String("role", role), slog.String("permission", permission))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package jwks
+
+import (
+	"auth/internal/lib/jwk"
+	"auth/internal/lib/logger/sl"
+	"fmt"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type KeyProvider interface {
+	PublicKeys() (*jwk.Set, error)
+}
+
+func New(log *slog.Logger, keyProvider KeyProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.jwks.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		set, err := keyProvider.PublicKeys()
+		if err != nil {
+			log.Error("failed to get public keys", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwk.CacheMaxAge.Seconds())))
+
+		render.JSON(w, r, set)
+	}
+}
@@ -1,19 +1,20 @@
 package login
 
 import (
-	"auth/internal/domain/models"
 	resp "auth/internal/lib/api/response"
-	"auth/internal/lib/jwt"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/clientip"
 	"auth/internal/lib/logger/sl"
-	"auth/internal/storage"
+	"auth/internal/services/auth"
+	"auth/internal/services/emailverification"
+	"auth/internal/services/lockout"
+	loginService "auth/internal/services/login"
+	"auth/internal/services/mfa"
 	"errors"
-	"fmt"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
 	"log/slog"
 	"net/http"
-	"time"
 )
 
 type Request struct {
@@ -23,21 +24,21 @@ type Request struct {
 
 type Response struct {
 	resp.Response
-	Token string
+	Token        string
+	RefreshToken string
+	MfaRequired  bool
+	MfaToken     string
 }
 
-type UserService interface {
-	UserByPhone(email string) (*models.User, error)
-	UserByEmail(email string) (*models.User, error)
-	UserByContactInfo(contactInfo string) (*models.User, error)
-	Authorize(user *models.User, password string) error
+type LoginService interface {
+	Login(contactInfo, password, device, ip string) (*loginService.Result, error)
 }
 
-func New(log *slog.Logger, userService UserService, tokenTtl time.Duration, secretKey string) http.HandlerFunc {
+func New(log *slog.Logger, loginService LoginService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		const op = "handlers.url.authentication.New"
+		const op = "handlers.url.login.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -47,60 +48,93 @@ func New(log *slog.Logger, userService UserService, tokenTtl time.Duration, secr
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
-		log.Info("request body decoded", slog.Any("request", req))
-
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
 
 			return
 		}
 
-		user, err := userService.UserByContactInfo(req.ContactInfo)
-		if errors.Is(err, storage.ErrUserNotFound) {
-			log.Info("user not found", slog.String("contact_info", req.ContactInfo))
-
-			render.JSON(w, r, resp.Error("user not found"))
+		result, err := loginService.Login(req.ContactInfo, req.Password, r.UserAgent(), clientip.FromRequest(r))
+		if err != nil {
+			resp.RenderProblem(w, r, Problem(log, err))
 
 			return
 		}
 
-		if err != nil {
-			log.Error("failed to authentication", sl.Err(err))
+		if result.MfaToken != "" {
+			log.Info("mfa required")
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+			render.JSON(w, r, Response{
+				Response:    resp.Ok(),
+				MfaRequired: true,
+				MfaToken:    result.MfaToken,
+			})
 
 			return
 		}
 
-		if err := userService.Authorize(user, req.Password); err != nil {
-			log.Error("invalid credentials", fmt.Errorf(err.Error()))
+		log.Info("user logged in successfully")
 
-			render.JSON(w, r, resp.Error("invalid credentials"))
+		render.JSON(w, r, Response{
+			Response:     resp.Ok(),
+			Token:        result.Tokens.AccessToken,
+			RefreshToken: result.Tokens.RefreshToken,
+		})
+	}
+}
 
-			return
+func Problem(log *slog.Logger, err error) resp.Problem {
+	var lockedErr *lockout.LockedError
+	if errors.As(err, &lockedErr) {
+		log.Info("login attempt rejected", sl.Err(err), slog.Duration("retry_after", lockedErr.RetryAfter))
+
+		problem := resp.NewProblem(http.StatusTooManyRequests, resp.CodeTooManyAttempts, "too many login attempts")
+		if errors.Is(err, lockout.ErrAccountLocked) {
+			problem = resp.NewProblem(http.StatusTooManyRequests, resp.CodeAccountLocked, "account is temporarily locked")
 		}
+		problem.RetryAfter = lockedErr.RetryAfter
 
-		log.Info("user logged in successfully")
+		return problem
+	}
 
-		token, err := jwt.NewToken(*user, secretKey, tokenTtl)
-		if err != nil {
-			log.Error("failed to generate token", fmt.Errorf(err.Error()))
+	if errors.Is(err, loginService.ErrInvalidCredentials) {
+		log.Info("invalid credentials", sl.Err(err))
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeInvalidCredentials, "invalid credentials")
+	}
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+	if errors.Is(err, auth.ErrPhoneNotVerified) {
+		log.Info("phone is not verified")
 
-			return
-		}
+		return resp.NewProblem(http.StatusForbidden, resp.CodePhoneNotVerified, "phone is not verified")
+	}
 
-		render.JSON(w, r, Response{
-			Response: resp.Ok(),
-			Token:    token,
-		})
+	if errors.Is(err, emailverification.ErrEmailNotVerified) {
+		log.Info("email is not verified")
+
+		return resp.NewProblem(http.StatusForbidden, resp.CodeEmailNotVerified, "email is not verified")
 	}
+
+	if errors.Is(err, mfa.ErrInvalidMfaToken) {
+		log.Info("invalid mfa token", sl.Err(err))
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeInvalidToken, "invalid mfa token")
+	}
+
+	if errors.Is(err, mfa.ErrInvalidCode) {
+		log.Info("invalid mfa code", sl.Err(err))
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeInvalidCode, "invalid code")
+	}
+
+	log.Error("failed to authentication", sl.Err(err))
+
+	return resp.NewProblem(http.StatusInternalServerError, resp.CodeInternal, "failed to authentication")
 }
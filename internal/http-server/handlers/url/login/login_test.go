@@ -0,0 +1,116 @@
+package login
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/auth"
+	"auth/internal/services/emailverification"
+	"auth/internal/services/lockout"
+	loginService "auth/internal/services/login"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeLoginService struct {
+	result *loginService.Result
+	err    error
+}
+
+func (s fakeLoginService) Login(contactInfo, password, device, ip string) (*loginService.Result, error) {
+	return s.result, s.err
+}
+
+func login(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		service fakeLoginService
+		code    int
+		body    string
+	}{
+		{
+			name:    "tokens",
+			service: fakeLoginService{result: &loginService.Result{Tokens: &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}}},
+			code:    http.StatusOK,
+			body:    `"Token":"access"`,
+		},
+		{
+			name:    "mfa required",
+			service: fakeLoginService{result: &loginService.Result{MfaToken: "challenge"}},
+			code:    http.StatusOK,
+			body:    `"MfaRequired":true`,
+		},
+		{
+			name:    "invalid credentials",
+			service: fakeLoginService{err: fmt.Errorf("op: %w", loginService.ErrInvalidCredentials)},
+			code:    http.StatusUnauthorized,
+			body:    "invalid_credentials",
+		},
+		{
+			name:    "phone not verified",
+			service: fakeLoginService{err: fmt.Errorf("op: %w", auth.ErrPhoneNotVerified)},
+			code:    http.StatusForbidden,
+			body:    "phone_not_verified",
+		},
+		{
+			name:    "email not verified",
+			service: fakeLoginService{err: fmt.Errorf("op: %w", emailverification.ErrEmailNotVerified)},
+			code:    http.StatusForbidden,
+			body:    "email_not_verified",
+		},
+		{
+			name:    "account locked",
+			service: fakeLoginService{err: fmt.Errorf("op: %w", &lockout.LockedError{Err: lockout.ErrAccountLocked, RetryAfter: time.Minute})},
+			code:    http.StatusTooManyRequests,
+			body:    "account_locked",
+		},
+		{
+			name:    "internal error",
+			service: fakeLoginService{err: errors.New("storage is down")},
+			code:    http.StatusInternalServerError,
+			body:    "internal",
+		},
+	}
+
+	for _, tt := range tests {
+		handler := New(slogdiscard.NewDiscardLogger(), tt.service)
+
+		rr := login(handler, `{"contact_info":"user@example.com","password":"secret"}`)
+		if rr.Code != tt.code || !strings.Contains(rr.Body.String(), tt.body) {
+			t.Errorf("%s: status = %d, body = %s, want %d with %s", tt.name, rr.Code, rr.Body.String(), tt.code, tt.body)
+		}
+	}
+}
+
+func TestLoginSetsRetryAfter(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), fakeLoginService{
+		err: &lockout.LockedError{Err: lockout.ErrTooManyAttempts, RetryAfter: time.Minute},
+	})
+
+	rr := login(handler, `{"contact_info":"user@example.com","password":"secret"}`)
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "61" {
+		t.Errorf("status = %d, retry after = %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
+
+func TestLoginValidatesRequest(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), fakeLoginService{err: errors.New("unexpected call")})
+
+	rr := login(handler, `{"contact_info":"user@example.com"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,74 @@
+package loginmfa
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/handlers/url/login"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	MfaToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type Response struct {
+	resp.Response
+	Token        string
+	RefreshToken string
+}
+
+type LoginService interface {
+	LoginMfa(mfaToken, code, recoveryCode, device, ip string) (*models.TokenPair, error)
+}
+
+func New(log *slog.Logger, loginService LoginService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.loginmfa.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil || (req.Code == "") == (req.RecoveryCode == "") {
+			log.Error("invalid request", slog.Any("validation", err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		tokens, err := loginService.LoginMfa(req.MfaToken, req.Code, req.RecoveryCode, r.UserAgent(), clientip.FromRequest(r))
+		if err != nil {
+			resp.RenderProblem(w, r, login.Problem(log, err))
+
+			return
+		}
+
+		log.Info("user logged in successfully")
+
+		render.JSON(w, r, Response{
+			Response:     resp.Ok(),
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package loginmfa
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/lockout"
+	"auth/internal/services/mfa"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeLoginService struct {
+	calls int
+	err   error
+}
+
+func (s *fakeLoginService) LoginMfa(mfaToken, code, recoveryCode, device, ip string) (*models.TokenPair, error) {
+	s.calls++
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func loginMfa(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login/mfa", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestLoginMfa(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+		body string
+	}{
+		{name: "valid code", code: http.StatusOK, body: `"Token":"access"`},
+		{name: "invalid code", err: fmt.Errorf("op: %w", mfa.ErrInvalidCode), code: http.StatusUnauthorized, body: "invalid_code"},
+		{name: "reused challenge", err: fmt.Errorf("op: %w", mfa.ErrInvalidMfaToken), code: http.StatusUnauthorized, body: "invalid_token"},
+	}
+
+	for _, tt := range tests {
+		handler := New(slogdiscard.NewDiscardLogger(), &fakeLoginService{err: tt.err})
+
+		rr := loginMfa(handler, `{"mfa_token":"challenge","code":"123456"}`)
+		if rr.Code != tt.code || !strings.Contains(rr.Body.String(), tt.body) {
+			t.Errorf("%s: status = %d, body = %s, want %d with %s", tt.name, rr.Code, rr.Body.String(), tt.code, tt.body)
+		}
+	}
+}
+
+func TestLoginMfaRequiresExactlyOneCode(t *testing.T) {
+	service := &fakeLoginService{}
+	handler := New(slogdiscard.NewDiscardLogger(), service)
+
+	for _, body := range []string{
+		`{"mfa_token":"challenge"}`,
+		`{"mfa_token":"challenge","code":"123456","recovery_code":"recovery"}`,
+	} {
+		if rr := loginMfa(handler, body); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, rr.Code, http.StatusBadRequest)
+		}
+	}
+
+	if service.calls != 0 {
+		t.Errorf("calls = %d, want 0", service.calls)
+	}
+}
+
+func TestLoginMfaRespectsLockout(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), &fakeLoginService{
+		err: &lockout.LockedError{Err: lockout.ErrAccountLocked, RetryAfter: time.Minute},
+	})
+
+	rr := loginMfa(handler, `{"mfa_token":"challenge","code":"123456"}`)
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("locked: status = %d, retry after = %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+}
@@ -0,0 +1,53 @@
+package logoutall
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type SessionService interface {
+	RevokeAll(userId int64) error
+}
+
+func New(log *slog.Logger, sessionService SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.logoutall.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		if err := sessionService.RevokeAll(principal.UserId); err != nil {
+			log.Error("failed to revoke sessions", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to logout")
+
+			return
+		}
+
+		log.Info("user logged out everywhere", slog.Int64("user_id", principal.UserId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package me
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/api/etag"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+	Id            int64  `json:"id,omitempty"`
+	FullName      string `json:"full_name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	PendingEmail  string `json:"pending_email,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	PhoneVerified bool   `json:"phone_verified,omitempty"`
+	PendingPhone  string `json:"pending_phone,omitempty"`
+	Role          string `json:"user_role,omitempty"`
+}
+
+type UserService interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+func New(log *slog.Logger, userService UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.me.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		user, err := userService.UserByUserId(principal.UserId)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeUserNotFound, "user not found")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		etag.Set(w, user.Version)
+		render.JSON(w, r, NewResponse(user))
+	}
+}
+
+func NewResponse(user *models.User) Response {
+	return Response{
+		Response:      resp.Ok(),
+		Id:            user.Id,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		PendingEmail:  user.PendingEmail,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerifiedAt != nil,
+		PendingPhone:  user.PendingPhone,
+		Role:          user.RoleString,
+	}
+}
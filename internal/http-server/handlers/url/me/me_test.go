@@ -0,0 +1,55 @@
+package me
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/api/etag"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/storage"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeUsers struct {
+	users map[int64]*models.User
+}
+
+func (u fakeUsers) UserByUserId(userId int64) (*models.User, error) {
+	user, ok := u.users[userId]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func getMe(handler http.HandlerFunc, userId int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+	req = req.WithContext(authentication.WithPrincipal(req.Context(), &models.Principal{UserId: userId}))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestMeSetsETag(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), fakeUsers{users: map[int64]*models.User{
+		1: {Id: 1, FullName: "User", Email: "user@example.com", Version: 5},
+	}})
+
+	rr := getMe(handler, 1)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+
+	if got := rr.Header().Get(etag.HeaderETag); got != etag.Format(5) {
+		t.Errorf("ETag = %q, want %q", got, etag.Format(5))
+	}
+
+	rr = getMe(handler, 2)
+	if rr.Code != http.StatusNotFound || rr.Header().Get(etag.HeaderETag) != "" {
+		t.Errorf("unknown user: status = %d, ETag = %q", rr.Code, rr.Header().Get(etag.HeaderETag))
+	}
+}
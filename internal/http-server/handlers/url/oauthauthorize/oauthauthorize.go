@@ -0,0 +1,158 @@
+package oauthauthorize
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	oauthresp "auth/internal/lib/api/oauth"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type AuthorizationService interface {
+	Client(clientId, redirectUri string) (*models.OAuthClient, error)
+	Authorize(userId int64, req oauth.AuthorizeRequest) (string, error)
+	Scopes(req oauth.AuthorizeRequest) ([]string, error)
+}
+
+func New(log *slog.Logger, authorizationService AuthorizationService, authenticator authentication.Authenticator, cookieName, loginUrl, consentUrl string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthauthorize.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		query := r.URL.Query()
+		req := oauth.AuthorizeRequest{
+			ResponseType:        query.Get("response_type"),
+			ClientId:            query.Get("client_id"),
+			RedirectUri:         query.Get("redirect_uri"),
+			Scope:               query.Get("scope"),
+			CodeChallenge:       query.Get("code_challenge"),
+			CodeChallengeMethod: query.Get("code_challenge_method"),
+			Nonce:               query.Get("nonce"),
+		}
+		state := query.Get("state")
+
+		client, err := authorizationService.Client(req.ClientId, req.RedirectUri)
+		if errors.Is(err, oauth.ErrInvalidClient) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidClient, "invalid client")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRedirectUri, "invalid redirect uri")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get client", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to authorize")
+
+			return
+		}
+
+		principal, err := authentication.AuthenticateWithCookie(r, authenticator, cookieName)
+		if err != nil || principal.Delegated() || principal.ServiceAccount || principal.UserId == 0 {
+			log.Info("user is not signed in, redirecting to login", slog.Any("error", err))
+
+			redirect(w, r, log, loginUrl, url.Values{"return_to": {r.URL.RequestURI()}})
+
+			return
+		}
+
+		code, err := authorizationService.Authorize(principal.UserId, req)
+		if errors.Is(err, oauth.ErrConsentRequired) {
+			scopes, err := authorizationService.Scopes(req)
+			if err != nil {
+				log.Error("failed to get scopes", sl.Err(err))
+
+				redirectError(w, r, log, req.RedirectUri, state, oauthresp.ErrorServerError)
+
+				return
+			}
+
+			params := url.Values{}
+			for key, values := range query {
+				params[key] = values
+			}
+			params.Set("scope", strings.Join(scopes, " "))
+			params.Set("client_name", client.Name)
+
+			redirect(w, r, log, consentUrl, params)
+
+			return
+		}
+
+		if errorCode, ok := redirectErrorCode(err); ok {
+			log.Info("authorization request rejected", slog.String("client_id", req.ClientId), sl.Err(err))
+
+			redirectError(w, r, log, req.RedirectUri, state, errorCode)
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to authorize", sl.Err(err))
+
+			redirectError(w, r, log, req.RedirectUri, state, oauthresp.ErrorServerError)
+
+			return
+		}
+
+		params := url.Values{"code": {code}}
+		if state != "" {
+			params.Set("state", state)
+		}
+
+		log.Info("authorization code issued", slog.Int64("user_id", principal.UserId), slog.String("client_id", req.ClientId))
+
+		redirect(w, r, log, req.RedirectUri, params)
+	}
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, log *slog.Logger, redirectUri, state, errorCode string) {
+	params := url.Values{"error": {errorCode}}
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	redirect(w, r, log, redirectUri, params)
+}
+
+func redirect(w http.ResponseWriter, r *http.Request, log *slog.Logger, target string, params url.Values) {
+	location, err := oauthresp.RedirectUri(target, params)
+	if err != nil {
+		log.Error("failed to build redirect uri", sl.Err(err))
+
+		resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to authorize")
+
+		return
+	}
+
+	http.Redirect(w, r, location, http.StatusFound)
+}
+
+func redirectErrorCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, oauth.ErrUnsupportedResponseType):
+		return oauthresp.ErrorUnsupportedResponseType, true
+	case errors.Is(err, oauth.ErrInvalidScope):
+		return oauthresp.ErrorInvalidScope, true
+	case errors.Is(err, oauth.ErrInvalidChallenge):
+		return oauthresp.ErrorInvalidRequest, true
+	default:
+		return "", false
+	}
+}
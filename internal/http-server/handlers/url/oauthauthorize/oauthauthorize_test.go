@@ -0,0 +1,151 @@
+package oauthauthorize
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/oauth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const (
+	cookieName = "access_token"
+	loginUrl   = "https://vacancy.example/login"
+	consentUrl = "https://vacancy.example/oauth/consent"
+)
+
+var errStorage = errors.New("storage is down")
+
+type fakeAuthorization struct {
+	err error
+}
+
+func (a fakeAuthorization) Client(clientId, redirectUri string) (*models.OAuthClient, error) {
+	if clientId != "partner" {
+		return nil, oauth.ErrInvalidClient
+	}
+
+	if redirectUri != "https://partner.example/callback" {
+		return nil, oauth.ErrInvalidRedirectUri
+	}
+
+	return &models.OAuthClient{Id: clientId, Name: "Partner"}, nil
+}
+
+func (a fakeAuthorization) Authorize(userId int64, req oauth.AuthorizeRequest) (string, error) {
+	if a.err != nil {
+		return "", a.err
+	}
+
+	return "code", nil
+}
+
+func (a fakeAuthorization) Scopes(req oauth.AuthorizeRequest) ([]string, error) {
+	return []string{"openid", "profile"}, nil
+}
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(token string) (*models.Principal, error) {
+	switch token {
+	case "user":
+		return &models.Principal{UserId: 1}, nil
+	case "delegated":
+		return &models.Principal{UserId: 1, ClientId: "other"}, nil
+	default:
+		return nil, errors.New("invalid token")
+	}
+}
+
+func authorize(handler http.HandlerFunc, query, cookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query, nil)
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: cookieName, Value: cookie})
+	}
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func location(t *testing.T, rr *httptest.ResponseRecorder) (string, url.Values) {
+	t.Helper()
+
+	parsed, err := url.Parse(rr.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsed.Query()
+	parsed.RawQuery = ""
+
+	return parsed.String(), query
+}
+
+func TestAuthorize(t *testing.T) {
+	const query = "response_type=code&client_id=partner&redirect_uri=https%3A%2F%2Fpartner.example%2Fcallback&state=xyz&code_challenge=abc&code_challenge_method=S256"
+
+	tests := []struct {
+		name   string
+		err    error
+		cookie string
+		target string
+		params url.Values
+	}{
+		{name: "code", cookie: "user", target: "https://partner.example/callback", params: url.Values{"code": {"code"}, "state": {"xyz"}}},
+		{name: "not signed in", target: loginUrl, params: url.Values{"return_to": {"/oauth/authorize?" + query}}},
+		{name: "invalid session", cookie: "expired", target: loginUrl, params: url.Values{"return_to": {"/oauth/authorize?" + query}}},
+		{name: "delegated token", cookie: "delegated", target: loginUrl, params: url.Values{"return_to": {"/oauth/authorize?" + query}}},
+		{name: "invalid scope", err: oauth.ErrInvalidScope, cookie: "user", target: "https://partner.example/callback", params: url.Values{"error": {"invalid_scope"}, "state": {"xyz"}}},
+		{name: "invalid challenge", err: oauth.ErrInvalidChallenge, cookie: "user", target: "https://partner.example/callback", params: url.Values{"error": {"invalid_request"}, "state": {"xyz"}}},
+		{name: "server error", err: errStorage, cookie: "user", target: "https://partner.example/callback", params: url.Values{"error": {"server_error"}, "state": {"xyz"}}},
+	}
+
+	for _, tt := range tests {
+		handler := New(slogdiscard.NewDiscardLogger(), fakeAuthorization{err: tt.err}, fakeAuthenticator{}, cookieName, loginUrl, consentUrl)
+
+		rr := authorize(handler, query, tt.cookie)
+		if rr.Code != http.StatusFound {
+			t.Errorf("%s: status = %d, body = %s, want %d", tt.name, rr.Code, rr.Body.String(), http.StatusFound)
+
+			continue
+		}
+
+		target, params := location(t, rr)
+		if target != tt.target || params.Encode() != tt.params.Encode() {
+			t.Errorf("%s: location = %s?%s, want %s?%s", tt.name, target, params.Encode(), tt.target, tt.params.Encode())
+		}
+	}
+}
+
+func TestAuthorizeRedirectsToConsent(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), fakeAuthorization{err: oauth.ErrConsentRequired}, fakeAuthenticator{}, cookieName, loginUrl, consentUrl)
+
+	rr := authorize(handler, "response_type=code&client_id=partner&redirect_uri=https%3A%2F%2Fpartner.example%2Fcallback&state=xyz", "user")
+	if rr.Code != http.StatusFound {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+
+	target, params := location(t, rr)
+	if target != consentUrl || params.Get("client_name") != "Partner" || params.Get("scope") != "openid profile" || params.Get("state") != "xyz" || params.Get("client_id") != "partner" {
+		t.Errorf("location = %s?%s, want consent page with request parameters", target, params.Encode())
+	}
+}
+
+func TestAuthorizeDoesNotRedirectToUnknownUris(t *testing.T) {
+	handler := New(slogdiscard.NewDiscardLogger(), fakeAuthorization{}, fakeAuthenticator{}, cookieName, loginUrl, consentUrl)
+
+	for _, query := range []string{
+		"response_type=code&client_id=unknown&redirect_uri=https%3A%2F%2Fpartner.example%2Fcallback",
+		"response_type=code&client_id=partner&redirect_uri=https%3A%2F%2Fevil.example%2Fcallback",
+	} {
+		rr := authorize(handler, query, "user")
+		if rr.Code != http.StatusBadRequest || rr.Header().Get("Location") != "" {
+			t.Errorf("%s: status = %d, location = %q", query, rr.Code, rr.Header().Get("Location"))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package oauthclients
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Client struct {
+	ClientId     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectUris []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Response struct {
+	resp.Response
+	Clients []Client `json:"clients"`
+}
+
+type ClientProvider interface {
+	Clients() ([]models.OAuthClient, error)
+}
+
+func New(log *slog.Logger, clientProvider ClientProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthclients.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		clients, err := clientProvider.Clients()
+		if err != nil {
+			log.Error("failed to get clients", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get clients")
+
+			return
+		}
+
+		result := make([]Client, 0, len(clients))
+		for _, client := range clients {
+			result = append(result, Client{
+				ClientId:     client.Id,
+				Name:         client.Name,
+				RedirectUris: client.RedirectUris,
+				Scopes:       client.Scopes,
+				Public:       client.Public,
+				CreatedAt:    client.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+			Clients:  result,
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package oauthconsent
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	oauthresp "auth/internal/lib/api/oauth"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+type Request struct {
+	ResponseType        string `json:"response_type" validate:"required"`
+	ClientId            string `json:"client_id" validate:"required"`
+	RedirectUri         string `json:"redirect_uri" validate:"required"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge" validate:"required"`
+	CodeChallengeMethod string `json:"code_challenge_method" validate:"required"`
+	Nonce               string `json:"nonce"`
+	Approve             bool   `json:"approve"`
+}
+
+type Response struct {
+	resp.Response
+	RedirectUri string `json:"redirect_uri,omitempty"`
+}
+
+type ConsentService interface {
+	Consent(userId int64, req oauth.AuthorizeRequest, approve bool) (string, error)
+}
+
+func New(log *slog.Logger, consentService ConsentService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthconsent.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok || principal.ClientId != "" {
+			log.Error("principal is not a first-party user")
+
+			resp.Error(w, r, http.StatusForbidden, resp.CodeAccessDenied, "access not allowed")
+
+			return
+		}
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		code, err := consentService.Consent(principal.UserId, oauth.AuthorizeRequest{
+			ResponseType:        req.ResponseType,
+			ClientId:            req.ClientId,
+			RedirectUri:         req.RedirectUri,
+			Scope:               req.Scope,
+			CodeChallenge:       req.CodeChallenge,
+			CodeChallengeMethod: req.CodeChallengeMethod,
+			Nonce:               req.Nonce,
+		}, req.Approve)
+		if errors.Is(err, oauth.ErrInvalidClient) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidClient, "invalid client")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRedirectUri, "invalid redirect uri")
+
+			return
+		}
+
+		params := url.Values{}
+		if req.State != "" {
+			params.Set("state", req.State)
+		}
+
+		if errorCode, ok := redirectErrorCode(err); ok {
+			params.Set("error", errorCode)
+		} else if err != nil {
+			log.Error("failed to save consent", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to authorize")
+
+			return
+		} else {
+			params.Set("code", code)
+		}
+
+		redirectUri, err := oauthresp.RedirectUri(req.RedirectUri, params)
+		if err != nil {
+			log.Error("failed to build redirect uri", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to authorize")
+
+			return
+		}
+
+		log.Info("consent resolved", slog.Int64("user_id", principal.UserId), slog.String("client_id", req.ClientId), slog.Bool("approve", req.Approve))
+
+		render.JSON(w, r, Response{
+			Response:    resp.Ok(),
+			RedirectUri: redirectUri,
+		})
+	}
+}
+
+func redirectErrorCode(err error) (string, bool) {
+	switch {
+	case errors.Is(err, oauth.ErrAccessDenied):
+		return oauthresp.ErrorAccessDenied, true
+	case errors.Is(err, oauth.ErrUnsupportedResponseType):
+		return oauthresp.ErrorUnsupportedResponseType, true
+	case errors.Is(err, oauth.ErrInvalidScope):
+		return oauthresp.ErrorInvalidScope, true
+	case errors.Is(err, oauth.ErrInvalidChallenge):
+		return oauthresp.ErrorInvalidRequest, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,104 @@
+package oauthintrospect
+
+import (
+	"auth/internal/domain/models"
+	oauthresp "auth/internal/lib/api/oauth"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/introspection"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	Active    bool            `json:"active"`
+	TokenType string          `json:"token_type,omitempty"`
+	Jti       string          `json:"jti,omitempty"`
+	Subject   string          `json:"sub,omitempty"`
+	UserId    int64           `json:"user_id,omitempty"`
+	UserRole  models.UserRole `json:"user_role,omitempty"`
+	ClientId  string          `json:"client_id,omitempty"`
+	Scope     string          `json:"scope,omitempty"`
+	IssuedAt  int64           `json:"iat,omitempty"`
+	ExpiresAt int64           `json:"exp,omitempty"`
+}
+
+type IntrospectionService interface {
+	Introspect(clientId, clientSecret, token, tokenTypeHint string) (*models.Introspection, error)
+}
+
+func New(log *slog.Logger, introspectionService IntrospectionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthintrospect.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		if err := r.ParseForm(); err != nil {
+			log.Error("failed to parse form", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidRequest, "failed to parse request")
+
+			return
+		}
+
+		token := r.PostForm.Get("token")
+		if token == "" {
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidRequest, "token is required")
+
+			return
+		}
+
+		clientId, clientSecret, basic := oauthresp.ClientCredentials(r)
+
+		result, err := introspectionService.Introspect(clientId, clientSecret, token, r.PostForm.Get("token_type_hint"))
+		if errors.Is(err, oauth.ErrInvalidClient) || errors.Is(err, introspection.ErrUnauthorizedClient) {
+			if basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+			}
+
+			oauthresp.RenderError(w, r, http.StatusUnauthorized, oauthresp.ErrorInvalidClient, "client authentication failed")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to introspect token", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusInternalServerError, oauthresp.ErrorServerError, "failed to introspect token")
+
+			return
+		}
+
+		if !result.Active {
+			render.JSON(w, r, Response{Active: false})
+
+			return
+		}
+
+		response := Response{
+			Active:    true,
+			TokenType: result.TokenType,
+			Jti:       result.Id,
+			Subject:   result.Subject,
+			UserId:    result.UserId,
+			UserRole:  result.Role,
+			ClientId:  result.ClientId,
+			Scope:     result.Scope,
+			ExpiresAt: result.ExpiresAt.Unix(),
+		}
+
+		if !result.IssuedAt.IsZero() {
+			response.IssuedAt = result.IssuedAt.Unix()
+		}
+
+		render.JSON(w, r, response)
+	}
+}
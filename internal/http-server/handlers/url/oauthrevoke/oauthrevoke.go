@@ -0,0 +1,66 @@
+package oauthrevoke
+
+import (
+	oauthresp "auth/internal/lib/api/oauth"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"log/slog"
+	"net/http"
+)
+
+type RevocationService interface {
+	Revoke(clientId, clientSecret, token, tokenTypeHint string) error
+}
+
+func New(log *slog.Logger, revocationService RevocationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthrevoke.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if err := r.ParseForm(); err != nil {
+			log.Error("failed to parse form", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidRequest, "failed to parse request")
+
+			return
+		}
+
+		token := r.PostForm.Get("token")
+		if token == "" {
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidRequest, "token is required")
+
+			return
+		}
+
+		clientId, clientSecret, basic := oauthresp.ClientCredentials(r)
+
+		err := revocationService.Revoke(clientId, clientSecret, token, r.PostForm.Get("token_type_hint"))
+		if errors.Is(err, oauth.ErrInvalidClient) {
+			if basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+			}
+
+			oauthresp.RenderError(w, r, http.StatusUnauthorized, oauthresp.ErrorInvalidClient, "client authentication failed")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to revoke token", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusServiceUnavailable, oauthresp.ErrorServerError, "failed to revoke token")
+
+			return
+		}
+
+		log.Info("token revoked", slog.String("client_id", clientId))
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,141 @@
+package oauthtoken
+
+import (
+	"auth/internal/domain/models"
+	oauthresp "auth/internal/lib/api/oauth"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"auth/internal/services/tokens"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+type Response struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type TokenService interface {
+	Exchange(clientId, clientSecret, code, redirectUri, codeVerifier, device, ip string) (*models.TokenPair, error)
+	Refresh(clientId, clientSecret, refreshToken string) (*models.TokenPair, error)
+	ClientCredentials(clientId, clientSecret, scope string) (*models.TokenPair, error)
+}
+
+func New(log *slog.Logger, tokenService TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.oauthtoken.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+
+		if err := r.ParseForm(); err != nil {
+			log.Error("failed to parse form", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidRequest, "failed to parse request")
+
+			return
+		}
+
+		clientId, clientSecret, basic := oauthresp.ClientCredentials(r)
+		if clientId == "" {
+			oauthresp.RenderError(w, r, http.StatusUnauthorized, oauthresp.ErrorInvalidClient, "client authentication required")
+
+			return
+		}
+
+		var (
+			tokenPair *models.TokenPair
+			err       error
+		)
+		switch grantType := r.PostForm.Get("grant_type"); grantType {
+		case GrantTypeAuthorizationCode:
+			tokenPair, err = tokenService.Exchange(
+				clientId,
+				clientSecret,
+				r.PostForm.Get("code"),
+				r.PostForm.Get("redirect_uri"),
+				r.PostForm.Get("code_verifier"),
+				r.UserAgent(),
+				clientip.FromRequest(r),
+			)
+		case GrantTypeRefreshToken:
+			tokenPair, err = tokenService.Refresh(clientId, clientSecret, r.PostForm.Get("refresh_token"))
+		case GrantTypeClientCredentials:
+			tokenPair, err = tokenService.ClientCredentials(clientId, clientSecret, r.PostForm.Get("scope"))
+		default:
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorUnsupportedGrantType, "unsupported grant type")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrInvalidClient) {
+			if basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+			}
+
+			oauthresp.RenderError(w, r, http.StatusUnauthorized, oauthresp.ErrorInvalidClient, "client authentication failed")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrUnauthorizedClient) {
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorUnauthorizedClient, "grant type is not allowed for this client")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrInvalidScope) {
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidScope, "requested scope is not allowed")
+
+			return
+		}
+
+		if errors.Is(err, oauth.ErrInvalidGrant) ||
+			errors.Is(err, tokens.ErrInvalidRefreshToken) ||
+			errors.Is(err, tokens.ErrRefreshTokenExpired) ||
+			errors.Is(err, tokens.ErrRefreshTokenReused) ||
+			errors.Is(err, tokens.EmptyRefreshTokenErr) {
+			log.Info("invalid grant", slog.String("client_id", clientId), sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusBadRequest, oauthresp.ErrorInvalidGrant, "invalid or expired grant")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to issue tokens", sl.Err(err))
+
+			oauthresp.RenderError(w, r, http.StatusInternalServerError, oauthresp.ErrorServerError, "failed to issue tokens")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			AccessToken:  tokenPair.AccessToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int64(tokenPair.ExpiresIn.Seconds()),
+			RefreshToken: tokenPair.RefreshToken,
+			IdToken:      tokenPair.IdToken,
+			Scope:        tokenPair.Scope,
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package openapidocument
+
+import (
+	"auth/internal/lib/api/openapi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+func New(log *slog.Logger, spec *openapi.Spec) http.HandlerFunc {
+	document := spec.Document()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.openapidocument.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		log.Debug("serving openapi document")
+
+		render.JSON(w, r, document)
+	}
+}
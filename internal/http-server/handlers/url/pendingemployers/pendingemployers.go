@@ -0,0 +1,69 @@
+package pendingemployers
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Employer struct {
+	UserId        int64     `json:"user_id"`
+	CompanyName   string    `json:"company_name"`
+	Inn           string    `json:"inn"`
+	Ogrn          string    `json:"ogrn"`
+	LegalAddress  string    `json:"legal_address"`
+	ContactPerson string    `json:"contact_person"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type Response struct {
+	resp.Response
+	Employers []Employer `json:"employers"`
+}
+
+type ModerationService interface {
+	Pending() ([]models.Company, error)
+}
+
+func New(log *slog.Logger, moderationService ModerationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.pendingemployers.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		companies, err := moderationService.Pending()
+		if err != nil {
+			log.Error("failed to get pending employers", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get pending employers")
+
+			return
+		}
+
+		employers := make([]Employer, 0, len(companies))
+		for _, company := range companies {
+			employers = append(employers, Employer{
+				UserId:        company.UserId,
+				CompanyName:   company.Name,
+				Inn:           company.Inn,
+				Ogrn:          company.Ogrn,
+				LegalAddress:  company.LegalAddress,
+				ContactPerson: company.ContactPerson,
+				CreatedAt:     company.CreatedAt,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response:  resp.Ok(),
+			Employers: employers,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package permissions
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Response struct {
+	resp.Response
+	Permissions []Permission `json:"permissions"`
+}
+
+type PermissionService interface {
+	Permissions() ([]models.Permission, error)
+}
+
+func New(log *slog.Logger, permissionService PermissionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.permissions.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		permissions, err := permissionService.Permissions()
+		if err != nil {
+			log.Error("failed to get permissions", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get permissions")
+
+			return
+		}
+
+		result := make([]Permission, 0, len(permissions))
+		for _, permission := range permissions {
+			result = append(result, Permission{Name: permission.Name, Description: permission.Description})
+		}
+
+		render.JSON(w, r, Response{
+			Response:    resp.Ok(),
+			Permissions: result,
+		})
+	}
+}
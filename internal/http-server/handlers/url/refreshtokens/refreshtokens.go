@@ -0,0 +1,94 @@
+package refreshtokens
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/tokens"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+	Token        string
+	RefreshToken string
+}
+
+type TokenRefresher interface {
+	Refresh(refreshToken string) (*models.TokenPair, error)
+}
+
+func New(log *slog.Logger, tokenRefresher TokenRefresher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.refreshtokens.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		pair, err := tokenRefresher.Refresh(req.RefreshToken)
+		if err != nil {
+			resp.RenderProblem(w, r, Problem(log, err))
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:     resp.Ok(),
+			Token:        pair.AccessToken,
+			RefreshToken: pair.RefreshToken,
+		})
+	}
+}
+
+func Problem(log *slog.Logger, err error) resp.Problem {
+	if errors.Is(err, tokens.ErrRefreshTokenReused) {
+		log.Warn("refresh token reuse detected, token family revoked")
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeTokenReused, "refresh token reused")
+	}
+
+	if errors.Is(err, tokens.ErrRefreshTokenExpired) {
+		log.Info("refresh token expired")
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeTokenExpired, "refresh token expired")
+	}
+
+	if errors.Is(err, tokens.ErrInvalidRefreshToken) {
+		log.Info("invalid refresh token")
+
+		return resp.NewProblem(http.StatusUnauthorized, resp.CodeInvalidToken, "invalid refresh token")
+	}
+
+	log.Error("failed to refresh tokens", sl.Err(err))
+
+	return resp.NewProblem(http.StatusInternalServerError, resp.CodeInternal, "failed to refresh tokens")
+}
@@ -1,15 +1,15 @@
 package register
 
 import (
+	"auth/internal/domain/models"
 	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
 	"auth/internal/lib/logger/sl"
 	"auth/internal/services/auth"
 	"auth/internal/storage"
 	"errors"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
-	"golang.org/x/crypto/bcrypt"
 	"log/slog"
 	"net/http"
 )
@@ -27,14 +27,18 @@ type Response struct {
 }
 
 type RegistrationService interface {
-	RegisterUser(fullName, password, phone, email string, userRole string) error
+	RegisterUser(fullName, password, phone, email string, userRole string) (int64, error)
 }
 
-func New(log *slog.Logger, registrationService RegistrationService) http.HandlerFunc {
+type EmailVerifier interface {
+	Send(user *models.User) error
+}
+
+func New(log *slog.Logger, registrationService RegistrationService, emailVerifier EmailVerifier) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.register.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
@@ -46,59 +50,59 @@ func New(log *slog.Logger, registrationService RegistrationService) http.Handler
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
-		log.Info("request body decoded", slog.Any("request", req))
-
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
 
 			return
 		}
 
-		passHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		userId, err := registrationService.RegisterUser(req.FullName, req.Password, req.Phone, req.Email, req.RoleId)
 		if err != nil {
-			log.Error("failed to generate password hash", sl.Err(err))
-
-			render.JSON(w, r, resp.Error("user register failed"))
+			resp.RenderProblem(w, r, Problem(log, err))
 
 			return
 		}
 
-		err = registrationService.RegisterUser(req.FullName, string(passHash), req.Phone, req.Email, req.RoleId)
-		if errors.Is(err, storage.ErrUserExist) {
-			log.Info("user already exists", slog.String("email", req.Email), slog.String("phone", req.Phone))
-
-			render.JSON(w, r, resp.Error("user with this email or phone already exists"))
+		log.Info("user added")
 
-			return
+		if err := emailVerifier.Send(&models.User{Id: userId, Email: req.Email}); err != nil {
+			log.Error("failed to send verification email", sl.Err(err))
 		}
 
-		if errors.Is(err, auth.ErrBadPassword) {
-			log.Info("bad password", slog.String("password", req.Password))
-
-			render.JSON(w, r, resp.Error("bad password"))
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
 
-			return
-		}
+func Problem(log *slog.Logger, err error) resp.Problem {
+	if errors.Is(err, storage.ErrUserExist) {
+		log.Info("user already exists")
 
-		if err != nil {
-			log.Error("failed to add user", sl.Err(err))
+		return resp.NewProblem(http.StatusConflict, resp.CodeUserExists, "user with this email or phone already exists")
+	}
 
-			render.JSON(w, r, resp.Error("failed to add user"))
+	if errors.Is(err, auth.ErrRoleNotAllowed) {
+		log.Info("role is not allowed")
 
-			return
-		}
+		return resp.NewProblem(http.StatusForbidden, resp.CodeRoleNotAllowed, "role is not allowed for registration")
+	}
 
-		log.Info("user added")
+	if errors.Is(err, auth.ErrBadPassword) {
+		log.Info("bad password")
 
-		render.JSON(w, r, Response{
-			Response: resp.Ok(),
-		})
+		return resp.NewProblem(http.StatusBadRequest, resp.CodeBadPassword, "bad password")
 	}
+
+	log.Error("failed to add user", sl.Err(err))
+
+	return resp.NewProblem(http.StatusInternalServerError, resp.CodeInternal, "failed to add user")
 }
@@ -0,0 +1,140 @@
+package registeremployer
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/auth"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	FullName      string `json:"full_name" validate:"required"`
+	Password      string `json:"password" validate:"required"`
+	Phone         string `json:"phone" validate:"required"`
+	Email         string `json:"email" validate:"required,email"`
+	CompanyName   string `json:"company_name" validate:"required"`
+	Inn           string `json:"inn" validate:"required,numeric"`
+	Ogrn          string `json:"ogrn" validate:"required,numeric"`
+	LegalAddress  string `json:"legal_address" validate:"required"`
+	ContactPerson string `json:"contact_person" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+	Status models.CompanyStatus `json:"status,omitempty"`
+}
+
+type RegistrationService interface {
+	RegisterEmployer(fullName, password, phone, email string, company models.Company) (int64, error)
+}
+
+type EmailVerifier interface {
+	Send(user *models.User) error
+}
+
+func New(log *slog.Logger, registrationService RegistrationService, emailVerifier EmailVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.registeremployer.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		log.Info("registering employer")
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		company := models.Company{
+			Name:          req.CompanyName,
+			Inn:           req.Inn,
+			Ogrn:          req.Ogrn,
+			LegalAddress:  req.LegalAddress,
+			ContactPerson: req.ContactPerson,
+		}
+
+		userId, err := registrationService.RegisterEmployer(req.FullName, req.Password, req.Phone, req.Email, company)
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("user already exists", slog.String("email", req.Email), slog.String("phone", req.Phone))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeUserExists, "user with this email or phone already exists")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrCompanyExist) {
+			log.Info("company already exists", slog.String("inn", req.Inn), slog.String("ogrn", req.Ogrn))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeCompanyExists, "company with this inn or ogrn already exists")
+
+			return
+		}
+
+		if errors.Is(err, auth.ErrInvalidInn) {
+			log.Info("invalid inn", slog.String("inn", req.Inn))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidInn, "invalid inn")
+
+			return
+		}
+
+		if errors.Is(err, auth.ErrInvalidOgrn) {
+			log.Info("invalid ogrn", slog.String("ogrn", req.Ogrn))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidOgrn, "invalid ogrn")
+
+			return
+		}
+
+		if errors.Is(err, auth.ErrBadPassword) {
+			log.Info("bad password")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeBadPassword, "bad password")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to add employer", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to add employer")
+
+			return
+		}
+
+		log.Info("employer added", slog.Int64("user_id", userId))
+
+		if err := emailVerifier.Send(&models.User{Id: userId, Email: req.Email}); err != nil {
+			log.Error("failed to send verification email", sl.Err(err))
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+			Status:   models.CompanyPending,
+		})
+	}
+}
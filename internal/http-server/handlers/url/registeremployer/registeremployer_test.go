@@ -0,0 +1,90 @@
+package registeremployer
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/auth"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeRegistrationService struct {
+	password string
+	err      error
+}
+
+func (s *fakeRegistrationService) RegisterEmployer(fullName, password, phone, email string, company models.Company) (int64, error) {
+	s.password = password
+
+	return 1, s.err
+}
+
+type fakeEmailVerifier struct{}
+
+func (fakeEmailVerifier) Send(user *models.User) error {
+	return nil
+}
+
+const body = `{
+	"full_name": "User",
+	"password": "correct-horse-battery-staple-42",
+	"phone": "+79990000000",
+	"email": "user@example.com",
+	"company_name": "Company",
+	"inn": "7707083893",
+	"ogrn": "1027700132195",
+	"legal_address": "Tomsk",
+	"contact_person": "User"
+}`
+
+func TestRegisterEmployerPassesPlaintextPassword(t *testing.T) {
+	service := &fakeRegistrationService{}
+	handler := New(slogdiscard.NewDiscardLogger(), service, fakeEmailVerifier{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/register-employer", strings.NewReader(body)))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	if service.password != "correct-horse-battery-staple-42" {
+		t.Errorf("service received password %q, want plaintext", service.password)
+	}
+}
+
+func TestRegisterEmployerValidationErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{err: auth.ErrBadPassword, code: "bad_password"},
+		{err: auth.ErrInvalidInn, code: "invalid_inn"},
+		{err: auth.ErrInvalidOgrn, code: "invalid_ogrn"},
+	}
+
+	for _, tt := range tests {
+		handler := New(slogdiscard.NewDiscardLogger(), &fakeRegistrationService{err: tt.err}, fakeEmailVerifier{})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/register-employer", strings.NewReader(body)))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%v: status = %d, want %d", tt.err, rec.Code, http.StatusBadRequest)
+		}
+
+		var response struct {
+			Code string `json:"code"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.Code != tt.code {
+			t.Errorf("%v: code = %s, want %s", tt.err, response.Code, tt.code)
+		}
+	}
+}
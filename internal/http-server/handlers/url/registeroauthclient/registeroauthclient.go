@@ -0,0 +1,84 @@
+package registeroauthclient
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Name         string   `json:"name" validate:"required"`
+	RedirectUris []string `json:"redirect_uris" validate:"required,min=1"`
+	Scopes       []string `json:"scopes" validate:"required,min=1"`
+	Public       bool     `json:"public"`
+}
+
+type Response struct {
+	resp.Response
+	ClientId     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type ClientRegistrar interface {
+	RegisterClient(name string, redirectUris, scopes []string, public bool) (*models.OAuthClient, string, error)
+}
+
+func New(log *slog.Logger, clientRegistrar ClientRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.registeroauthclient.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		client, secret, err := clientRegistrar.RegisterClient(req.Name, req.RedirectUris, req.Scopes, req.Public)
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRedirectUri, "invalid redirect uri")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to register client", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to register client")
+
+			return
+		}
+
+		log.Info("oauth client registered", slog.String("client_id", client.Id))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response:     resp.Ok(),
+			ClientId:     client.Id,
+			ClientSecret: secret,
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package registerserviceaccount
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+	ClientId     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type ServiceAccountRegistrar interface {
+	RegisterServiceAccount(name string) (*models.OAuthClient, string, error)
+}
+
+func New(log *slog.Logger, registrar ServiceAccountRegistrar) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.registerserviceaccount.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		client, secret, err := registrar.RegisterServiceAccount(req.Name)
+		if err != nil {
+			log.Error("failed to register service account", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to register service account")
+
+			return
+		}
+
+		log.Info("service account registered", slog.String("client_id", client.Id))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, Response{
+			Response:     resp.Ok(),
+			ClientId:     client.Id,
+			ClientSecret: secret,
+		})
+	}
+}
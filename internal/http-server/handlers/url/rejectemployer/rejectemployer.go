@@ -0,0 +1,98 @@
+package rejectemployer
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/moderation"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	ParameterUserIdName = "user_id"
+)
+
+type Request struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type ModerationService interface {
+	Reject(userId int64, reason string) error
+}
+
+func New(log *slog.Logger, moderationService ModerationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.rejectemployer.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userId, err := strconv.ParseInt(chi.URLParam(r, ParameterUserIdName), 10, 64)
+		if err != nil {
+			log.Error("invalid user id", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		var req Request
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		err = moderationService.Reject(userId, req.Reason)
+		if errors.Is(err, moderation.ErrCompanyNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeEmployerNotFound, "employer not found")
+
+			return
+		}
+
+		if errors.Is(err, moderation.ErrAlreadyReviewed) {
+			resp.Error(w, r, http.StatusConflict, resp.CodeAlreadyReviewed, "employer is already reviewed")
+
+			return
+		}
+
+		if errors.Is(err, moderation.ErrNotificationFailed) {
+			log.Error("failed to notify employer", sl.Err(err))
+		} else if err != nil {
+			log.Error("failed to reject employer", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to reject employer")
+
+			return
+		}
+
+		log.Info("employer rejected", slog.Int64("user_id", userId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
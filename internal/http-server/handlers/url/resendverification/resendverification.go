@@ -0,0 +1,112 @@
+package resendverification
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/emailverification"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type UserService interface {
+	UserByEmail(email string) (*models.User, error)
+}
+
+type EmailVerifier interface {
+	Send(user *models.User) error
+}
+
+func New(log *slog.Logger, userService UserService, emailVerifier EmailVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.resendverification.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		user, err := userService.UserByEmail(req.Email)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", slog.String("email", req.Email))
+
+			render.JSON(w, r, Response{
+				Response: resp.Ok(),
+			})
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		err = emailVerifier.Send(user)
+		if errors.Is(err, emailverification.ErrAlreadyVerified) {
+			log.Info("email already verified", slog.Int64("user_id", user.Id))
+
+			render.JSON(w, r, Response{
+				Response: resp.Ok(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, emailverification.ErrTooManyRequests) {
+			log.Info("verification requested too often", slog.Int64("user_id", user.Id))
+
+			render.JSON(w, r, Response{
+				Response: resp.Ok(),
+			})
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to send verification email", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to send email")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package resendverification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/emailverification"
+	"auth/internal/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeUsers map[string]*models.User
+
+func (u fakeUsers) UserByEmail(email string) (*models.User, error) {
+	user, ok := u[email]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+type fakeVerifier struct {
+	errs map[int64]error
+	sent []int64
+}
+
+func (v *fakeVerifier) Send(user *models.User) error {
+	if err := v.errs[user.Id]; err != nil {
+		return err
+	}
+
+	v.sent = append(v.sent, user.Id)
+
+	return nil
+}
+
+func TestResendDoesNotRevealAccounts(t *testing.T) {
+	users := fakeUsers{
+		"pending@example.com":  {Id: 1},
+		"verified@example.com": {Id: 2},
+		"limited@example.com":  {Id: 3},
+	}
+	verifier := &fakeVerifier{errs: map[int64]error{
+		2: emailverification.ErrAlreadyVerified,
+		3: emailverification.ErrTooManyRequests,
+	}}
+	handler := New(slogdiscard.NewDiscardLogger(), users, verifier)
+
+	var bodies []string
+	for _, email := range []string{"pending@example.com", "verified@example.com", "limited@example.com", "unknown@example.com"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/verify-email/resend", strings.NewReader(`{"email":"`+email+`"}`))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", email, rr.Code, http.StatusOK)
+		}
+
+		bodies = append(bodies, rr.Body.String())
+	}
+
+	for _, body := range bodies[1:] {
+		if body != bodies[0] {
+			t.Errorf("response body = %s, want %s", body, bodies[0])
+		}
+	}
+
+	if len(verifier.sent) != 1 || verifier.sent[0] != 1 {
+		t.Errorf("sent = %v, want [1]", verifier.sent)
+	}
+}
@@ -3,16 +3,17 @@ package restorepassword
 import (
 	"auth/internal/domain/models"
 	resp "auth/internal/lib/api/response"
-	"golang.org/x/crypto/bcrypt"
+	"auth/internal/services/auth"
+	"errors"
 	"log/slog"
 	"time"
 )
 
 import (
+	"auth/internal/lib/api/validation"
 	"auth/internal/lib/logger/sl"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
 	"net/http"
 )
 
@@ -34,7 +35,11 @@ type LinkProvider interface {
 	DeleteLinkById(id int64) error
 }
 
-func New(log *slog.Logger, passwordUpdater PasswordUpdater, linkProvider LinkProvider) http.HandlerFunc {
+type AccountUnlocker interface {
+	Unlock(userId int64) error
+}
+
+func New(log *slog.Logger, passwordUpdater PasswordUpdater, linkProvider LinkProvider, accountUnlocker AccountUnlocker) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.restorepassword.New"
 
@@ -48,17 +53,15 @@ func New(log *slog.Logger, passwordUpdater PasswordUpdater, linkProvider LinkPro
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
-		log.Info("request body decoded", slog.Any("request", req))
-
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
 
 			return
 		}
@@ -67,7 +70,7 @@ func New(log *slog.Logger, passwordUpdater PasswordUpdater, linkProvider LinkPro
 		if err != nil {
 			log.Error("user get error", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
 
 			return
 		}
@@ -82,29 +85,32 @@ func New(log *slog.Logger, passwordUpdater PasswordUpdater, linkProvider LinkPro
 		if time.Now().After(linkInfo.Expiration) {
 			log.Error("link is deprecated", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("link is deprecated"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeTokenExpired, "link is deprecated")
 
 			return
 		}
 
-		passHash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
-		if err != nil {
-			log.Error("failed to generate password hash", sl.Err(err))
+		err = passwordUpdater.UpdatePassword(linkInfo.UserId, req.NewPassword)
+		if errors.Is(err, auth.ErrBadPassword) {
+			log.Info("bad password")
 
-			render.JSON(w, r, resp.Error("user register failed"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeBadPassword, "bad password")
 
 			return
 		}
 
-		err = passwordUpdater.UpdatePassword(linkInfo.UserId, string(passHash))
 		if err != nil {
 			log.Error("update password error", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("update password error"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "update password error")
 
 			return
 		}
 
+		if err := accountUnlocker.Unlock(linkInfo.UserId); err != nil {
+			log.Error("failed to unlock account", sl.Err(err))
+		}
+
 		render.JSON(w, r, Response{
 			Response: resp.Ok(),
 		})
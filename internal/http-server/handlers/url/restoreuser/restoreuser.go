@@ -1,11 +1,12 @@
 package restoreuser
 
 import (
+	"auth/internal/http-server/middleware/authorization"
 	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
 	"auth/internal/lib/logger/sl"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
 	"log/slog"
 	"net/http"
 )
@@ -24,39 +25,48 @@ type UserService interface {
 
 func New(log *slog.Logger, userService UserService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		const op = "handlers.url.deleteuser.New"
+		const op = "handlers.url.restoreuser.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
+		userId, ok := authorization.TargetFromContext(r.Context())
+		if !ok {
+			log.Error("target user not found in context")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
 		var req Request
 		err := render.DecodeJSON(r.Body, &req)
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
 
 			return
 		}
 
-		err = userService.RestoreUser(req.UserId)
+		err = userService.RestoreUser(userId)
 
 		if err != nil {
 			log.Error("failed to restore user", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to restore user"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to restore user")
 
 			return
 		}
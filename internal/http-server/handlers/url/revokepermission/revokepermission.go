@@ -0,0 +1,75 @@
+package revokepermission
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/permissions"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterRoleName       = "role"
+	ParameterPermissionName = "permission"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type PermissionService interface {
+	Revoke(role models.UserRole, permission string) error
+}
+
+func New(log *slog.Logger, permissionService PermissionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.revokepermission.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		role := chi.URLParam(r, ParameterRoleName)
+		permission := chi.URLParam(r, ParameterPermissionName)
+
+		err := permissionService.Revoke(enums.RoleConvertFromString(role), permission)
+		if errors.Is(err, permissions.ErrUnknownRole) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeRoleNotFound, "unknown role")
+
+			return
+		}
+
+		if errors.Is(err, permissions.EmptyPermissionErr) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		if errors.Is(err, permissions.ErrProtectedPermission) {
+			resp.Error(w, r, http.StatusConflict, resp.CodeProtectedPermission, "permission can't be revoked from this role")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to revoke permission", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to revoke permission")
+
+			return
+		}
+
+		log.Info("permission revoked", slog.String("role", role), slog.String("permission", permission))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
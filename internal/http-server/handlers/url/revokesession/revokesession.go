@@ -0,0 +1,76 @@
+package revokesession
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/sessions"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterSessionIdName = "session_id"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type SessionService interface {
+	Revoke(userId int64, sessionId string) error
+}
+
+func New(log *slog.Logger, sessionService SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.revokesession.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		sessionId := chi.URLParam(r, ParameterSessionIdName)
+		if sessionId == "" {
+			log.Error("empty session id")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		err := sessionService.Revoke(principal.UserId, sessionId)
+		if errors.Is(err, sessions.ErrSessionNotFound) {
+			log.Info("session not found", slog.String("session_id", sessionId))
+
+			resp.Error(w, r, http.StatusNotFound, resp.CodeSessionNotFound, "session not found")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to revoke session", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to revoke session")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
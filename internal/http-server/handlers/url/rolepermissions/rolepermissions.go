@@ -0,0 +1,63 @@
+package rolepermissions
+
+import (
+	"auth/internal/domain/models"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/permissions"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterRoleName = "role"
+)
+
+type Response struct {
+	resp.Response
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+type PermissionService interface {
+	RolePermissions(role models.UserRole) ([]string, error)
+}
+
+func New(log *slog.Logger, permissionService PermissionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.rolepermissions.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		role := chi.URLParam(r, ParameterRoleName)
+
+		rolePermissions, err := permissionService.RolePermissions(enums.RoleConvertFromString(role))
+		if errors.Is(err, permissions.ErrUnknownRole) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeRoleNotFound, "unknown role")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get role permissions", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get role permissions")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:    resp.Ok(),
+			Role:        role,
+			Permissions: rolePermissions,
+		})
+	}
+}
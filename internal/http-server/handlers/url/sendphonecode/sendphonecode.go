@@ -0,0 +1,84 @@
+package sendphonecode
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/phoneverification"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Response struct {
+	resp.Response
+}
+
+type UserService interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type PhoneVerifier interface {
+	Send(user *models.User) error
+}
+
+func New(log *slog.Logger, userService UserService, phoneVerifier PhoneVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.sendphonecode.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		user, err := userService.UserByUserId(principal.UserId)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		err = phoneVerifier.Send(user)
+		if errors.Is(err, phoneverification.ErrAlreadyVerified) {
+			log.Info("phone already verified", slog.Int64("user_id", user.Id))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeAlreadyVerified, "phone is already verified")
+
+			return
+		}
+
+		if errors.Is(err, phoneverification.ErrTooManyRequests) {
+			log.Info("verification requested too often", slog.Int64("user_id", user.Id))
+
+			resp.Error(w, r, http.StatusTooManyRequests, resp.CodeRateLimited, "too many requests")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to send verification code", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to send verification code")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package serviceaccountpermissions
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/permissions"
+	"errors"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterClientIdName = "client_id"
+)
+
+type Request struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type PermissionService interface {
+	SetClientPermissions(clientId string, permissions []string) error
+}
+
+func New(log *slog.Logger, permissionService PermissionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.serviceaccountpermissions.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		clientId := chi.URLParam(r, ParameterClientIdName)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		err = permissionService.SetClientPermissions(clientId, req.Permissions)
+		if errors.Is(err, permissions.ErrClientNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeClientNotFound, "service account not found")
+
+			return
+		}
+
+		if errors.Is(err, permissions.ErrPermissionNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodePermissionNotFound, "permission not found")
+
+			return
+		}
+
+		if errors.Is(err, permissions.ErrScopedPermission) {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeScopedPermission, "self scoped permission can't be granted to service account")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to set service account permissions", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to set service account permissions")
+
+			return
+		}
+
+		log.Info("service account permissions updated", slog.String("client_id", clientId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
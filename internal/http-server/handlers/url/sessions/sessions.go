@@ -0,0 +1,77 @@
+package sessions
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type Session struct {
+	Id         string    `json:"id"`
+	Device     string    `json:"device"`
+	Ip         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+type Response struct {
+	resp.Response
+	Sessions []Session `json:"sessions"`
+}
+
+type SessionService interface {
+	Sessions(userId int64) ([]models.Session, error)
+}
+
+func New(log *slog.Logger, sessionService SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.sessions.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		userSessions, err := sessionService.Sessions(principal.UserId)
+		if err != nil {
+			log.Error("failed to get sessions", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get sessions")
+
+			return
+		}
+
+		sessions := make([]Session, 0, len(userSessions))
+		for _, session := range userSessions {
+			sessions = append(sessions, Session{
+				Id:         session.Id,
+				Device:     session.Device,
+				Ip:         session.Ip,
+				CreatedAt:  session.CreatedAt,
+				LastSeenAt: session.LastSeenAt,
+				Current:    session.Id == principal.SessionId,
+			})
+		}
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+			Sessions: sessions,
+		})
+	}
+}
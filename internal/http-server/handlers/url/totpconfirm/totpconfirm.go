@@ -0,0 +1,105 @@
+package totpconfirm
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/mfa"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MfaService interface {
+	Confirm(userId int64, code string) ([]string, error)
+}
+
+func New(log *slog.Logger, mfaService MfaService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.totpconfirm.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		recoveryCodes, err := mfaService.Confirm(principal.UserId, req.Code)
+		if errors.Is(err, mfa.ErrTotpNotEnrolled) {
+			log.Info("totp not enrolled", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeTotpNotEnrolled, "totp is not enrolled")
+
+			return
+		}
+
+		if errors.Is(err, mfa.ErrTotpAlreadyEnabled) {
+			log.Info("totp already enabled", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeTotpAlreadyEnabled, "totp is already enabled")
+
+			return
+		}
+
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			log.Info("invalid totp code", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidCode, "invalid code")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to confirm totp", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to confirm totp")
+
+			return
+		}
+
+		log.Info("totp enabled", slog.Int64("user_id", principal.UserId))
+
+		render.JSON(w, r, Response{
+			Response:      resp.Ok(),
+			RecoveryCodes: recoveryCodes,
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package totpenroll
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/mfa"
+	"encoding/base64"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/skip2/go-qrcode"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	qrCodeSize   = 256
+	qrCodePrefix = "data:image/png;base64,"
+)
+
+type Response struct {
+	resp.Response
+	Secret     string `json:"secret"`
+	OtpauthUri string `json:"otpauth_uri"`
+	QrCode     string `json:"qr_code"`
+}
+
+type UserService interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type MfaService interface {
+	Enroll(userId int64, accountName string) (string, string, error)
+}
+
+func New(log *slog.Logger, userService UserService, mfaService MfaService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.totpenroll.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		user, err := userService.UserByUserId(principal.UserId)
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		secret, uri, err := mfaService.Enroll(user.Id, user.Email)
+		if errors.Is(err, mfa.ErrTotpAlreadyEnabled) {
+			log.Info("totp already enabled", slog.Int64("user_id", user.Id))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeTotpAlreadyEnabled, "totp is already enabled")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to enroll totp", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to enroll totp")
+
+			return
+		}
+
+		qrCode, err := qrcode.Encode(uri, qrcode.Medium, qrCodeSize)
+		if err != nil {
+			log.Error("failed to generate qr code", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to enroll totp")
+
+			return
+		}
+
+		render.JSON(w, r, Response{
+			Response:   resp.Ok(),
+			Secret:     secret,
+			OtpauthUri: uri,
+			QrCode:     qrCodePrefix + base64.StdEncoding.EncodeToString(qrCode),
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package unlockuser
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	UserId int64 `json:"user_id" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type AccountUnlocker interface {
+	Unlock(userId int64) error
+}
+
+func New(log *slog.Logger, accountUnlocker AccountUnlocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.unlockuser.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		if err := accountUnlocker.Unlock(req.UserId); err != nil {
+			log.Error("failed to unlock user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to unlock user")
+
+			return
+		}
+
+		log.Info("user unlocked", slog.Int64("user_id", req.UserId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package updateme
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/handlers/url/me"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/api/etag"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/auth"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	FullName *string `json:"full_name,omitempty" validate:"omitempty,min=1"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone    *string `json:"phone,omitempty" validate:"omitempty,min=1"`
+}
+
+type UserService interface {
+	UpdateProfile(userId, version int64, update models.ProfileUpdate) (*models.User, models.ProfileChanges, error)
+}
+
+type VerificationService interface {
+	SendChanged(user *models.User, changes models.ProfileChanges)
+}
+
+func New(log *slog.Logger, userService UserService, verificationService VerificationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.updateme.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		version, err := etag.IfMatch(r)
+		if errors.Is(err, etag.ErrMissing) {
+			log.Info("if-match header is missing")
+
+			resp.Error(w, r, http.StatusPreconditionRequired, resp.CodePreconditionMissing, "If-Match header is required")
+
+			return
+		}
+
+		if err != nil {
+			log.Info("invalid if-match header", sl.Err(err))
+
+			resp.Error(w, r, http.StatusPreconditionFailed, resp.CodeVersionConflict, "user was modified, reload and retry")
+
+			return
+		}
+
+		var req Request
+		err = render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		if req.FullName == nil && req.Email == nil && req.Phone == nil {
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "nothing to update")
+
+			return
+		}
+
+		user, changes, err := userService.UpdateProfile(principal.UserId, version, models.ProfileUpdate{
+			FullName: req.FullName,
+			Email:    req.Email,
+			Phone:    req.Phone,
+		})
+		if errors.Is(err, auth.EmptyNameErr) || errors.Is(err, auth.EmptyEmailErr) || errors.Is(err, auth.EmptyPhoneErr) {
+			log.Info("invalid profile update", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, err.Error())
+
+			return
+		}
+
+		if errors.Is(err, auth.ErrVersionConflict) {
+			log.Info("version conflict", slog.Int64("user_id", principal.UserId), slog.Int64("version", version))
+
+			resp.Error(w, r, http.StatusPreconditionFailed, resp.CodeVersionConflict, "user was modified, reload and retry")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("email or phone already taken", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeUserExists, "user with this email or phone already exists")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeUserNotFound, "user not found")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to update profile", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to update profile")
+
+			return
+		}
+
+		verificationService.SendChanged(user, changes)
+
+		etag.Set(w, user.Version)
+		render.JSON(w, r, me.NewResponse(user))
+	}
+}
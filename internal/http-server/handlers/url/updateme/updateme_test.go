@@ -0,0 +1,115 @@
+package updateme
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/api/etag"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/auth"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeUsers struct {
+	changes models.ProfileChanges
+	version int64
+	calls   int
+}
+
+func (u *fakeUsers) UpdateProfile(userId, version int64, update models.ProfileUpdate) (*models.User, models.ProfileChanges, error) {
+	u.calls++
+
+	if u.version != 0 && version != 0 && version != u.version {
+		return nil, models.ProfileChanges{}, auth.ErrVersionConflict
+	}
+
+	user := &models.User{Id: userId, FullName: "User", Email: "user@example.com", Phone: "+79990000000", PendingEmail: "new@example.com", Version: u.version + 1}
+
+	return user, u.changes, nil
+}
+
+type fakeVerifications struct {
+	changes []models.ProfileChanges
+}
+
+func (v *fakeVerifications) SendChanged(user *models.User, changes models.ProfileChanges) {
+	v.changes = append(v.changes, changes)
+}
+
+func updateMe(handler http.HandlerFunc, ifMatch, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/api/auth/me", strings.NewReader(body))
+	req = req.WithContext(authentication.WithPrincipal(req.Context(), &models.Principal{UserId: 1}))
+	if ifMatch != "" {
+		req.Header.Set(etag.HeaderIfMatch, ifMatch)
+	}
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestUpdateMePassesChangesToVerification(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes models.ProfileChanges
+	}{
+		{name: "pending email resubmitted"},
+		{name: "email changed", changes: models.ProfileChanges{Email: true}},
+	}
+
+	for _, tt := range tests {
+		verifications := &fakeVerifications{}
+		handler := New(slogdiscard.NewDiscardLogger(), &fakeUsers{changes: tt.changes}, verifications)
+
+		rr := updateMe(handler, etag.Format(1), `{"email":"new@example.com"}`)
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, body = %s", tt.name, rr.Code, rr.Body.String())
+
+			continue
+		}
+
+		if len(verifications.changes) != 1 || verifications.changes[0] != tt.changes {
+			t.Errorf("%s: verification changes = %+v, want [%+v]", tt.name, verifications.changes, tt.changes)
+		}
+	}
+}
+
+func TestUpdateMeChecksIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		code    int
+		reason  string
+		calls   int
+		etag    string
+	}{
+		{name: "missing", code: http.StatusPreconditionRequired, reason: resp.CodePreconditionMissing},
+		{name: "weak tag", ifMatch: `W/"3"`, code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict},
+		{name: "malformed", ifMatch: "3", code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict},
+		{name: "stale version", ifMatch: etag.Format(2), code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict, calls: 1},
+		{name: "current version", ifMatch: etag.Format(3), code: http.StatusOK, calls: 1, etag: etag.Format(4)},
+		{name: "any version", ifMatch: "*", code: http.StatusOK, calls: 1, etag: etag.Format(4)},
+	}
+
+	for _, tt := range tests {
+		users := &fakeUsers{version: 3}
+		handler := New(slogdiscard.NewDiscardLogger(), users, &fakeVerifications{})
+
+		rr := updateMe(handler, tt.ifMatch, `{"full_name":"User"}`)
+		if rr.Code != tt.code || !strings.Contains(rr.Body.String(), tt.reason) {
+			t.Errorf("%s: status = %d, body = %s, want %d with %s", tt.name, rr.Code, rr.Body.String(), tt.code, tt.reason)
+		}
+
+		if users.calls != tt.calls {
+			t.Errorf("%s: calls = %d, want %d", tt.name, users.calls, tt.calls)
+		}
+
+		if got := rr.Header().Get(etag.HeaderETag); got != tt.etag {
+			t.Errorf("%s: ETag = %q, want %q", tt.name, got, tt.etag)
+		}
+	}
+}
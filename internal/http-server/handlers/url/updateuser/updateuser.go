@@ -1,11 +1,17 @@
 package updateuser
 
 import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/api/etag"
 	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
 	"auth/internal/lib/logger/sl"
+	"auth/internal/services/auth"
+	"auth/internal/storage"
+	"errors"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
 	"log/slog"
 	"net/http"
 )
@@ -22,48 +28,111 @@ type Response struct {
 }
 
 type UserService interface {
-	UpdateUser(newFullName, newPhone, newEmail string, userId int64) error
+	UpdateUser(newFullName, newPhone, newEmail string, userId, version int64) (*models.User, models.ProfileChanges, error)
 }
 
-func New(log *slog.Logger, userService UserService) http.HandlerFunc {
+type VerificationService interface {
+	SendChanged(user *models.User, changes models.ProfileChanges)
+}
+
+func New(log *slog.Logger, userService UserService, verificationService VerificationService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.updateuser.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
 		)
 
+		userId, ok := authorization.TargetFromContext(r.Context())
+		if !ok {
+			log.Error("target user not found in context")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		version, err := etag.IfMatch(r)
+		if errors.Is(err, etag.ErrMissing) {
+			log.Info("if-match header is missing")
+
+			resp.Error(w, r, http.StatusPreconditionRequired, resp.CodePreconditionMissing, "If-Match header is required")
+
+			return
+		}
+
+		if err != nil {
+			log.Info("invalid if-match header", sl.Err(err))
+
+			resp.Error(w, r, http.StatusPreconditionFailed, resp.CodeVersionConflict, "user was modified, reload and retry")
+
+			return
+		}
+
 		var req Request
-		err := render.DecodeJSON(r.Body, &req)
+		err = render.DecodeJSON(r.Body, &req)
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to decode request"))
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
 
 			return
 		}
 
 		log.Info("request body decoded", slog.Any("request", req))
 
-		if err := validator.New().Struct(req); err != nil {
+		if err := validation.Struct(req); err != nil {
 			log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		user, changes, err := userService.UpdateUser(req.NewFullName, req.NewPhone, req.NewEmail, userId, version)
+		if errors.Is(err, auth.EmptyNameErr) || errors.Is(err, auth.EmptyEmailErr) || errors.Is(err, auth.EmptyPhoneErr) {
+			log.Info("invalid user update", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, err.Error())
+
+			return
+		}
+
+		if errors.Is(err, auth.ErrVersionConflict) {
+			log.Info("version conflict", slog.Int64("user_id", userId), slog.Int64("version", version))
+
+			resp.Error(w, r, http.StatusPreconditionFailed, resp.CodeVersionConflict, "user was modified, reload and retry")
 
 			return
 		}
 
-		err = userService.UpdateUser(req.NewFullName, req.NewPhone, req.NewEmail, req.UserId)
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("email or phone already taken", slog.Int64("user_id", userId))
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeUserExists, "user with this email or phone already exists")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeUserNotFound, "user not found")
+
+			return
+		}
 
 		if err != nil {
 			log.Error("failed to update user", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to update user"))
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to update user")
 
 			return
 		}
 
+		verificationService.SendChanged(user, changes)
+
+		etag.Set(w, user.Version)
+
 		render.JSON(w, r, Response{
 			Response: resp.Ok(),
 		})
@@ -0,0 +1,155 @@
+package updateuser
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/api/etag"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/auth"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeUsers struct {
+	changes models.ProfileChanges
+	version int64
+	calls   int
+	err     error
+}
+
+func (u *fakeUsers) UpdateUser(newFullName, newPhone, newEmail string, userId, version int64) (*models.User, models.ProfileChanges, error) {
+	u.calls++
+
+	if u.err != nil {
+		return nil, models.ProfileChanges{}, u.err
+	}
+
+	if u.version != 0 && version != 0 && version != u.version {
+		return nil, models.ProfileChanges{}, auth.ErrVersionConflict
+	}
+
+	user := &models.User{Id: userId, FullName: newFullName, Email: "user@example.com", Phone: "+79990000000", Version: u.version + 1}
+	if u.changes.Email {
+		user.PendingEmail = newEmail
+	}
+
+	if u.changes.Phone {
+		user.PendingPhone = newPhone
+	}
+
+	return user, u.changes, nil
+}
+
+type fakeVerifications struct {
+	changes []models.ProfileChanges
+}
+
+func (v *fakeVerifications) SendChanged(user *models.User, changes models.ProfileChanges) {
+	v.changes = append(v.changes, changes)
+}
+
+func updateUser(handler http.HandlerFunc, ifMatch, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/update-user", strings.NewReader(body))
+	req = req.WithContext(authorization.WithTarget(req.Context(), 1))
+	if ifMatch != "" {
+		req.Header.Set(etag.HeaderIfMatch, ifMatch)
+	}
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestUpdateUserPassesChangesToVerification(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes models.ProfileChanges
+	}{
+		{name: "nothing changed"},
+		{name: "full name only", changes: models.ProfileChanges{FullName: true}},
+		{name: "email", changes: models.ProfileChanges{Email: true}},
+		{name: "phone", changes: models.ProfileChanges{Phone: true}},
+		{name: "email and phone", changes: models.ProfileChanges{Email: true, Phone: true}},
+	}
+
+	for _, tt := range tests {
+		verifications := &fakeVerifications{}
+		handler := New(slogdiscard.NewDiscardLogger(), &fakeUsers{changes: tt.changes}, verifications)
+
+		rr := updateUser(handler, etag.Format(1), `{"new_full_name":"User","new_phone":"+79990000001","new_email":"new@example.com","user_id":1}`)
+		if rr.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, body = %s", tt.name, rr.Code, rr.Body.String())
+
+			continue
+		}
+
+		if len(verifications.changes) != 1 || verifications.changes[0] != tt.changes {
+			t.Errorf("%s: verification changes = %+v, want [%+v]", tt.name, verifications.changes, tt.changes)
+		}
+	}
+}
+
+func TestUpdateUserRejectsEmptyFields(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "empty name", err: auth.EmptyNameErr},
+		{name: "empty email", err: auth.EmptyEmailErr},
+		{name: "empty phone", err: auth.EmptyPhoneErr},
+	}
+
+	for _, tt := range tests {
+		verifications := &fakeVerifications{}
+		handler := New(slogdiscard.NewDiscardLogger(), &fakeUsers{err: tt.err}, verifications)
+
+		rr := updateUser(handler, etag.Format(1), `{"new_full_name":"   ","new_phone":"+79990000001","new_email":"new@example.com","user_id":1}`)
+		if rr.Code != http.StatusBadRequest || !strings.Contains(rr.Body.String(), resp.CodeInvalidRequest) {
+			t.Errorf("%s: status = %d, body = %s, want %d with %s", tt.name, rr.Code, rr.Body.String(), http.StatusBadRequest, resp.CodeInvalidRequest)
+		}
+
+		if len(verifications.changes) != 0 {
+			t.Errorf("%s: verification sent for rejected update", tt.name)
+		}
+	}
+}
+
+func TestUpdateUserChecksIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		code    int
+		reason  string
+		calls   int
+		etag    string
+	}{
+		{name: "missing", code: http.StatusPreconditionRequired, reason: resp.CodePreconditionMissing},
+		{name: "weak tag", ifMatch: `W/"3"`, code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict},
+		{name: "malformed", ifMatch: "3", code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict},
+		{name: "stale version", ifMatch: etag.Format(2), code: http.StatusPreconditionFailed, reason: resp.CodeVersionConflict, calls: 1},
+		{name: "current version", ifMatch: etag.Format(3), code: http.StatusOK, calls: 1, etag: etag.Format(4)},
+		{name: "any version", ifMatch: "*", code: http.StatusOK, calls: 1, etag: etag.Format(4)},
+	}
+
+	for _, tt := range tests {
+		users := &fakeUsers{version: 3}
+		handler := New(slogdiscard.NewDiscardLogger(), users, &fakeVerifications{})
+
+		rr := updateUser(handler, tt.ifMatch, `{"new_full_name":"User","new_phone":"+79990000001","new_email":"new@example.com","user_id":1}`)
+		if rr.Code != tt.code || !strings.Contains(rr.Body.String(), tt.reason) {
+			t.Errorf("%s: status = %d, body = %s, want %d with %s", tt.name, rr.Code, rr.Body.String(), tt.code, tt.reason)
+		}
+
+		if users.calls != tt.calls {
+			t.Errorf("%s: calls = %d, want %d", tt.name, users.calls, tt.calls)
+		}
+
+		if got := rr.Header().Get(etag.HeaderETag); got != tt.etag {
+			t.Errorf("%s: ETag = %q, want %q", tt.name, got, tt.etag)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/api/etag"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	ParameterUserIdName = "user_id"
+)
+
+type Response struct {
+	resp.Response
+	Id            int64  `json:"id,omitempty"`
+	FullName      string `json:"full_name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	PhoneVerified bool   `json:"phone_verified,omitempty"`
+	Role          string `json:"user_role,omitempty"`
+}
+
+type UserService interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+func New(log *slog.Logger, userService UserService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.user.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userId, ok := authorization.TargetFromContext(r.Context())
+		if !ok {
+			log.Error("target user not found in context")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
+
+			return
+		}
+
+		user, err := userService.UserByUserId(userId)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			resp.Error(w, r, http.StatusNotFound, resp.CodeUserNotFound, "user not found")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to get user")
+
+			return
+		}
+
+		etag.Set(w, user.Version)
+		render.JSON(w, r, Response{
+			Response:      resp.Ok(),
+			Id:            user.Id,
+			FullName:      user.FullName,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			Phone:         user.Phone,
+			PhoneVerified: user.PhoneVerifiedAt != nil,
+			Role:          user.RoleString,
+		})
+	}
+}
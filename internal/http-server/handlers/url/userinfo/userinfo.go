@@ -0,0 +1,58 @@
+package userinfo
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/oauth"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type UserInfoProvider interface {
+	UserInfo(principal *models.Principal) (map[string]any, error)
+}
+
+func New(log *slog.Logger, authenticator authentication.Authenticator, userInfoProvider UserInfoProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.userinfo.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, err := authentication.Authenticate(r, authenticator)
+		if err != nil {
+			log.Info("invalid access token", sl.Err(err))
+
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		userInfo, err := userInfoProvider.UserInfo(principal)
+		if errors.Is(err, oauth.ErrInsufficientScope) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to get user info", sl.Err(err))
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		render.JSON(w, r, userInfo)
+	}
+}
@@ -0,0 +1,114 @@
+package verify
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/logger/sl"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	HeaderUserId          = "X-User-Id"
+	HeaderUserRole        = "X-User-Role"
+	HeaderClientId        = "X-Client-Id"
+	HeaderForwardedUri    = "X-Forwarded-Uri"
+	HeaderForwardedMethod = "X-Forwarded-Method"
+	HeaderOriginalUri     = "X-Original-URI"
+	HeaderOriginalMethod  = "X-Original-Method"
+)
+
+func New(log *slog.Logger, authenticator authentication.Authenticator, cookieName string, policy *authorization.Policy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.verify.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		method, path, ok := originalRequest(r)
+		if !ok {
+			log.Info("original request uri is missing or invalid", sl.Err(authorization.ErrInvalidPath))
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		log = log.With(slog.String("method", method), slog.String("path", path))
+
+		principal, err := authentication.AuthenticateWithCookie(r, authenticator, cookieName)
+		if err != nil {
+			log.Debug("request is not authenticated", sl.Err(err))
+
+			principal = nil
+		}
+
+		principal, err = policy.Verify(method, path, principal)
+		if errors.Is(err, authorization.ErrUnauthenticated) {
+			log.Info("authentication required")
+
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if err != nil {
+			log.Info("access not allowed", sl.Err(err))
+
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if principal == nil {
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		if principal.UserId != 0 {
+			w.Header().Set(HeaderUserId, strconv.FormatInt(principal.UserId, 10))
+		}
+
+		if role := enums.RoleConvertToString(principal.Role); role != "" {
+			w.Header().Set(HeaderUserRole, role)
+		}
+
+		if principal.ClientId != "" {
+			w.Header().Set(HeaderClientId, principal.ClientId)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func originalRequest(r *http.Request) (string, string, bool) {
+	method := r.Header.Get(HeaderForwardedMethod)
+	if method == "" {
+		method = r.Header.Get(HeaderOriginalMethod)
+	}
+
+	if method == "" {
+		method = r.Method
+	}
+
+	uri := r.Header.Get(HeaderForwardedUri)
+	if uri == "" {
+		uri = r.Header.Get(HeaderOriginalUri)
+	}
+
+	parsed, err := url.ParseRequestURI(uri)
+	if err != nil || parsed.Path == "" {
+		return method, "", false
+	}
+
+	return method, parsed.Path, true
+}
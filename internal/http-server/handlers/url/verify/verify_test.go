@@ -0,0 +1,87 @@
+package verify
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(token string) (*models.Principal, error) {
+	switch token {
+	case "employer":
+		return &models.Principal{UserId: 2, Role: models.Employer}, nil
+	case "delegated":
+		return &models.Principal{UserId: 2, Role: models.Employer, ClientId: "partner", Scope: "openid"}, nil
+	default:
+		return nil, authentication.ErrTokenNotFound
+	}
+}
+
+func newHandler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	public, err := authorization.NewRule("/api/vacancies/**", []string{"GET"}, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employers, err := authorization.NewRule("/api/vacancies/**", []string{"POST"}, []string{"employer"}, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := authorization.NewPolicy([]authorization.Rule{public, employers}, authorization.UnmatchedDeny)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(slogdiscard.NewDiscardLogger(), fakeAuthenticator{}, "access_token", policy)
+}
+
+func TestVerify(t *testing.T) {
+	handler := newHandler(t)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		code    int
+		userId  string
+	}{
+		{name: "missing uri", headers: map[string]string{HeaderForwardedMethod: "GET"}, code: http.StatusBadRequest},
+		{name: "invalid uri", headers: map[string]string{HeaderForwardedMethod: "GET", HeaderForwardedUri: "vacancies"}, code: http.StatusBadRequest},
+		{name: "public", headers: map[string]string{HeaderForwardedMethod: "GET", HeaderForwardedUri: "/api/vacancies/1?page=2"}, code: http.StatusOK},
+		{name: "anonymous", headers: map[string]string{HeaderOriginalMethod: "POST", HeaderOriginalUri: "/api/vacancies"}, code: http.StatusUnauthorized},
+		{name: "unmatched", headers: map[string]string{HeaderForwardedMethod: "GET", HeaderForwardedUri: "/api/internal", "Authorization": "Bearer employer"}, code: http.StatusForbidden},
+		{name: "employer", headers: map[string]string{HeaderForwardedMethod: "POST", HeaderForwardedUri: "/api/vacancies", "Authorization": "Bearer employer"}, code: http.StatusOK, userId: "2"},
+		{name: "delegated on role rule", headers: map[string]string{HeaderForwardedMethod: "POST", HeaderForwardedUri: "/api/vacancies", "Authorization": "Bearer delegated"}, code: http.StatusForbidden},
+		{name: "delegated on public rule", headers: map[string]string{HeaderForwardedMethod: "GET", HeaderForwardedUri: "/api/vacancies/1", "Authorization": "Bearer delegated"}, code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/auth/verify", nil)
+		for name, value := range tt.headers {
+			req.Header.Set(name, value)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.code)
+		}
+
+		if got := rec.Header().Get(HeaderUserId); got != tt.userId {
+			t.Errorf("%s: %s = %q, want %q", tt.name, HeaderUserId, got, tt.userId)
+		}
+
+		if tt.userId == "" && (rec.Header().Get(HeaderUserRole) != "" || rec.Header().Get(HeaderClientId) != "") {
+			t.Errorf("%s: identity headers = %v, want none", tt.name, rec.Header())
+		}
+	}
+}
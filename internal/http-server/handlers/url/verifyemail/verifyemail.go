@@ -0,0 +1,94 @@
+package verifyemail
+
+import (
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/emailverification"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type EmailVerifier interface {
+	Confirm(token string) error
+}
+
+func New(log *slog.Logger, emailVerifier EmailVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.verifyemail.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		err = emailVerifier.Confirm(req.Token)
+		if errors.Is(err, emailverification.ErrInvalidToken) {
+			log.Info("invalid verification token")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidToken, "invalid verification token")
+
+			return
+		}
+
+		if errors.Is(err, emailverification.ErrTokenExpired) {
+			log.Info("verification token expired")
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeTokenExpired, "verification token expired")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("email is already taken")
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeUserExists, "user with this email already exists")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to verify email", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to verify email")
+
+			return
+		}
+
+		log.Info("email verified")
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package verifyphone
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/api/validation"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/phoneverification"
+	"auth/internal/storage"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type Response struct {
+	resp.Response
+}
+
+type PhoneVerifier interface {
+	Confirm(userId int64, code string) error
+}
+
+func New(log *slog.Logger, phoneVerifier PhoneVerifier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.verifyphone.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		principal, ok := authentication.PrincipalFromContext(r.Context())
+		if !ok {
+			log.Error("principal not found in context")
+
+			resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
+
+			return
+		}
+
+		var req Request
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+			return
+		}
+
+		if err := validation.Struct(req); err != nil {
+			log.Error("invalid request", sl.Err(err))
+
+			resp.ValidationError(w, r, err)
+
+			return
+		}
+
+		err = phoneVerifier.Confirm(principal.UserId, req.Code)
+		if errors.Is(err, phoneverification.ErrCodeNotFound) || errors.Is(err, phoneverification.ErrInvalidCode) {
+			log.Info("invalid verification code", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidCode, "invalid verification code")
+
+			return
+		}
+
+		if errors.Is(err, phoneverification.ErrCodeExpired) {
+			log.Info("verification code expired", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusBadRequest, resp.CodeTokenExpired, "verification code expired")
+
+			return
+		}
+
+		if errors.Is(err, phoneverification.ErrTooManyAttempts) {
+			log.Info("too many verification attempts", slog.Int64("user_id", principal.UserId))
+
+			resp.Error(w, r, http.StatusTooManyRequests, resp.CodeTooManyAttempts, "too many attempts")
+
+			return
+		}
+
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("phone is already taken")
+
+			resp.Error(w, r, http.StatusConflict, resp.CodeUserExists, "user with this phone already exists")
+
+			return
+		}
+
+		if err != nil {
+			log.Error("failed to verify phone", sl.Err(err))
+
+			resp.Error(w, r, http.StatusInternalServerError, resp.CodeInternal, "failed to verify phone")
+
+			return
+		}
+
+		log.Info("phone verified", slog.Int64("user_id", principal.UserId))
+
+		render.JSON(w, r, Response{
+			Response: resp.Ok(),
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package authentication
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"github.com/go-chi/chi/middleware"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	BearerSchema               = "Bearer "
+	ParameterAuthorizationName = "Authorization"
+)
+
+var (
+	ErrTokenNotFound  = errors.New("token doesn't exist")
+	ErrDelegatedToken = errors.New("token is issued to a third-party client")
+	ErrNotUser        = errors.New("principal is not a user")
+)
+
+type ctxKey struct{}
+
+type Authenticator interface {
+	Authenticate(token string) (*models.Principal, error)
+}
+
+func New(log *slog.Logger, authenticator Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.authentication.New"
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			principal, err := Authenticate(r, authenticator)
+			if err != nil {
+				log.Error("failed to authenticate", sl.Err(err))
+
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				response.Error(w, r, http.StatusUnauthorized, response.CodeUnauthorized, "failed to authentication")
+
+				return
+			}
+
+			if principal.Delegated() {
+				log.Error("failed to authenticate", slog.String("client_id", principal.ClientId), sl.Err(ErrDelegatedToken))
+
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+				response.Error(w, r, http.StatusForbidden, response.CodeAccessDenied, "access not allowed")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func RequireUser(log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.authentication.RequireUser"
+
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok || principal.ServiceAccount || principal.UserId == 0 {
+				log.With(
+					slog.String("op", op),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+				).Error("access not allowed", sl.Err(ErrNotUser))
+
+				response.Error(w, r, http.StatusForbidden, response.CodeAccessDenied, "access not allowed")
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func Authenticate(r *http.Request, authenticator Authenticator) (*models.Principal, error) {
+	tokenString, ok := BearerToken(r)
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return authenticator.Authenticate(tokenString)
+}
+
+func AuthenticateWithCookie(r *http.Request, authenticator Authenticator, cookieName string) (*models.Principal, error) {
+	tokenString, ok := BearerToken(r)
+	if !ok {
+		tokenString, ok = CookieToken(r, cookieName)
+	}
+
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return authenticator.Authenticate(tokenString)
+}
+
+func BearerToken(r *http.Request) (string, bool) {
+	tokenString, ok := strings.CutPrefix(r.Header.Get(ParameterAuthorizationName), BearerSchema)
+	if !ok || tokenString == "" {
+		return "", false
+	}
+
+	return tokenString, true
+}
+
+func CookieToken(r *http.Request, cookieName string) (string, bool) {
+	if cookieName == "" {
+		return "", false
+	}
+
+	cookie, err := r.Cookie(cookieName)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+
+	return cookie.Value, true
+}
+
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*models.Principal, bool) {
+	principal, ok := ctx.Value(ctxKey{}).(*models.Principal)
+
+	return principal, ok
+}
@@ -0,0 +1,92 @@
+package authentication_test
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/http-server/middleware/authorization"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeAuthenticator struct {
+	principals map[string]*models.Principal
+}
+
+func (a fakeAuthenticator) Authenticate(token string) (*models.Principal, error) {
+	principal, ok := a.principals[token]
+	if !ok {
+		return nil, authentication.ErrTokenNotFound
+	}
+
+	return principal, nil
+}
+
+func TestDelegatedTokenIsRejectedOnFirstPartyRoutes(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	authenticator := fakeAuthenticator{principals: map[string]*models.Principal{
+		"first-party": {UserId: 1, Role: models.Admin, SessionId: "session", Permissions: []string{models.PermissionUsersUpdateAny}},
+		"client":      {UserId: 1, Role: models.Admin, SessionId: "session", ClientId: "partner", Scope: "openid"},
+	}}
+
+	router := chi.NewRouter()
+	router.Use(authentication.New(log, authenticator))
+	router.With(authorization.New(log, authorization.FromBody("user_id"), models.PermissionUsersUpdateSelf, models.PermissionUsersUpdateAny)).
+		Put("/api/auth/update-user", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		token string
+		code  int
+	}{
+		{token: "first-party", code: http.StatusOK},
+		{token: "client", code: http.StatusForbidden},
+		{token: "unknown", code: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/api/auth/update-user", strings.NewReader(`{"user_id":2}`))
+		req.Header.Set(authentication.ParameterAuthorizationName, authentication.BearerSchema+tt.token)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.token, rec.Code, tt.code)
+		}
+	}
+}
+
+func TestRequireUserRejectsServiceAccounts(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	authenticator := fakeAuthenticator{principals: map[string]*models.Principal{
+		"user":    {UserId: 1, Role: models.JobSeeker, SessionId: "session"},
+		"service": {ClientId: "worker", ServiceAccount: true},
+	}}
+
+	router := chi.NewRouter()
+	router.Use(authentication.New(log, authenticator))
+	router.With(authentication.RequireUser(log)).Post("/api/auth/logout", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		token string
+		code  int
+	}{
+		{token: "user", code: http.StatusOK},
+		{token: "service", code: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+		req.Header.Set(authentication.ParameterAuthorizationName, authentication.BearerSchema+tt.token)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.token, rec.Code, tt.code)
+		}
+	}
+}
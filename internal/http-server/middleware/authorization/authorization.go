@@ -2,151 +2,99 @@ package authorization
 
 import (
 	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
 	resp "auth/internal/lib/api/response"
 	"auth/internal/lib/logger/sl"
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"github.com/go-chi/chi/middleware"
-	"github.com/go-chi/render"
-	"github.com/go-playground/validator"
-	"github.com/golang-jwt/jwt/v5"
-	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 )
 
 const (
-	BearerSchema               = "Bearer "
-	ParameterAuthorizationName = "Authorization"
+	ScopeSelf = "self"
+	ScopeAny  = "any"
 )
 
-type Request struct {
-	UserId int64 `json:"user_id" validate:"required"`
-}
-
-type UserProvider interface {
-	UserByUserId(userId int64) (*models.User, error)
-}
-
-func New(next http.Handler, log *slog.Logger, secretKey string, userProvider UserProvider, allowedUserRole []models.UserRole) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		const op = "middleware.authorization.New"
-
-		log = log.With(
-			slog.String("op", op),
-			slog.String("request_id", middleware.GetReqID(r.Context())),
-		)
-
-		if allowedUserRole == nil || len(allowedUserRole) == 0 {
-			log.Error("empty user role")
-
-			render.JSON(w, r, resp.Error("empty user role"))
-
-			return
-		}
+var (
+	ErrAccessDenied = errors.New("access not allowed")
+)
 
-		tokenWithHeader := r.Header.Get(ParameterAuthorizationName)
-		if tokenWithHeader == "" {
-			log.Error("token doesn't exist")
+type targetKey struct{}
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+func New(log *slog.Logger, resolver ResourceResolver, permissions ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.authorization.New"
 
-			return
-		}
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
 
-		tokenString := tokenWithHeader[len(BearerSchema):]
-		if tokenString == "" {
-			log.Error("token doesn't exist")
+			principal, ok := authentication.PrincipalFromContext(r.Context())
+			if !ok {
+				log.Error("principal not found in context")
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+				resp.Error(w, r, http.StatusUnauthorized, resp.CodeUnauthorized, "failed to authentication")
 
-			return
-		}
-
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secretKey), nil
-		})
+				return
+			}
 
-		if err != nil || !token.Valid {
-			log.Error("invalid token", sl.Err(err))
+			targetId, err := resolver(r, principal)
+			if err != nil {
+				log.Error("invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+				resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "invalid request")
 
-			return
-		}
+				return
+			}
 
-		var jwtUserId int64
-		jwtUserId = int64(claims["user_id"].(float64))
+			if err := Authorize(principal, targetId, permissions); err != nil {
+				log.Error("access not allowed", slog.Int64("user_id", principal.UserId), slog.Int64("target_id", targetId), sl.Err(err))
 
-		buf, err := io.ReadAll(r.Body)
-		if err != nil {
-			log.Error("invalid token", sl.Err(err))
+				resp.Error(w, r, http.StatusForbidden, resp.CodeAccessDenied, "access not allowed")
 
-			render.JSON(w, r, resp.Error("failed to authentication"))
+				return
+			}
 
-			return
+			next.ServeHTTP(w, r.WithContext(WithTarget(r.Context(), targetId)))
 		}
 
-		copyBody := io.NopCloser(bytes.NewBuffer(buf))
-		r.Body = copyBody
-
-		var req Request
-		err = json.Unmarshal(buf, &req)
-
-		if err != nil {
-			log.Error("failed to decode request body", sl.Err(err))
-
-			render.JSON(w, r, resp.Error("failed to decode request"))
+		return http.HandlerFunc(fn)
+	}
+}
 
-			return
+func Authorize(principal *models.Principal, targetId int64, permissions []string) error {
+	for _, permission := range permissions {
+		if !principal.HasPermission(permission) {
+			continue
 		}
 
-		if err := validator.New().Struct(req); err != nil {
-			log.Error("invalid request", sl.Err(err))
-
-			render.JSON(w, r, resp.Error("invalid request"))
-
-			return
+		if !strings.HasSuffix(permission, ":"+ScopeSelf) {
+			return nil
 		}
 
-		jwtUser, err := userProvider.UserByUserId(jwtUserId)
-		if err != nil || jwtUser == nil {
-			log.Error("user from jwt not found", sl.Err(err))
-
-			render.JSON(w, r, resp.Error("invalid authorization"))
-
-			return
+		if principal.ServiceAccount {
+			continue
 		}
 
-		reqUser, err := userProvider.UserByUserId(req.UserId)
-		if err != nil || reqUser == nil {
-			log.Error("user from jwt not found", sl.Err(err))
-
-			render.JSON(w, r, resp.Error("invalid authorization"))
-
-			return
+		if targetId == principal.UserId {
+			return nil
 		}
+	}
 
-		if reqUser.Role != models.Admin {
-			var necessaryRoleFound bool
-
-			for _, role := range allowedUserRole {
-				if role == reqUser.Role {
-					necessaryRoleFound = true
-					break
-				}
-			}
-
-			if !necessaryRoleFound || reqUser.Id != jwtUser.Id {
-				log.Error("access not allowed")
+	return ErrAccessDenied
+}
 
-				render.JSON(w, r, resp.Error("access not allowed"))
+func WithTarget(ctx context.Context, targetId int64) context.Context {
+	return context.WithValue(ctx, targetKey{}, targetId)
+}
 
-				return
-			}
-		}
+func TargetFromContext(ctx context.Context) (int64, bool) {
+	targetId, ok := ctx.Value(targetKey{}).(int64)
 
-		next.ServeHTTP(w, r)
-	}
+	return targetId, ok
 }
@@ -0,0 +1,125 @@
+package authorization
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"context"
+	"errors"
+	"github.com/go-chi/chi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(t *testing.T, principal *models.Principal, resolver ResourceResolver, body string) (int, int64) {
+	t.Helper()
+
+	var targetId int64
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := TargetFromContext(r.Context())
+		if !ok {
+			t.Error("target is not stored in context")
+		}
+
+		targetId = id
+	})
+
+	handler := New(slogdiscard.NewDiscardLogger(), resolver, models.PermissionUsersUpdateSelf, models.PermissionUsersUpdateAny)(next)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/auth/update-user", strings.NewReader(body))
+	req = req.WithContext(authentication.WithPrincipal(req.Context(), principal))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code, targetId
+}
+
+func TestFromBodyRejectsCaseInsensitiveDuplicates(t *testing.T) {
+	principal := &models.Principal{UserId: 1, Permissions: []string{models.PermissionUsersUpdateSelf}}
+
+	code, _ := serve(t, principal, FromBody("user_id"), `{"user_id":1,"USER_ID":2}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
+
+func TestFromBodyStoresTarget(t *testing.T) {
+	self := &models.Principal{UserId: 1, Permissions: []string{models.PermissionUsersUpdateSelf}}
+	admin := &models.Principal{UserId: 3, Permissions: []string{models.PermissionUsersUpdateAny}}
+
+	tests := []struct {
+		name      string
+		principal *models.Principal
+		body      string
+		code      int
+		targetId  int64
+	}{
+		{name: "self", principal: self, body: `{"new_full_name":"User","user_id":1}`, code: http.StatusOK, targetId: 1},
+		{name: "other user", principal: self, body: `{"user_id":2}`, code: http.StatusForbidden},
+		{name: "any scope", principal: admin, body: `{"user_id":2}`, code: http.StatusOK, targetId: 2},
+		{name: "string id", principal: self, body: `{"user_id":"1"}`, code: http.StatusBadRequest},
+		{name: "fractional id", principal: self, body: `{"user_id":1.5}`, code: http.StatusBadRequest},
+		{name: "missing id", principal: self, body: `{"new_full_name":"User"}`, code: http.StatusBadRequest},
+		{name: "not an object", principal: self, body: `[1]`, code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, targetId := serve(t, tt.principal, FromBody("user_id"), tt.body)
+			if code != tt.code {
+				t.Fatalf("status = %d, want %d", code, tt.code)
+			}
+
+			if targetId != tt.targetId {
+				t.Errorf("target = %d, want %d", targetId, tt.targetId)
+			}
+		})
+	}
+}
+
+func TestResolvers(t *testing.T) {
+	principal := &models.Principal{UserId: 7}
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("user_id", "5")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/users/5?user_id=6", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+
+	tests := []struct {
+		name     string
+		resolver ResourceResolver
+		want     int64
+	}{
+		{name: "url param", resolver: FromURLParam("user_id"), want: 5},
+		{name: "query", resolver: FromQuery("user_id"), want: 6},
+		{name: "none", resolver: None(), want: 7},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.resolver(req, principal)
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: target = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := FromQuery("missing")(req, principal); !errors.Is(err, ErrNoTarget) {
+		t.Errorf("FromQuery(missing) error = %v, want %v", err, ErrNoTarget)
+	}
+}
+
+func TestAuthorizeSkipsSelfForServiceAccounts(t *testing.T) {
+	principal := &models.Principal{ServiceAccount: true, Permissions: []string{models.PermissionUsersUpdateSelf}}
+
+	if err := Authorize(principal, 0, []string{models.PermissionUsersUpdateSelf}); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Authorize() error = %v, want %v", err, ErrAccessDenied)
+	}
+}
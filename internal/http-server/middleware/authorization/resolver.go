@@ -0,0 +1,104 @@
+package authorization
+
+import (
+	"auth/internal/domain/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoTarget = errors.New("target resource not found in request")
+)
+
+type ResourceResolver func(r *http.Request, principal *models.Principal) (int64, error)
+
+func FromURLParam(name string) ResourceResolver {
+	return func(r *http.Request, _ *models.Principal) (int64, error) {
+		return parseId(chi.URLParam(r, name))
+	}
+}
+
+func FromQuery(name string) ResourceResolver {
+	return func(r *http.Request, _ *models.Principal) (int64, error) {
+		return parseId(r.URL.Query().Get(name))
+	}
+}
+
+func FromBody(field string) ResourceResolver {
+	return func(r *http.Request, _ *models.Principal) (int64, error) {
+		if r.Body == nil {
+			return 0, ErrNoTarget
+		}
+
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			return 0, err
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(buf))
+
+		return bodyId(buf, field)
+	}
+}
+
+func None() ResourceResolver {
+	return func(_ *http.Request, principal *models.Principal) (int64, error) {
+		return principal.UserId, nil
+	}
+}
+
+func parseId(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrNoTarget
+	}
+
+	return id, nil
+}
+
+func bodyId(body []byte, field string) (int64, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return 0, ErrNoTarget
+	}
+
+	var id int64
+	found := false
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return 0, ErrNoTarget
+		}
+
+		key, _ := token.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return 0, ErrNoTarget
+		}
+
+		if !strings.EqualFold(key, field) {
+			continue
+		}
+
+		if found || json.Unmarshal(raw, &id) != nil || id <= 0 {
+			return 0, ErrNoTarget
+		}
+
+		found = true
+	}
+
+	if !found {
+		return 0, ErrNoTarget
+	}
+
+	return id, nil
+}
@@ -0,0 +1,224 @@
+package authorization
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/enums"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	wildcardSuffix = "/**"
+
+	UnmatchedDeny          = "deny"
+	UnmatchedAuthenticated = "authenticated"
+)
+
+var (
+	ErrInvalidRule      = errors.New("invalid path rule")
+	ErrInvalidUnmatched = errors.New("invalid unmatched path action")
+	ErrInvalidPath      = errors.New("invalid request path")
+	ErrUnauthenticated  = errors.New("authentication required")
+	ErrDelegatedToken   = fmt.Errorf("%w: token is issued to a third-party client without a required scope", ErrAccessDenied)
+)
+
+type Policy struct {
+	rules     []Rule
+	unmatched string
+}
+
+type Rule struct {
+	Path        string
+	Methods     []string
+	Roles       []models.UserRole
+	Permissions []string
+	Scopes      []string
+	Public      bool
+}
+
+func NewRule(pattern string, methods []string, roles []string, permissions []string, scopes []string, public bool) (Rule, error) {
+	const op = "middleware.authorization.NewRule"
+
+	if !strings.HasPrefix(pattern, "/") {
+		return Rule{}, fmt.Errorf("%s: %w: path %q must start with /", op, ErrInvalidRule, pattern)
+	}
+
+	if _, err := path.Match(strings.TrimSuffix(pattern, wildcardSuffix), "/"); err != nil {
+		return Rule{}, fmt.Errorf("%s: %w: path %q: %w", op, ErrInvalidRule, pattern, err)
+	}
+
+	for _, permission := range permissions {
+		if strings.HasSuffix(permission, ":"+ScopeSelf) {
+			return Rule{}, fmt.Errorf("%s: %w: permission %q needs a target resource", op, ErrInvalidRule, permission)
+		}
+	}
+
+	rule := Rule{
+		Path:        pattern,
+		Permissions: permissions,
+		Scopes:      scopes,
+		Public:      public,
+	}
+
+	for _, method := range methods {
+		rule.Methods = append(rule.Methods, strings.ToUpper(method))
+	}
+
+	for _, role := range roles {
+		userRole := enums.RoleConvertFromString(role)
+		if userRole == 0 {
+			return Rule{}, fmt.Errorf("%s: %w: unknown role %q", op, ErrInvalidRule, role)
+		}
+
+		rule.Roles = append(rule.Roles, userRole)
+	}
+
+	return rule, nil
+}
+
+func MatchRule(rules []Rule, method string, requestPath string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.matches(method, requestPath) {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+func NewPolicy(rules []Rule, unmatched string) (*Policy, error) {
+	const op = "middleware.authorization.NewPolicy"
+
+	if unmatched != UnmatchedDeny && unmatched != UnmatchedAuthenticated {
+		return nil, fmt.Errorf("%s: %w: %q", op, ErrInvalidUnmatched, unmatched)
+	}
+
+	return &Policy{rules: rules, unmatched: unmatched}, nil
+}
+
+func (p *Policy) Verify(method string, requestPath string, principal *models.Principal) (*models.Principal, error) {
+	if !strings.HasPrefix(requestPath, "/") {
+		return nil, ErrInvalidPath
+	}
+
+	rule, ok := MatchRule(p.rules, method, requestPath)
+	if !ok && p.unmatched == UnmatchedDeny {
+		return nil, ErrAccessDenied
+	}
+
+	if ok && rule.Public {
+		if principal != nil && principal.Delegated() && !rule.acceptsDelegated(principal) {
+			return nil, nil
+		}
+
+		return principal, nil
+	}
+
+	if principal == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	if !ok {
+		if principal.Delegated() {
+			return nil, ErrDelegatedToken
+		}
+
+		return principal, nil
+	}
+
+	if err := rule.Allow(principal); err != nil {
+		return nil, err
+	}
+
+	return principal, nil
+}
+
+func (rule Rule) Allow(principal *models.Principal) error {
+	if principal.Delegated() && !rule.acceptsDelegated(principal) {
+		return ErrDelegatedToken
+	}
+
+	if len(rule.Roles) > 0 && !hasRole(principal, rule.Roles) {
+		return ErrAccessDenied
+	}
+
+	if len(rule.Permissions) == 0 {
+		return nil
+	}
+
+	return Authorize(principal, principal.UserId, rule.Permissions)
+}
+
+func (rule Rule) acceptsDelegated(principal *models.Principal) bool {
+	if len(rule.Scopes) == 0 {
+		return false
+	}
+
+	granted := strings.Fields(principal.Scope)
+	for _, scope := range rule.Scopes {
+		if !contains(granted, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rule Rule) matches(method string, requestPath string) bool {
+	if len(rule.Methods) > 0 && !contains(rule.Methods, method) {
+		return false
+	}
+
+	requestPath = path.Clean("/" + requestPath)
+
+	prefix, ok := strings.CutSuffix(rule.Path, wildcardSuffix)
+	if !ok {
+		matched, _ := path.Match(rule.Path, requestPath)
+
+		return matched
+	}
+
+	if prefix == "" {
+		return true
+	}
+
+	if matched, _ := path.Match(prefix, requestPath); matched {
+		return true
+	}
+
+	segments := strings.Count(prefix, "/")
+	parts := strings.SplitAfterN(requestPath, "/", segments+2)
+	if len(parts) <= segments+1 {
+		return false
+	}
+
+	matched, _ := path.Match(prefix, strings.TrimSuffix(strings.Join(parts[:segments+1], ""), "/"))
+
+	return matched
+}
+
+func hasRole(principal *models.Principal, roles []models.UserRole) bool {
+	if principal.ServiceAccount {
+		return false
+	}
+
+	for _, role := range roles {
+		if principal.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
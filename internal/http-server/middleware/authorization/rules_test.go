@@ -0,0 +1,119 @@
+package authorization
+
+import (
+	"auth/internal/domain/models"
+	"errors"
+	"testing"
+)
+
+func newPolicy(t *testing.T, unmatched string) *Policy {
+	t.Helper()
+
+	public, err := NewRule("/api/vacancies/**", []string{"get"}, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employers, err := NewRule("/api/vacancies/**", []string{"POST"}, []string{"employer"}, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moderation, err := NewRule("/api/admin/*/users", nil, nil, []string{models.PermissionUsersReadAny}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partners, err := NewRule("/api/partner/**", []string{"GET"}, []string{"employer"}, nil, []string{"vacancies:read"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := NewPolicy([]Rule{public, employers, moderation, partners}, unmatched)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return policy
+}
+
+func TestPolicyVerify(t *testing.T) {
+	jobSeeker := &models.Principal{UserId: 1, Role: models.JobSeeker}
+	employer := &models.Principal{UserId: 2, Role: models.Employer}
+	moderator := &models.Principal{ClientId: "moderation", ServiceAccount: true, Permissions: []string{models.PermissionUsersReadAny}}
+	delegated := &models.Principal{UserId: 2, Role: models.Employer, ClientId: "partner", Scope: "openid vacancies:read"}
+	unscoped := &models.Principal{UserId: 2, Role: models.Employer, ClientId: "partner", Scope: "openid"}
+
+	tests := []struct {
+		name      string
+		unmatched string
+		method    string
+		path      string
+		principal *models.Principal
+		err       error
+	}{
+		{name: "public rule", unmatched: UnmatchedDeny, method: "GET", path: "/api/vacancies/1", err: nil},
+		{name: "public prefix", unmatched: UnmatchedDeny, method: "GET", path: "/api/vacancies", err: nil},
+		{name: "role rule without principal", unmatched: UnmatchedDeny, method: "POST", path: "/api/vacancies/1", err: ErrUnauthenticated},
+		{name: "role rule wrong role", unmatched: UnmatchedDeny, method: "POST", path: "/api/vacancies/1", principal: jobSeeker, err: ErrAccessDenied},
+		{name: "role rule", unmatched: UnmatchedDeny, method: "POST", path: "/api/vacancies/1", principal: employer, err: nil},
+		{name: "permission rule", unmatched: UnmatchedDeny, method: "GET", path: "/api/admin/v1/users", principal: moderator, err: nil},
+		{name: "permission rule without permission", unmatched: UnmatchedDeny, method: "GET", path: "/api/admin/v1/users", principal: employer, err: ErrAccessDenied},
+		{name: "traversal", unmatched: UnmatchedDeny, method: "GET", path: "/api/vacancies/../admin/v1/users", principal: employer, err: ErrAccessDenied},
+		{name: "unmatched deny", unmatched: UnmatchedDeny, method: "GET", path: "/api/other", principal: employer, err: ErrAccessDenied},
+		{name: "unmatched authenticated", unmatched: UnmatchedAuthenticated, method: "GET", path: "/api/other", principal: employer, err: nil},
+		{name: "unmatched authenticated anonymous", unmatched: UnmatchedAuthenticated, method: "GET", path: "/api/other", err: ErrUnauthenticated},
+		{name: "empty path", unmatched: UnmatchedAuthenticated, method: "GET", path: "", principal: employer, err: ErrInvalidPath},
+		{name: "delegated on role rule", unmatched: UnmatchedDeny, method: "POST", path: "/api/vacancies/1", principal: delegated, err: ErrDelegatedToken},
+		{name: "delegated on unmatched authenticated", unmatched: UnmatchedAuthenticated, method: "GET", path: "/api/other", principal: delegated, err: ErrDelegatedToken},
+		{name: "delegated without rule scope", unmatched: UnmatchedDeny, method: "GET", path: "/api/partner/vacancies", principal: unscoped, err: ErrDelegatedToken},
+		{name: "delegated with rule scope", unmatched: UnmatchedDeny, method: "GET", path: "/api/partner/vacancies", principal: delegated, err: nil},
+		{name: "user on scoped rule", unmatched: UnmatchedDeny, method: "GET", path: "/api/partner/vacancies", principal: employer, err: nil},
+	}
+
+	for _, tt := range tests {
+		forwarded, err := newPolicy(t, tt.unmatched).Verify(tt.method, tt.path, tt.principal)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: Verify() error = %v, want %v", tt.name, err, tt.err)
+		}
+
+		if tt.err == nil && forwarded != tt.principal {
+			t.Errorf("%s: Verify() principal = %+v, want %+v", tt.name, forwarded, tt.principal)
+		}
+	}
+
+	if !errors.Is(ErrDelegatedToken, ErrAccessDenied) {
+		t.Errorf("ErrDelegatedToken must be an access denial")
+	}
+}
+
+func TestPolicyVerifyDropsDelegatedIdentityOnPublicRules(t *testing.T) {
+	delegated := &models.Principal{UserId: 2, Role: models.Employer, ClientId: "partner", Scope: "openid"}
+
+	forwarded, err := newPolicy(t, UnmatchedDeny).Verify("GET", "/api/vacancies/1", delegated)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if forwarded != nil {
+		t.Errorf("Verify() principal = %+v, want anonymous", forwarded)
+	}
+}
+
+func TestNewRuleRejectsInvalidConfig(t *testing.T) {
+	if _, err := NewRule("/api/users/**", nil, nil, []string{models.PermissionUsersReadSelf}, nil, false); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("NewRule(self permission) error = %v, want %v", err, ErrInvalidRule)
+	}
+
+	if _, err := NewRule("api/users", nil, nil, nil, nil, false); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("NewRule(relative path) error = %v, want %v", err, ErrInvalidRule)
+	}
+
+	if _, err := NewRule("/api/users", nil, []string{"owner"}, nil, nil, false); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("NewRule(unknown role) error = %v, want %v", err, ErrInvalidRule)
+	}
+
+	if _, err := NewPolicy(nil, "allow"); !errors.Is(err, ErrInvalidUnmatched) {
+		t.Errorf("NewPolicy(allow) error = %v, want %v", err, ErrInvalidUnmatched)
+	}
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"auth/internal/http-server/middleware/authentication"
+	"auth/internal/lib/api/oauth"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/clientip"
+	"auth/internal/lib/logger/sl"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	KeyIp       = "ip"
+	KeyUserId   = "user_id"
+	KeyClientId = "client_id"
+)
+
+type KeyFunc func(r *http.Request) string
+
+type Policy struct {
+	Name    string
+	Rate    float64
+	Burst   int
+	Key     KeyFunc
+	KeyName string
+}
+
+type Limiter interface {
+	Allow(key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+func New(log *slog.Logger, limiter Limiter, failOpen bool, policies ...Policy) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.ratelimit.New"
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			for _, policy := range policies {
+				key := policy.Key(r)
+				if key == "" {
+					continue
+				}
+
+				allowed, retryAfter, err := limiter.Allow(policy.Name+":"+key, policy.Rate, policy.Burst)
+				if err != nil {
+					log.Error("failed to check rate limit", sl.Err(err), slog.String("policy", policy.Name))
+
+					if failOpen {
+						continue
+					}
+
+					resp.Error(w, r, http.StatusServiceUnavailable, resp.CodeUnavailable, "rate limiter is unavailable")
+
+					return
+				}
+
+				if !allowed {
+					log.Info("rate limit exceeded", slog.String("policy", policy.Name), slog.Duration("retry_after", retryAfter))
+
+					problem := resp.NewProblem(http.StatusTooManyRequests, resp.CodeRateLimited, "too many requests")
+					problem.RetryAfter = retryAfter
+
+					resp.RenderProblem(w, r, problem)
+
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func NewPolicy(route string, key string, requests int, period time.Duration) (Policy, error) {
+	if requests <= 0 || period <= 0 {
+		return Policy{}, fmt.Errorf("invalid rate limit policy for %s: requests and period must be positive", route)
+	}
+
+	return Policy{
+		Name:    route + ":" + key,
+		Rate:    float64(requests) / period.Seconds(),
+		Burst:   requests,
+		Key:     KeyFuncByName(key),
+		KeyName: key,
+	}, nil
+}
+
+func KeyFuncByName(name string) KeyFunc {
+	switch name {
+	case KeyIp:
+		return ByIp
+	case KeyUserId:
+		return ByUserId
+	case KeyClientId:
+		return ByClientId
+	default:
+		return ByBodyField(name)
+	}
+}
+
+func ByIp(r *http.Request) string {
+	return clientip.FromRequest(r)
+}
+
+func ByUserId(r *http.Request) string {
+	principal, ok := authentication.PrincipalFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatInt(principal.UserId, 10)
+}
+
+func ByClientId(r *http.Request) string {
+	if err := r.ParseForm(); err != nil {
+		return ""
+	}
+
+	clientId, _, _ := oauth.ClientCredentials(r)
+
+	return clientId
+}
+
+func ByBodyField(field string) KeyFunc {
+	return func(r *http.Request) string {
+		if r.Body == nil {
+			return ""
+		}
+
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			return ""
+		}
+
+		r.Body = io.NopCloser(bytes.NewBuffer(buf))
+
+		return strings.ToLower(strings.TrimSpace(bodyField(buf, field)))
+	}
+}
+
+func bodyField(body []byte, field string) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+
+	var value string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return ""
+		}
+
+		name, _ := token.(string)
+		if !strings.EqualFold(name, field) {
+			continue
+		}
+
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = ""
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeLimiter struct {
+	err  error
+	used map[string]int
+}
+
+func (l *fakeLimiter) Allow(key string, rate float64, burst int) (bool, time.Duration, error) {
+	if l.err != nil {
+		return false, 0, l.err
+	}
+
+	l.used[key]++
+
+	return l.used[key] <= burst, time.Second, nil
+}
+
+func newHandler(t *testing.T, limiter Limiter, failOpen bool, route, key string) http.Handler {
+	t.Helper()
+
+	policy, err := NewPolicy(route, key, 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(slogdiscard.NewDiscardLogger(), limiter, failOpen, policy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		_, _ = w.Write(body)
+	}))
+}
+
+func serve(handler http.Handler, remoteAddr, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestLimitsByIp(t *testing.T) {
+	handler := newHandler(t, &fakeLimiter{used: make(map[string]int)}, false, "login", KeyIp)
+
+	if rr := serve(handler, "203.0.113.5:1000", ""); rr.Code != http.StatusOK {
+		t.Fatalf("first request: status = %d", rr.Code)
+	}
+
+	rr := serve(handler, "203.0.113.5:1001", "")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") == "" {
+		t.Errorf("second request: status = %d, retry after = %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+
+	if rr := serve(handler, "198.51.100.1:1000", ""); rr.Code != http.StatusOK {
+		t.Errorf("other ip: status = %d", rr.Code)
+	}
+}
+
+func TestRoutesUseSeparateBuckets(t *testing.T) {
+	limiter := &fakeLimiter{used: make(map[string]int)}
+	send := newHandler(t, limiter, false, "verify_phone_send", KeyIp)
+	verify := newHandler(t, limiter, false, "verify_phone", KeyIp)
+
+	if rr := serve(send, "203.0.113.5:1000", ""); rr.Code != http.StatusOK {
+		t.Fatalf("send: status = %d", rr.Code)
+	}
+
+	if rr := serve(verify, "203.0.113.5:1000", ""); rr.Code != http.StatusOK {
+		t.Errorf("verify after send: status = %d", rr.Code)
+	}
+}
+
+func TestLimitsByBodyFieldCaseInsensitive(t *testing.T) {
+	handler := newHandler(t, &fakeLimiter{used: make(map[string]int)}, false, "forgot_password", "contact_info")
+
+	rr := serve(handler, "203.0.113.5:1000", `{"contact_info":"User@Example.com"}`)
+	if rr.Code != http.StatusOK || rr.Body.String() != `{"contact_info":"User@Example.com"}` {
+		t.Fatalf("first request: status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+
+	if rr := serve(handler, "198.51.100.1:1000", `{"contact_info":"other@example.com","CONTACT_INFO":"user@example.com"}`); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("differently cased key: status = %d, want %d", rr.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestBackendFailure(t *testing.T) {
+	limiter := &fakeLimiter{err: errors.New("connection refused")}
+
+	if rr := serve(newHandler(t, limiter, false, "login", KeyIp), "203.0.113.5:1000", ""); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("fail closed: status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+
+	if rr := serve(newHandler(t, limiter, true, "login", KeyIp), "203.0.113.5:1000", ""); rr.Code != http.StatusOK {
+		t.Errorf("fail open: status = %d, want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestLimitsByClientId(t *testing.T) {
+	limiter := &fakeLimiter{used: make(map[string]int)}
+	handler := newHandler(t, limiter, false, "oauth_introspect", KeyClientId)
+
+	request := func(clientId string, basic bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("token=abc&client_id="+clientId))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if basic {
+			req.SetBasicAuth(clientId, "secret")
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	if code := request("partner", false); code != http.StatusOK {
+		t.Fatalf("first request: status = %d", code)
+	}
+
+	if code := request("partner", true); code != http.StatusTooManyRequests {
+		t.Errorf("same client with basic auth: status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+
+	if code := request("other", false); code != http.StatusOK {
+		t.Errorf("other client: status = %d, want %d", code, http.StatusOK)
+	}
+}
@@ -0,0 +1,19 @@
+package realip
+
+import (
+	"auth/internal/lib/clientip"
+	"net/http"
+	"strings"
+)
+
+func New(resolver *clientip.Resolver) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ip := resolver.Resolve(r.RemoteAddr, strings.Join(r.Header.Values(clientip.HeaderForwardedFor), ","), r.Header.Get(clientip.HeaderRealIp))
+
+			next.ServeHTTP(w, r.WithContext(clientip.NewContext(r.Context(), ip)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,82 @@
+package requestvalidation
+
+import (
+	"auth/internal/lib/api/openapi"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/sl"
+	"bytes"
+	"encoding/json"
+	"github.com/go-chi/chi/middleware"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+func New(log *slog.Logger, spec *openapi.Spec) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			const op = "middleware.requestvalidation.New"
+
+			operation, ok := spec.Lookup(r.Method, r.URL.Path)
+			if !ok || operation.RequestSchema() == nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			log := log.With(
+				slog.String("op", op),
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+			)
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = io.ReadAll(r.Body)
+				if err != nil {
+					log.Error("failed to read request body", sl.Err(err))
+
+					resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+					return
+				}
+
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+
+			var payload any
+			if err := decoder.Decode(&payload); err != nil {
+				log.Info("failed to decode request body", sl.Err(err))
+
+				resp.Error(w, r, http.StatusBadRequest, resp.CodeInvalidRequest, "failed to decode request")
+
+				return
+			}
+
+			validationErrors := operation.RequestSchema().Validate(payload)
+			if len(validationErrors) > 0 {
+				log.Info("request doesn't match specification", slog.Int("errors", len(validationErrors)))
+
+				problem := resp.NewProblem(http.StatusBadRequest, resp.CodeValidationFailed, "request doesn't match specification")
+				for _, validationError := range validationErrors {
+					problem.Errors = append(problem.Errors, resp.FieldError{
+						Field:   validationError.Field,
+						Rule:    validationError.Rule,
+						Message: validationError.Message,
+					})
+				}
+
+				resp.RenderProblem(w, r, problem)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,134 @@
+package requestvalidation
+
+import (
+	"auth/internal/lib/api/openapi"
+	resp "auth/internal/lib/api/response"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type itemRequest struct {
+	Name  string   `json:"name" validate:"required"`
+	Kind  string   `json:"kind" validate:"oneof=book film"`
+	Count int64    `json:"count"`
+	Tags  []string `json:"tags" validate:"min=1"`
+}
+
+func newHandler(t *testing.T) (http.Handler, *string) {
+	t.Helper()
+
+	spec := openapi.New("test", "1.0.0")
+	spec.Add(
+		openapi.Operation{Method: http.MethodPost, Path: "/items", Request: itemRequest{}},
+		openapi.Operation{Method: http.MethodPut, Path: "/items/{id}", Request: itemRequest{}},
+		openapi.Operation{Method: http.MethodGet, Path: "/items"},
+	)
+
+	received := new(string)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		*received = string(body)
+	})
+
+	return New(slogdiscard.NewDiscardLogger(), spec)(next), received
+}
+
+func TestRequestValidation(t *testing.T) {
+	handler, received := newHandler(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+		errors []resp.FieldError
+	}{
+		{name: "valid", method: http.MethodPost, path: "/items", body: `{"name":"a","kind":"book","count":2,"tags":["x"]}`, code: http.StatusOK},
+		{name: "path params", method: http.MethodPut, path: "/items/42", body: `{"name":"a"}`, code: http.StatusOK},
+		{name: "undocumented route", method: http.MethodPost, path: "/other", body: `not json`, code: http.StatusOK},
+		{name: "operation without body", method: http.MethodGet, path: "/items", code: http.StatusOK},
+		{name: "malformed json", method: http.MethodPost, path: "/items", body: `{"name":`, code: http.StatusBadRequest},
+		{name: "empty body", method: http.MethodPost, path: "/items", code: http.StatusBadRequest},
+		{
+			name: "missing required", method: http.MethodPost, path: "/items", body: `{"kind":"book"}`, code: http.StatusBadRequest,
+			errors: []resp.FieldError{{Field: "name", Rule: "required", Message: "field is required"}},
+		},
+		{
+			name: "unknown field", method: http.MethodPost, path: "/items", body: `{"name":"a","admin":true}`, code: http.StatusBadRequest,
+			errors: []resp.FieldError{{Field: "admin", Rule: "unknown", Message: "unknown field"}},
+		},
+		{
+			name: "wrong types", method: http.MethodPost, path: "/items", body: `{"name":1,"count":1.5,"tags":[2]}`, code: http.StatusBadRequest,
+			errors: []resp.FieldError{
+				{Field: "count", Rule: "type", Message: "must be integer"},
+				{Field: "name", Rule: "type", Message: "must be string"},
+				{Field: "tags[0]", Rule: "type", Message: "must be string"},
+			},
+		},
+		{
+			name: "enum and min items", method: http.MethodPost, path: "/items", body: `{"name":"a","kind":"song","tags":[]}`, code: http.StatusBadRequest,
+			errors: []resp.FieldError{
+				{Field: "kind", Rule: "oneof", Message: "must be one of: book film"},
+				{Field: "tags", Rule: "min", Message: "must contain at least 1 items"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		*received = ""
+
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d, body = %s", tt.name, rec.Code, tt.code, rec.Body.String())
+
+			continue
+		}
+
+		if tt.code == http.StatusOK {
+			if *received != tt.body {
+				t.Errorf("%s: next handler got body %q, want %q", tt.name, *received, tt.body)
+			}
+
+			continue
+		}
+
+		var problem resp.Problem
+		if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+			t.Fatalf("%s: decode problem: %v", tt.name, err)
+		}
+
+		wantCode := resp.CodeValidationFailed
+		if tt.errors == nil {
+			wantCode = resp.CodeInvalidRequest
+		}
+
+		if problem.Code != wantCode {
+			t.Errorf("%s: code = %q, want %q", tt.name, problem.Code, wantCode)
+		}
+
+		if len(problem.Errors) != len(tt.errors) {
+			t.Errorf("%s: errors = %+v, want %+v", tt.name, problem.Errors, tt.errors)
+
+			continue
+		}
+
+		for i := range tt.errors {
+			if problem.Errors[i] != tt.errors[i] {
+				t.Errorf("%s: errors[%d] = %+v, want %+v", tt.name, i, problem.Errors[i], tt.errors[i])
+			}
+		}
+	}
+}
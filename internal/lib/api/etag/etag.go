@@ -0,0 +1,50 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	HeaderETag    = "ETag"
+	HeaderIfMatch = "If-Match"
+
+	anyTag = "*"
+)
+
+var (
+	ErrMissing = errors.New("if-match header is missing")
+	ErrInvalid = errors.New("invalid entity tag")
+)
+
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func Set(w http.ResponseWriter, version int64) {
+	w.Header().Set(HeaderETag, Format(version))
+}
+
+func IfMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get(HeaderIfMatch))
+	if value == "" {
+		return 0, ErrMissing
+	}
+
+	if value == anyTag {
+		return 0, nil
+	}
+
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, ErrInvalid
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, ErrInvalid
+	}
+
+	return version, nil
+}
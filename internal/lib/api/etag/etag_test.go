@@ -0,0 +1,59 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	Set(rr, 42)
+
+	if got := rr.Header().Get(HeaderETag); got != `"42"` {
+		t.Errorf("ETag = %q, want %q", got, `"42"`)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		err     error
+	}{
+		{name: "missing", err: ErrMissing},
+		{name: "blank", header: "  ", err: ErrMissing},
+		{name: "any", header: "*"},
+		{name: "version", header: `"7"`, version: 7},
+		{name: "surrounding spaces", header: ` "7" `, version: 7},
+		{name: "round trip", header: Format(123), version: 123},
+		{name: "unquoted", header: "7", err: ErrInvalid},
+		{name: "weak tag", header: `W/"7"`, err: ErrInvalid},
+		{name: "single quote", header: `"`, err: ErrInvalid},
+		{name: "not a number", header: `"abc"`, err: ErrInvalid},
+		{name: "zero", header: `"0"`, err: ErrInvalid},
+		{name: "negative", header: `"-1"`, err: ErrInvalid},
+		{name: "tag list", header: `"1", "2"`, err: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(HeaderIfMatch, tt.header)
+		}
+
+		version, err := IfMatch(req)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+
+			continue
+		}
+
+		if version != tt.version {
+			t.Errorf("%s: version = %d, want %d", tt.name, version, tt.version)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"github.com/go-chi/render"
+	"net/http"
+	"net/url"
+)
+
+type Error struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorAccessDenied            = "access_denied"
+	ErrorServerError             = "server_error"
+)
+
+func NewError(code, description string) Error {
+	return Error{Error: code, ErrorDescription: description}
+}
+
+func RedirectUri(redirectUri string, params url.Values) (string, error) {
+	parsed, err := url.Parse(redirectUri)
+	if err != nil {
+		return "", err
+	}
+
+	query := parsed.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
+
+func ClientCredentials(r *http.Request) (string, string, bool) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if ok {
+		if unescaped, err := url.QueryUnescape(clientId); err == nil {
+			clientId = unescaped
+		}
+
+		if unescaped, err := url.QueryUnescape(clientSecret); err == nil {
+			clientSecret = unescaped
+		}
+
+		return clientId, clientSecret, true
+	}
+
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), false
+}
+
+func RenderError(w http.ResponseWriter, r *http.Request, status int, code, description string) {
+	render.Status(r, status)
+	render.JSON(w, r, NewError(code, description))
+}
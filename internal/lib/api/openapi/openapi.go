@@ -0,0 +1,309 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	Version = "3.1.0"
+
+	contentTypeJson    = "application/json"
+	contentTypeProblem = "application/problem+json"
+
+	securitySchemeBearer = "bearerAuth"
+	problemSchemaName    = "Problem"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+type Operation struct {
+	Method     string
+	Path       string
+	Summary    string
+	Tag        string
+	Request    any
+	Response   any
+	Status     int
+	Secured    bool
+	PathParams map[string]any
+
+	RequestHeaders  []string
+	ResponseHeaders []string
+
+	requestSchema *Schema
+	matcher       *regexp.Regexp
+}
+
+type Spec struct {
+	title      string
+	version    string
+	operations []*Operation
+}
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type PathItem struct {
+	Summary     string                `json:"summary,omitempty"`
+	OperationId string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Schema *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+func New(title, version string) *Spec {
+	return &Spec{title: title, version: version}
+}
+
+func (s *Spec) Add(operations ...Operation) {
+	for _, operation := range operations {
+		operation := operation
+
+		if operation.Status == 0 {
+			operation.Status = http.StatusOK
+		}
+
+		operation.requestSchema = SchemaOf(operation.Request)
+		operation.matcher = pathMatcher(operation.Path)
+
+		s.operations = append(s.operations, &operation)
+	}
+}
+
+func (s *Spec) HasPath(path string) bool {
+	for _, operation := range s.operations {
+		if operation.Path == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Spec) Lookup(method, path string) (*Operation, bool) {
+	for _, operation := range s.operations {
+		if operation.Method == method && operation.Path == path {
+			return operation, true
+		}
+	}
+
+	for _, operation := range s.operations {
+		if operation.Method == method && operation.matcher.MatchString(path) {
+			return operation, true
+		}
+	}
+
+	return nil, false
+}
+
+func (o *Operation) RequestSchema() *Schema {
+	return o.requestSchema
+}
+
+func (s *Spec) Document() Document {
+	doc := Document{
+		OpenAPI: Version,
+		Info:    Info{Title: s.title, Version: s.version},
+		Paths:   make(map[string]map[string]*PathItem),
+		Components: Components{
+			Schemas: map[string]*Schema{problemSchemaName: problemSchema()},
+			SecuritySchemes: map[string]SecurityScheme{
+				securitySchemeBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, operation := range s.operations {
+		item := &PathItem{
+			Summary:     operation.Summary,
+			OperationId: operationId(operation.Method, operation.Path),
+			Parameters:  parameters(operation),
+			Responses: map[string]Response{
+				"default": {
+					Description: "Error",
+					Content:     map[string]MediaType{contentTypeProblem: {Schema: ref(problemSchemaName)}},
+				},
+			},
+		}
+
+		if operation.Tag != "" {
+			item.Tags = []string{operation.Tag}
+		}
+
+		if operation.Secured {
+			item.Security = []map[string][]string{{securitySchemeBearer: {}}}
+		}
+
+		if operation.Request != nil {
+			name := schemaName(operation.Request)
+			doc.Components.Schemas[name] = operation.requestSchema
+			item.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{contentTypeJson: {Schema: ref(name)}},
+			}
+		}
+
+		success := Response{Description: http.StatusText(operation.Status)}
+		for _, name := range operation.ResponseHeaders {
+			if success.Headers == nil {
+				success.Headers = make(map[string]Header)
+			}
+
+			success.Headers[name] = Header{Schema: &Schema{Type: TypeString}}
+		}
+
+		if operation.Response != nil {
+			name := schemaName(operation.Response)
+			doc.Components.Schemas[name] = SchemaOf(operation.Response)
+			success.Content = map[string]MediaType{contentTypeJson: {Schema: ref(name)}}
+		}
+		item.Responses[strconv.Itoa(operation.Status)] = success
+
+		if doc.Paths[operation.Path] == nil {
+			doc.Paths[operation.Path] = make(map[string]*PathItem)
+		}
+		doc.Paths[operation.Path][strings.ToLower(operation.Method)] = item
+	}
+
+	return doc
+}
+
+func pathMatcher(path string) *regexp.Regexp {
+	parts := pathParamPattern.Split(path, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, `[^/]+`) + "$")
+}
+
+func parameters(operation *Operation) []Parameter {
+	var params []Parameter
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(operation.Path, -1) {
+		schema := &Schema{Type: TypeString}
+		if v, ok := operation.PathParams[match[1]]; ok {
+			schema = SchemaOf(v)
+		}
+
+		params = append(params, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+
+	for _, name := range operation.RequestHeaders {
+		params = append(params, Parameter{Name: name, In: "header", Required: true, Schema: &Schema{Type: TypeString}})
+	}
+
+	return params
+}
+
+func problemSchema() *Schema {
+	schema := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"type":     {Type: TypeString},
+			"title":    {Type: TypeString},
+			"status":   {Type: TypeInteger},
+			"detail":   {Type: TypeString},
+			"instance": {Type: TypeString},
+			"code":     {Type: TypeString},
+			"errors": {Type: TypeArray, Items: &Schema{
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"field":   {Type: TypeString},
+					"rule":    {Type: TypeString},
+					"message": {Type: TypeString},
+				},
+			}},
+		},
+		Required: []string{"type", "title", "status", "code"},
+	}
+
+	return schema
+}
+
+func schemaName(v any) string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
+
+func operationId(method, path string) string {
+	parts := strings.FieldsFunc(pathParamPattern.ReplaceAllString(path, "by-$1"), func(r rune) bool {
+		return r == '/' || r == '-' || r == '_'
+	})
+
+	id := strings.ToLower(method)
+	for _, part := range parts {
+		if part == "api" || part == "auth" {
+			continue
+		}
+
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return id
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
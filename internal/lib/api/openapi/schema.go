@@ -0,0 +1,284 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+type ValidationError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func SchemaOf(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+
+	return schemaOf(reflect.TypeOf(v))
+}
+
+func schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: TypeString, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString, Format: "byte"}
+		}
+
+		return &Schema{Type: TypeArray, Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TypeObject}
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: TypeInteger, Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: TypeInteger, Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	default:
+		return &Schema{}
+	}
+}
+
+func structSchema(t reflect.Type) *Schema {
+	closed := false
+	schema := &Schema{Type: TypeObject, Properties: map[string]*Schema{}, AdditionalProperties: &closed}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := schemaOf(field.Type)
+			for propName, prop := range embedded.Properties {
+				schema.Properties[propName] = prop
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := schemaOf(field.Type)
+		if applyRules(prop, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = prop
+	}
+
+	return schema
+}
+
+func applyRules(schema *Schema, rules string) bool {
+	required := false
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case schema.Type == TypeArray && name == "min":
+				schema.MinItems = &n
+			case schema.Type == TypeString && name == "min":
+				schema.MinLength = &n
+			case schema.Type == TypeString && name == "max":
+				schema.MaxLength = &n
+			}
+		}
+	}
+
+	return required
+}
+
+func (s *Schema) Validate(value any) []ValidationError {
+	errs := s.validate("", value)
+
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+
+	return errs
+}
+
+func (s *Schema) validate(field string, value any) []ValidationError {
+	if s == nil || s.Type == "" {
+		return nil
+	}
+
+	if value == nil {
+		return []ValidationError{{Field: field, Rule: "type", Message: "must be " + s.Type}}
+	}
+
+	switch s.Type {
+	case TypeObject:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be object"}}
+		}
+
+		return s.validateObject(field, object)
+	case TypeArray:
+		items, ok := value.([]any)
+		if !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be array"}}
+		}
+
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			return []ValidationError{{Field: field, Rule: "min", Message: "must contain at least " + strconv.Itoa(*s.MinItems) + " items"}}
+		}
+
+		var errs []ValidationError
+		for i, item := range items {
+			errs = append(errs, s.Items.validate(join(field, "["+strconv.Itoa(i)+"]"), item)...)
+		}
+
+		return errs
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be string"}}
+		}
+
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			return []ValidationError{{Field: field, Rule: "oneof", Message: "must be one of: " + strings.Join(s.Enum, " ")}}
+		}
+
+		return nil
+	case TypeInteger:
+		number, ok := value.(json.Number)
+		if !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be integer"}}
+		}
+
+		if _, err := number.Int64(); err != nil {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be integer"}}
+		}
+
+		return nil
+	case TypeNumber:
+		if _, ok := value.(json.Number); !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be number"}}
+		}
+
+		return nil
+	case TypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return []ValidationError{{Field: field, Rule: "type", Message: "must be boolean"}}
+		}
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (s *Schema) validateObject(field string, object map[string]any) []ValidationError {
+	var errs []ValidationError
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, ValidationError{Field: join(field, name), Rule: "required", Message: "field is required"})
+		}
+	}
+
+	for name, value := range object {
+		prop, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				errs = append(errs, ValidationError{Field: join(field, name), Rule: "unknown", Message: "unknown field"})
+			}
+
+			continue
+		}
+
+		errs = append(errs, prop.validate(join(field, name), value)...)
+	}
+
+	return errs
+}
+
+func join(parent, name string) string {
+	if parent == "" || strings.HasPrefix(name, "[") {
+		return parent + name
+	}
+
+	return parent + "." + name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
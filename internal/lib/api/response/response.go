@@ -1,19 +1,155 @@
 package response
 
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator"
+	"net/http"
+	"strconv"
+	"time"
+)
+
 type Response struct {
 	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+}
+
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+
+	RetryAfter time.Duration `json:"-"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 const (
-	StatusOk    = "OK"
-	StatusError = "Error"
+	StatusOk = "OK"
+
+	ContentTypeProblem = "application/problem+json"
+	problemTypeDefault = "about:blank"
+)
+
+const (
+	CodeInvalidRequest      = "invalid_request"
+	CodeValidationFailed    = "validation_failed"
+	CodeUnauthorized        = "unauthorized"
+	CodeInvalidCredentials  = "invalid_credentials"
+	CodeInvalidToken        = "invalid_token"
+	CodeTokenExpired        = "token_expired"
+	CodeTokenReused         = "token_reused"
+	CodeInvalidCode         = "invalid_code"
+	CodeAccessDenied        = "access_denied"
+	CodeRoleNotAllowed      = "role_not_allowed"
+	CodeEmailNotVerified    = "email_not_verified"
+	CodePhoneNotVerified    = "phone_not_verified"
+	CodeBadPassword         = "bad_password"
+	CodeInvalidInn          = "invalid_inn"
+	CodeInvalidOgrn         = "invalid_ogrn"
+	CodeInvalidClient       = "invalid_client"
+	CodeInvalidRedirectUri  = "invalid_redirect_uri"
+	CodeScopedPermission    = "scoped_permission"
+	CodeUserNotFound        = "user_not_found"
+	CodeEmployerNotFound    = "employer_not_found"
+	CodeSessionNotFound     = "session_not_found"
+	CodeRoleNotFound        = "role_not_found"
+	CodePermissionNotFound  = "permission_not_found"
+	CodeClientNotFound      = "client_not_found"
+	CodeUserExists          = "user_exists"
+	CodeCompanyExists       = "company_exists"
+	CodeAlreadyVerified     = "already_verified"
+	CodeAlreadyReviewed     = "already_reviewed"
+	CodeTotpAlreadyEnabled  = "totp_already_enabled"
+	CodeTotpNotEnrolled     = "totp_not_enrolled"
+	CodeProtectedPermission = "protected_permission"
+	CodeVersionConflict     = "version_conflict"
+	CodePreconditionMissing = "precondition_required"
+	CodeAccountLocked       = "account_locked"
+	CodeTooManyAttempts     = "too_many_attempts"
+	CodeRateLimited         = "rate_limited"
+	CodeUnavailable         = "service_unavailable"
+	CodeInternal            = "internal_error"
 )
 
 func Ok() Response {
 	return Response{Status: StatusOk}
 }
 
-func Error(msg string) Response {
-	return Response{Status: StatusError, Error: msg}
+func NewProblem(status int, code string, detail string) Problem {
+	return Problem{
+		Type:   problemTypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func Error(w http.ResponseWriter, r *http.Request, status int, code string, detail string) {
+	RenderProblem(w, r, NewProblem(status, code, detail))
+}
+
+func ValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(http.StatusBadRequest, CodeValidationFailed, "request validation failed")
+	problem.Errors = FieldErrors(err)
+
+	RenderProblem(w, r, problem)
+}
+
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Message: fieldMessage(fieldError),
+		})
+	}
+
+	return fieldErrors
+}
+
+func RenderProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+
+	if problem.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(problem.RetryAfter.Seconds())+1))
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(problem.Status)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "field is required"
+	case "email":
+		return "must be a valid email"
+	case "oneof":
+		return "must be one of: " + fieldError.Param()
+	case "min":
+		return "must be at least " + fieldError.Param()
+	case "max":
+		return "must be at most " + fieldError.Param()
+	default:
+		return "failed on " + fieldError.Tag() + " rule"
+	}
 }
@@ -0,0 +1,78 @@
+package rpcstatus
+
+import (
+	resp "auth/internal/lib/api/response"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"net/http"
+	"time"
+)
+
+const ErrorDomain = "auth"
+
+func Error(code codes.Code, reason string, message string) error {
+	return withDetails(code, reason, message)
+}
+
+func RetryError(code codes.Code, reason string, message string, retryAfter time.Duration) error {
+	return withDetails(code, reason, message, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+}
+
+func ValidationError(err error) error {
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldError := range resp.FieldErrors(err) {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldError.Field,
+			Description: fieldError.Message,
+		})
+	}
+
+	return withDetails(codes.InvalidArgument, resp.CodeValidationFailed, "request validation failed", badRequest)
+}
+
+func FromProblem(problem resp.Problem) error {
+	if problem.RetryAfter > 0 {
+		return RetryError(code(problem.Status), problem.Code, problem.Detail, problem.RetryAfter)
+	}
+
+	return Error(code(problem.Status), problem.Code, problem.Detail)
+}
+
+func code(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+func withDetails(code codes.Code, reason string, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}}, details...)
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"github.com/go-playground/validator"
+	"reflect"
+	"strings"
+)
+
+var validate = newValidator()
+
+func Struct(s any) error {
+	return validate.Struct(s)
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+
+		if name == "" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	return v
+}
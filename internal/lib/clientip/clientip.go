@@ -0,0 +1,122 @@
+package clientip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderForwardedFor = "X-Forwarded-For"
+	HeaderRealIp       = "X-Real-IP"
+)
+
+var (
+	ErrInvalidProxy = errors.New("trusted proxy must be an ip address or a cidr")
+)
+
+type ctxKey struct{}
+
+type Resolver struct {
+	trustedProxies []*net.IPNet
+}
+
+func New(trustedProxies []string) (*Resolver, error) {
+	resolver := &Resolver{trustedProxies: make([]*net.IPNet, 0, len(trustedProxies))}
+
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, ErrInvalidProxy
+			}
+
+			resolver.trustedProxies = append(resolver.trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, ErrInvalidProxy
+		}
+
+		resolver.trustedProxies = append(resolver.trustedProxies, network)
+	}
+
+	return resolver, nil
+}
+
+func (r *Resolver) Resolve(remoteAddr, forwardedFor, realIp string) string {
+	ip := host(remoteAddr)
+	if !r.trusted(ip) {
+		return ip
+	}
+
+	if forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				return ip
+			}
+
+			ip = hop
+			if !r.trusted(hop) {
+				return hop
+			}
+		}
+
+		return ip
+	}
+
+	if realIp = strings.TrimSpace(realIp); net.ParseIP(realIp) != nil {
+		return realIp
+	}
+
+	return ip
+}
+
+func (r *Resolver) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, network := range r.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+
+	return ip
+}
+
+func FromRequest(r *http.Request) string {
+	if ip := FromContext(r.Context()); ip != "" {
+		return ip
+	}
+
+	return host(r.RemoteAddr)
+}
+
+func host(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
@@ -0,0 +1,48 @@
+package clientip
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	resolver, err := New([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIp       string
+		want         string
+	}{
+		{name: "direct", remoteAddr: "203.0.113.5:1234", want: "203.0.113.5"},
+		{name: "untrusted peer forged header", remoteAddr: "203.0.113.5:1234", forwardedFor: "198.51.100.1", realIp: "198.51.100.2", want: "203.0.113.5"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.2:1234", forwardedFor: "198.51.100.1", want: "198.51.100.1"},
+		{name: "client forged first hop", remoteAddr: "10.0.0.2:1234", forwardedFor: "1.1.1.1, 198.51.100.1", want: "198.51.100.1"},
+		{name: "proxy chain", remoteAddr: "10.0.0.2:1234", forwardedFor: "198.51.100.1, 192.168.1.1, 10.0.0.3", want: "198.51.100.1"},
+		{name: "real ip", remoteAddr: "192.168.1.1:1234", realIp: "198.51.100.1", want: "198.51.100.1"},
+		{name: "garbage header", remoteAddr: "10.0.0.2:1234", forwardedFor: "not-an-ip", want: "10.0.0.2"},
+		{name: "all hops trusted", remoteAddr: "10.0.0.2:1234", forwardedFor: "10.0.0.4, 10.0.0.3", want: "10.0.0.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.Resolve(tt.remoteAddr, tt.forwardedFor, tt.realIp); got != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidProxy(t *testing.T) {
+	if _, err := New([]string{"proxy.local"}); !errors.Is(err, ErrInvalidProxy) {
+		t.Errorf("New(hostname) error = %v, want %v", err, ErrInvalidProxy)
+	}
+
+	if _, err := New([]string{"10.0.0.0/33"}); !errors.Is(err, ErrInvalidProxy) {
+		t.Errorf("New(bad cidr) error = %v, want %v", err, ErrInvalidProxy)
+	}
+}
@@ -0,0 +1,46 @@
+package email
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrSendFailed = errors.New("failed to send email")
+)
+
+type Sender struct {
+	client      *http.Client
+	apiKey      string
+	senderName  string
+	senderEmail string
+}
+
+func NewSender(client *http.Client, apiKey string, senderName string, senderEmail string) *Sender {
+	return &Sender{
+		client:      client,
+		apiKey:      apiKey,
+		senderName:  senderName,
+		senderEmail: senderEmail,
+	}
+}
+
+func (s *Sender) Send(recipientEmail string, subject string, body string) error {
+	req, err := FormSendEmail(s.apiKey, s.senderName, s.senderEmail, recipientEmail, subject, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ErrSendFailed
+	}
+
+	return nil
+}
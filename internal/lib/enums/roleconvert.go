@@ -3,19 +3,32 @@ package enums
 import "auth/internal/domain/models"
 
 const (
-	roleAdmin     = "admin"
-	roleJobSeeker = "jobseeker"
-	roleEmployer  = "employer"
+	RoleAdmin     = "admin"
+	RoleJobSeeker = "jobseeker"
+	RoleEmployer  = "employer"
 )
 
 func RoleConvertFromString(role string) models.UserRole {
-	if role == roleAdmin {
+	if role == RoleAdmin {
 		return models.Admin
-	} else if role == roleJobSeeker {
+	} else if role == RoleJobSeeker {
 		return models.JobSeeker
-	} else if role == roleEmployer {
+	} else if role == RoleEmployer {
 		return models.Employer
 	} else {
 		return 0
 	}
 }
+
+func RoleConvertToString(role models.UserRole) string {
+	switch role {
+	case models.Admin:
+		return RoleAdmin
+	case models.JobSeeker:
+		return RoleJobSeeker
+	case models.Employer:
+		return RoleEmployer
+	default:
+		return ""
+	}
+}
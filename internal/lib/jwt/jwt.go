@@ -2,22 +2,281 @@ package jwt
 
 import (
 	"auth/internal/domain/models"
+	"auth/internal/lib/enums"
+	"crypto"
+	"errors"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"time"
 )
 
-func NewToken(user models.User, secretKey string, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["user_id"] = user.Id
-	claims["user_role"] = user.Role
-	claims["exp"] = time.Now().Add(duration).Unix()
+	typeMfa     = "mfa"
+	typeService = "service"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrKeyNotFound  = errors.New("signing key not found")
+)
+
+type Key struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+type KeyProvider interface {
+	SigningKey() (*Key, error)
+	VerificationKey(kid string) (*Key, error)
+}
+
+type Claims struct {
+	UserId         int64
+	Role           models.UserRole
+	SessionId      string
+	EmployerStatus models.CompanyStatus
+	ClientId       string
+	Scope          string
+	ServiceAccount bool
+	Id             string
+	IssuedAt       time.Time
+	ExpiresAt      time.Time
+}
+
+func NewToken(user models.User, sessionId string, permissions []string, keys KeyProvider, duration time.Duration) (string, error) {
+	return sign(userClaims(user, sessionId, permissions, duration), keys)
+}
+
+func NewClientToken(user models.User, sessionId, clientId, scope string, keys KeyProvider, duration time.Duration) (string, error) {
+	claims := userClaims(user, sessionId, nil, duration)
+	claims["client_id"] = clientId
+	claims["scope"] = scope
+
+	return sign(claims, keys)
+}
+
+func NewMfaToken(userId int64, keys KeyProvider, duration time.Duration) (string, error) {
+	return sign(jwt.MapClaims{
+		"typ":     typeMfa,
+		"jti":     uuid.New().String(),
+		"user_id": userId,
+		"exp":     time.Now().Add(duration).Unix(),
+	}, keys)
+}
+
+func NewServiceToken(clientId, scope string, permissions []string, keys KeyProvider, duration time.Duration) (string, error) {
+	now := time.Now()
+
+	return sign(jwt.MapClaims{
+		"typ":         typeService,
+		"jti":         uuid.New().String(),
+		"client_id":   clientId,
+		"scope":       scope,
+		"permissions": permissionsClaim(permissions),
+		"iat":         now.Unix(),
+		"exp":         now.Add(duration).Unix(),
+	}, keys)
+}
+
+func NewIdToken(issuer, clientId, nonce string, userInfo map[string]any, keys KeyProvider, duration time.Duration) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss": issuer,
+		"aud": clientId,
+		"iat": now.Unix(),
+		"exp": now.Add(duration).Unix(),
+	}
+
+	for name, value := range userInfo {
+		claims[name] = value
+	}
+
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
 
-	tokenString, err := token.SignedString([]byte(secretKey))
+	return sign(claims, keys)
+}
+
+func ParseToken(tokenString string, keys KeyProvider) (*Claims, error) {
+	claims, err := parse(tokenString, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims["typ"] == typeService {
+		return serviceClaims(claims)
+	}
+
+	if _, ok := claims["typ"]; ok {
+		return nil, ErrInvalidToken
+	}
+
+	userId, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	role, ok := claims["user_role"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	sessionId, ok := claims["sid"].(string)
+	if !ok || sessionId == "" {
+		return nil, ErrInvalidToken
+	}
+
+	jti, issuedAt, expiresAt := registeredClaims(claims)
+	employerStatus, _ := claims["employer_status"].(string)
+	clientId, _ := claims["client_id"].(string)
+	scope, _ := claims["scope"].(string)
+
+	return &Claims{
+		UserId:         int64(userId),
+		Role:           models.UserRole(role),
+		SessionId:      sessionId,
+		EmployerStatus: models.CompanyStatus(employerStatus),
+		ClientId:       clientId,
+		Scope:          scope,
+		Id:             jti,
+		IssuedAt:       issuedAt,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+func serviceClaims(claims jwt.MapClaims) (*Claims, error) {
+	clientId, ok := claims["client_id"].(string)
+	if !ok || clientId == "" {
+		return nil, ErrInvalidToken
+	}
+
+	scope, _ := claims["scope"].(string)
+	jti, issuedAt, expiresAt := registeredClaims(claims)
+
+	return &Claims{
+		ClientId:       clientId,
+		Scope:          scope,
+		ServiceAccount: true,
+		Id:             jti,
+		IssuedAt:       issuedAt,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+func registeredClaims(claims jwt.MapClaims) (string, time.Time, time.Time) {
+	jti, _ := claims["jti"].(string)
+
+	var issuedAt, expiresAt time.Time
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		expiresAt = exp.Time
+	}
+
+	return jti, issuedAt, expiresAt
+}
+
+func ParseMfaToken(tokenString string, keys KeyProvider) (*models.MfaChallenge, error) {
+	claims, err := parse(tokenString, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims["typ"] != typeMfa {
+		return nil, ErrInvalidToken
+	}
+
+	userId, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	jti, _, expiresAt := registeredClaims(claims)
+	if jti == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &models.MfaChallenge{Id: jti, UserId: int64(userId), ExpiresAt: expiresAt}, nil
+}
+
+func userClaims(user models.User, sessionId string, permissions []string, duration time.Duration) jwt.MapClaims {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"jti":         uuid.New().String(),
+		"user_id":     user.Id,
+		"user_role":   user.Role,
+		"role":        enums.RoleConvertToString(user.Role),
+		"permissions": permissionsClaim(permissions),
+		"sid":         sessionId,
+		"iat":         now.Unix(),
+		"exp":         now.Add(duration).Unix(),
+	}
+
+	if user.EmployerStatus != "" {
+		claims["employer_status"] = user.EmployerStatus
+	}
+
+	return claims
+}
+
+func permissionsClaim(permissions []string) []string {
+	if permissions == nil {
+		return []string{}
+	}
+
+	return permissions
+}
+
+func sign(claims jwt.MapClaims, keys KeyProvider) (string, error) {
+	key, err := keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.Kid
+
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+func parse(tokenString string, keys KeyProvider) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+
+		key, err := keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.Algorithm != token.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+
+		return key.PrivateKey.Public(), nil
+	}, jwt.WithValidMethods([]string{AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA}))
+
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,46 @@
+package oidc
+
+import (
+	"auth/internal/domain/models"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	ScopeOpenId  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+	ScopePhone   = "phone"
+)
+
+var (
+	Scopes = []string{ScopeOpenId, ScopeProfile, ScopeEmail, ScopePhone}
+	Claims = []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "email", "email_verified", "phone_number", "phone_number_verified"}
+)
+
+func HasScope(scope, name string) bool {
+	return slices.Contains(strings.Fields(scope), name)
+}
+
+func UserInfo(user *models.User, scope string) map[string]any {
+	claims := map[string]any{
+		"sub": strconv.FormatInt(user.Id, 10),
+	}
+
+	if HasScope(scope, ScopeProfile) {
+		claims["name"] = user.FullName
+	}
+
+	if HasScope(scope, ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerifiedAt != nil
+	}
+
+	if HasScope(scope, ScopePhone) {
+		claims["phone_number"] = user.Phone
+		claims["phone_number_verified"] = user.PhoneVerifiedAt != nil
+	}
+
+	return claims
+}
@@ -0,0 +1,32 @@
+package pkce
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"regexp"
+)
+
+const (
+	MethodS256 = "S256"
+)
+
+var verifierPattern = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+func ValidChallenge(challenge string) bool {
+	return len(challenge) == base64.RawURLEncoding.EncodedLen(sha256.Size) && verifierPattern.MatchString(challenge)
+}
+
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func Verify(challenge, method, verifier string) bool {
+	if method != MethodS256 || !verifierPattern.MatchString(verifier) {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(Challenge(verifier)), []byte(challenge)) == 1
+}
@@ -0,0 +1,50 @@
+package pkce
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := Challenge(verifier)
+
+	if challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("Challenge() = %s, want RFC 7636 example", challenge)
+	}
+
+	if !ValidChallenge(challenge) {
+		t.Error("ValidChallenge() = false for S256 challenge")
+	}
+
+	if !Verify(challenge, MethodS256, verifier) {
+		t.Error("Verify() = false for matching verifier")
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		verifier string
+	}{
+		{name: "plain method", method: "plain", verifier: verifier},
+		{name: "wrong verifier", method: MethodS256, verifier: strings.Repeat("a", 43)},
+		{name: "short verifier", method: MethodS256, verifier: "short"},
+		{name: "invalid characters", method: MethodS256, verifier: strings.Repeat("+", 43)},
+	}
+
+	for _, tt := range tests {
+		if Verify(challenge, tt.method, tt.verifier) {
+			t.Errorf("%s: Verify() = true, want false", tt.name)
+		}
+	}
+}
+
+func TestValidChallengeRejectsPlainVerifiers(t *testing.T) {
+	if ValidChallenge(strings.Repeat("a", 128)) {
+		t.Error("ValidChallenge() = true for a 128 character value")
+	}
+
+	if ValidChallenge("") {
+		t.Error("ValidChallenge() = true for an empty value")
+	}
+}
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	sweepInterval = time.Hour
+	staleAfter    = 24 * time.Hour
+)
+
+type Limiter interface {
+	Allow(key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+type bucket struct {
+	tokens    float64
+	rate      float64
+	burst     float64
+	updatedAt time.Time
+}
+
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (m *Memory) Allow(key string, rate float64, burst int) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	b.rate = rate
+	b.burst = float64(burst)
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		return true, 0, nil
+	}
+
+	return false, retryAfter(b.tokens, rate), nil
+}
+
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range m.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*b.rate >= b.burst {
+			delete(m.buckets, key)
+		}
+	}
+
+	m.lastSweep = now
+}
+
+type Store interface {
+	TakeRateLimitToken(key string, rate float64, burst int) (bool, float64, error)
+	DeleteRateLimitBuckets(updatedBefore time.Time) error
+}
+
+type Postgres struct {
+	store     Store
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgres(store Store) *Postgres {
+	return &Postgres{
+		store:     store,
+		lastSweep: time.Now(),
+	}
+}
+
+func (p *Postgres) Allow(key string, rate float64, burst int) (bool, time.Duration, error) {
+	if err := p.sweep(); err != nil {
+		return false, 0, err
+	}
+
+	allowed, tokens, err := p.store.TakeRateLimitToken(key, rate, burst)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if allowed {
+		return true, 0, nil
+	}
+
+	return false, retryAfter(tokens, rate), nil
+}
+
+func (p *Postgres) sweep() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(p.lastSweep) < sweepInterval {
+		return nil
+	}
+
+	p.lastSweep = now
+
+	return p.store.DeleteRateLimitBuckets(now.Add(-staleAfter))
+}
+
+func retryAfter(tokens float64, rate float64) time.Duration {
+	if rate <= 0 {
+		return staleAfter
+	}
+
+	return time.Duration((1 - tokens) / rate * float64(time.Second))
+}
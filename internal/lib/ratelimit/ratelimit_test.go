@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryAllow(t *testing.T) {
+	limiter := NewMemory()
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := limiter.Allow("login:ip:1", 1, 3)
+		if err != nil || !allowed {
+			t.Fatalf("Allow() #%d = %v, %v, want allowed", i, allowed, err)
+		}
+	}
+
+	allowed, retryAfter, err := limiter.Allow("login:ip:1", 1, 3)
+	if err != nil || allowed {
+		t.Fatalf("Allow() over burst = %v, %v, want denied", allowed, err)
+	}
+
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("retry after = %s, want (0, 1s]", retryAfter)
+	}
+
+	if allowed, _, _ := limiter.Allow("login:ip:2", 1, 3); !allowed {
+		t.Error("Allow(other key) denied")
+	}
+
+	limiter.buckets["login:ip:1"].updatedAt = time.Now().Add(-time.Second)
+
+	if allowed, _, _ := limiter.Allow("login:ip:1", 1, 3); !allowed {
+		t.Error("Allow() after refill denied")
+	}
+}
+
+type fakeStore struct {
+	err    error
+	tokens float64
+}
+
+func (s *fakeStore) TakeRateLimitToken(key string, rate float64, burst int) (bool, float64, error) {
+	if s.err != nil {
+		return false, 0, s.err
+	}
+
+	if s.tokens >= 1 {
+		s.tokens--
+
+		return true, s.tokens, nil
+	}
+
+	return false, s.tokens, nil
+}
+
+func (s *fakeStore) DeleteRateLimitBuckets(updatedBefore time.Time) error {
+	return nil
+}
+
+func TestPostgresAllow(t *testing.T) {
+	store := &fakeStore{tokens: 1}
+	limiter := NewPostgres(store)
+
+	if allowed, _, err := limiter.Allow("key", 0.5, 1); err != nil || !allowed {
+		t.Fatalf("Allow() = %v, %v, want allowed", allowed, err)
+	}
+
+	allowed, retryAfter, err := limiter.Allow("key", 0.5, 1)
+	if err != nil || allowed {
+		t.Fatalf("Allow() = %v, %v, want denied", allowed, err)
+	}
+
+	if retryAfter != 2*time.Second {
+		t.Errorf("retry after = %s, want 2s", retryAfter)
+	}
+
+	store.err = errors.New("connection refused")
+
+	if _, _, err := limiter.Allow("key", 0.5, 1); !errors.Is(err, store.err) {
+		t.Errorf("Allow() error = %v, want %v", err, store.err)
+	}
+}
@@ -0,0 +1,70 @@
+package requisites
+
+var (
+	innLegalWeights     = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	innPersonalWeights1 = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	innPersonalWeights2 = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+func ValidInn(inn string) bool {
+	digits, ok := toDigits(inn)
+	if !ok {
+		return false
+	}
+
+	switch len(digits) {
+	case 10:
+		return checksum(digits, innLegalWeights) == digits[9]
+	case 12:
+		return checksum(digits, innPersonalWeights1) == digits[10] && checksum(digits, innPersonalWeights2) == digits[11]
+	default:
+		return false
+	}
+}
+
+func ValidOgrn(ogrn string) bool {
+	digits, ok := toDigits(ogrn)
+	if !ok {
+		return false
+	}
+
+	switch len(digits) {
+	case 13:
+		return remainder(digits[:12], 11)%10 == digits[12]
+	case 15:
+		return remainder(digits[:14], 13)%10 == digits[14]
+	default:
+		return false
+	}
+}
+
+func checksum(digits []int, weights []int) int {
+	var sum int
+	for i, weight := range weights {
+		sum += digits[i] * weight
+	}
+
+	return sum % 11 % 10
+}
+
+func remainder(digits []int, divisor int) int {
+	var rem int
+	for _, digit := range digits {
+		rem = (rem*10 + digit) % divisor
+	}
+
+	return rem
+}
+
+func toDigits(s string) ([]int, bool) {
+	digits := make([]int, 0, len(s))
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return nil, false
+		}
+
+		digits = append(digits, int(r-'0'))
+	}
+
+	return digits, true
+}
@@ -0,0 +1,65 @@
+package sealer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	keySize = 32
+)
+
+var (
+	ErrInvalidKey = errors.New("encryption key must be 32 base64 encoded bytes")
+	ErrMalformed  = errors.New("sealed data is malformed")
+)
+
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+func New(key string) (*Sealer, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != keySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sealer{aead: aead}, nil
+}
+
+func (s *Sealer) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize(), s.aead.NonceSize()+len(plaintext)+s.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return s.aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func (s *Sealer) Open(sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < s.aead.NonceSize()+s.aead.Overhead() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,71 @@
+package sealer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newSealer(t *testing.T) *Sealer {
+	t.Helper()
+
+	s, err := New(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, keySize)))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return s
+}
+
+func TestSealOpen(t *testing.T) {
+	s := newSealer(t)
+
+	sealed, err := s.Seal([]byte("private key"), []byte("kid"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	if bytes.Contains(sealed, []byte("private key")) {
+		t.Fatal("Seal() leaked the plaintext")
+	}
+
+	opened, err := s.Open(sealed, []byte("kid"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if string(opened) != "private key" {
+		t.Errorf("Open() = %q, want %q", opened, "private key")
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	s := newSealer(t)
+
+	sealed, err := s.Seal([]byte("private key"), []byte("kid"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	if _, err := s.Open(sealed, []byte("other")); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Open(other additional data) error = %v, want %v", err, ErrMalformed)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := s.Open(sealed, []byte("kid")); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Open(tampered) error = %v, want %v", err, ErrMalformed)
+	}
+
+	if _, err := s.Open([]byte("short"), []byte("kid")); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Open(short) error = %v, want %v", err, ErrMalformed)
+	}
+}
+
+func TestNewRejectsInvalidKey(t *testing.T) {
+	for _, key := range []string{"", "not base64", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := New(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("New(%q) error = %v, want %v", key, err, ErrInvalidKey)
+		}
+	}
+}
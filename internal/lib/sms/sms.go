@@ -0,0 +1,77 @@
+package sms
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	ProviderLog  = "log"
+	ProviderFile = "file"
+)
+
+var (
+	ErrUnknownProvider = errors.New("unknown sms provider")
+)
+
+type Sender interface {
+	Send(phone string, text string) error
+}
+
+func New(provider string, filePath string, log *slog.Logger) (Sender, error) {
+	switch provider {
+	case ProviderLog:
+		return NewLogSender(log), nil
+	case ProviderFile:
+		return NewFileSender(filePath), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+}
+
+type LogSender struct {
+	log *slog.Logger
+}
+
+func NewLogSender(log *slog.Logger) *LogSender {
+	return &LogSender{
+		log: log.With(slog.String("component", "sms/log")),
+	}
+}
+
+func (s *LogSender) Send(phone string, text string) error {
+	s.log.Info("sms sent", slog.String("phone", phone), slog.String("text", text))
+
+	return nil
+}
+
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSender(path string) *FileSender {
+	return &FileSender{
+		path: path,
+	}
+}
+
+func (s *FileSender) Send(phone string, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phone, text)
+
+	return err
+}
@@ -3,11 +3,12 @@ package auth
 import (
 	"auth/internal/domain/models"
 	"auth/internal/lib/enums"
+	"auth/internal/lib/requisites"
 	"auth/internal/storage"
 	"errors"
-	"fmt"
 	passwordvalidator "github.com/wagslane/go-password-validator"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,11 @@ var (
 	EmptyEmailErr         = errors.New("email is empty")
 	EmptyPhoneErr         = errors.New("phone is empty")
 	EmptyNameErr          = errors.New("empty name err")
+	ErrPhoneNotVerified   = errors.New("phone is not verified")
+	ErrRoleNotAllowed     = errors.New("role is not allowed for self registration")
+	ErrInvalidInn         = errors.New("invalid inn")
+	ErrInvalidOgrn        = errors.New("invalid ogrn")
+	ErrVersionConflict    = errors.New("user was modified concurrently")
 )
 
 const (
@@ -27,14 +33,16 @@ const (
 )
 
 type UserRepository interface {
-	SaveUser(fullName, password, phone, email string, userRole string) error
+	SaveUser(fullName, password, phone, email string, userRole string) (int64, error)
+	SaveEmployer(fullName, password, phone, email string, company models.Company) (int64, error)
 	UserByEmail(email string) (*models.User, error)
 	UserByPhone(email string) (*models.User, error)
 	UpdatePassword(userId int64, newPassword string) error
 	UserByUserId(userId int64) (*models.User, error)
-	UpdateUser(newFullName, newPhone, newEmail string, userId int64) error
+	UpdateProfile(userId, version int64, fullName, pendingEmail, pendingPhone *string) error
 	DeleteUser(userId int64) error
 	RestoreUser(userId int64) error
+	RevokeUserSessions(userId int64) error
 }
 
 type Service struct {
@@ -49,22 +57,47 @@ func New(userRepository UserRepository, tokenTtl time.Duration) *Service {
 	}
 }
 
-func (s *Service) RegisterUser(fullName, password, phone, email string, userRole string) error {
-	err := passwordvalidator.Validate(password, minEntropyBits)
+func (s *Service) RegisterUser(fullName, password, phone, email string, userRole string) (int64, error) {
+	if userRole != enums.RoleJobSeeker {
+		return 0, ErrRoleNotAllowed
+	}
+
+	passHash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.userRepository.SaveUser(fullName, passHash, phone, email, userRole)
+}
+
+func (s *Service) RegisterEmployer(fullName, password, phone, email string, company models.Company) (int64, error) {
+	if !requisites.ValidInn(company.Inn) {
+		return 0, ErrInvalidInn
+	}
+
+	if !requisites.ValidOgrn(company.Ogrn) {
+		return 0, ErrInvalidOgrn
+	}
+
+	passHash, err := hashPassword(password)
 	if err != nil {
-		return ErrBadPassword
+		return 0, err
 	}
 
-	return s.userRepository.SaveUser(fullName, password, phone, email, userRole)
+	return s.userRepository.SaveEmployer(fullName, passHash, phone, email, company)
 }
 
 func (s *Service) UpdatePassword(userId int64, newPassword string) error {
-	err := passwordvalidator.Validate(newPassword, minEntropyBits)
+	passHash, err := hashPassword(newPassword)
 	if err != nil {
-		return ErrBadPassword
+		return err
 	}
 
-	return s.userRepository.UpdatePassword(userId, newPassword)
+	if err := s.userRepository.UpdatePassword(userId, passHash); err != nil {
+		return err
+	}
+
+	return s.userRepository.RevokeUserSessions(userId)
 }
 
 func (s *Service) UserByEmail(email string) (*models.User, error) {
@@ -72,7 +105,14 @@ func (s *Service) UserByEmail(email string) (*models.User, error) {
 		return nil, ErrEmptyFieldLogin
 	}
 
-	return s.userRepository.UserByEmail(email)
+	user, err := s.userRepository.UserByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = enums.RoleConvertFromString(user.RoleString)
+
+	return user, nil
 }
 
 func (s *Service) UserByPhone(phone string) (*models.User, error) {
@@ -80,7 +120,14 @@ func (s *Service) UserByPhone(phone string) (*models.User, error) {
 		return nil, ErrEmptyFieldLogin
 	}
 
-	return s.userRepository.UserByPhone(phone)
+	user, err := s.userRepository.UserByPhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = enums.RoleConvertFromString(user.RoleString)
+
+	return user, nil
 }
 
 func (s *Service) UserByContactInfo(contactInfo string) (*models.User, error) {
@@ -89,7 +136,6 @@ func (s *Service) UserByContactInfo(contactInfo string) (*models.User, error) {
 	}
 
 	user, err := s.UserByPhone(contactInfo)
-	fmt.Println(user)
 	if err == nil {
 		return user, nil
 	}
@@ -108,6 +154,14 @@ func (s *Service) UserByContactInfo(contactInfo string) (*models.User, error) {
 	return nil, storage.ErrUserNotFound
 }
 
+func (s *Service) CheckContactInfo(user *models.User, contactInfo string) error {
+	if strings.TrimSpace(contactInfo) == user.Phone && user.PhoneVerifiedAt == nil {
+		return ErrPhoneNotVerified
+	}
+
+	return nil
+}
+
 func (s *Service) Authorize(user *models.User, password string) error {
 	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
 		return ErrInvalidCredentials
@@ -134,24 +188,112 @@ func (s *Service) UserByUserId(userId int64) (*models.User, error) {
 	return user, nil
 }
 
-func (s *Service) UpdateUser(newFullName, newPhone, newEmail string, userId int64) error {
-	if newFullName == "" {
-		return EmptyNameErr
+func (s *Service) UpdateUser(newFullName, newPhone, newEmail string, userId, version int64) (*models.User, models.ProfileChanges, error) {
+	return s.UpdateProfile(userId, version, models.ProfileUpdate{
+		FullName: &newFullName,
+		Email:    &newEmail,
+		Phone:    &newPhone,
+	})
+}
+
+func (s *Service) UpdateProfile(userId, version int64, update models.ProfileUpdate) (*models.User, models.ProfileChanges, error) {
+	if userId == 0 {
+		return nil, models.ProfileChanges{}, EmptyUser
 	}
 
-	if newPhone == "" {
-		return EmptyPhoneErr
+	if update.FullName != nil && strings.TrimSpace(*update.FullName) == "" {
+		return nil, models.ProfileChanges{}, EmptyNameErr
 	}
 
-	if newEmail == "" {
-		return EmptyEmailErr
+	if update.Email != nil && *update.Email == "" {
+		return nil, models.ProfileChanges{}, EmptyEmailErr
 	}
 
-	if userId == 0 {
-		return EmptyUser
+	if update.Phone != nil && *update.Phone == "" {
+		return nil, models.ProfileChanges{}, EmptyPhoneErr
+	}
+
+	user, err := s.userRepository.UserByUserId(userId)
+	if err != nil {
+		return nil, models.ProfileChanges{}, err
+	}
+
+	if version == 0 {
+		version = user.Version
 	}
 
-	return s.userRepository.UpdateUser(newFullName, newPhone, newEmail, userId)
+	if version != user.Version {
+		return nil, models.ProfileChanges{}, ErrVersionConflict
+	}
+
+	var pendingEmail, pendingPhone *string
+
+	if update.Email != nil {
+		pendingEmail, err = s.pendingChange(*update.Email, user.Email, user.PendingEmail, s.userRepository.UserByEmail)
+		if err != nil {
+			return nil, models.ProfileChanges{}, err
+		}
+	}
+
+	if update.Phone != nil {
+		pendingPhone, err = s.pendingChange(*update.Phone, user.Phone, user.PendingPhone, s.userRepository.UserByPhone)
+		if err != nil {
+			return nil, models.ProfileChanges{}, err
+		}
+	}
+
+	changes := models.ProfileChanges{
+		FullName: update.FullName != nil && *update.FullName != user.FullName,
+		Email:    pendingEmail != nil && *pendingEmail != "",
+		Phone:    pendingPhone != nil && *pendingPhone != "",
+	}
+
+	if update.FullName == nil && pendingEmail == nil && pendingPhone == nil {
+		return user, changes, nil
+	}
+
+	err = s.userRepository.UpdateProfile(userId, version, update.FullName, pendingEmail, pendingPhone)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		return nil, models.ProfileChanges{}, ErrVersionConflict
+	}
+
+	if err != nil {
+		return nil, models.ProfileChanges{}, err
+	}
+
+	user, err = s.userRepository.UserByUserId(userId)
+	if err != nil {
+		return nil, models.ProfileChanges{}, err
+	}
+
+	return user, changes, nil
+}
+
+func (s *Service) pendingChange(value, current, pending string, lookup func(string) (*models.User, error)) (*string, error) {
+	if value == current {
+		if pending == "" {
+			return nil, nil
+		}
+
+		cancel := ""
+
+		return &cancel, nil
+	}
+
+	if value == pending {
+		return nil, nil
+	}
+
+	_, err := lookup(value)
+	if err == nil {
+		return nil, storage.ErrUserExist
+	}
+
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		return nil, err
+	}
+
+	return &value, nil
 }
 
 func (s *Service) DeleteUser(userId int64) error {
@@ -159,7 +301,11 @@ func (s *Service) DeleteUser(userId int64) error {
 		return EmptyUser
 	}
 
-	return s.userRepository.DeleteUser(userId)
+	if err := s.userRepository.DeleteUser(userId); err != nil {
+		return err
+	}
+
+	return s.userRepository.RevokeUserSessions(userId)
 }
 
 func (s *Service) RestoreUser(userId int64) error {
@@ -169,3 +315,16 @@ func (s *Service) RestoreUser(userId int64) error {
 
 	return s.userRepository.RestoreUser(userId)
 }
+
+func hashPassword(password string) (string, error) {
+	if err := passwordvalidator.Validate(password, minEntropyBits); err != nil {
+		return "", ErrBadPassword
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(passHash), nil
+}
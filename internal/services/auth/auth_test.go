@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	strongPassword = "correct-horse-battery-staple-42"
+	validInn       = "7707083893"
+	validOgrn      = "1027700132195"
+)
+
+type fakeRepository struct {
+	passwords       map[int64]string
+	users           map[int64]*models.User
+	revokedSessions []int64
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{passwords: make(map[int64]string), users: make(map[int64]*models.User)}
+}
+
+func (r *fakeRepository) SaveUser(fullName, password, phone, email string, userRole string) (int64, error) {
+	userId := int64(len(r.passwords) + 1)
+	r.passwords[userId] = password
+
+	return userId, nil
+}
+
+func (r *fakeRepository) SaveEmployer(fullName, password, phone, email string, company models.Company) (int64, error) {
+	return r.SaveUser(fullName, password, phone, email, "employer")
+}
+
+func (r *fakeRepository) UserByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return nil, storage.ErrUserNotFound
+}
+
+func (r *fakeRepository) UserByPhone(phone string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Phone == strings.TrimRight(phone, " ") {
+			return user, nil
+		}
+	}
+
+	return nil, storage.ErrUserNotFound
+}
+
+func (r *fakeRepository) UpdatePassword(userId int64, newPassword string) error {
+	r.passwords[userId] = newPassword
+
+	return nil
+}
+
+func (r *fakeRepository) UserByUserId(userId int64) (*models.User, error) {
+	user, ok := r.users[userId]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	copied := *user
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) UpdateProfile(userId, version int64, fullName, pendingEmail, pendingPhone *string) error {
+	user, ok := r.users[userId]
+	if !ok {
+		return storage.ErrUserNotFound
+	}
+
+	if user.Version != version {
+		return storage.ErrVersionConflict
+	}
+
+	if fullName != nil {
+		user.FullName = *fullName
+	}
+
+	if pendingEmail != nil {
+		user.PendingEmail = *pendingEmail
+	}
+
+	if pendingPhone != nil {
+		user.PendingPhone = *pendingPhone
+	}
+
+	user.Version++
+
+	return nil
+}
+
+func (r *fakeRepository) DeleteUser(userId int64) error {
+	return nil
+}
+
+func (r *fakeRepository) RestoreUser(userId int64) error {
+	return nil
+}
+
+func (r *fakeRepository) RevokeUserSessions(userId int64) error {
+	r.revokedSessions = append(r.revokedSessions, userId)
+
+	return nil
+}
+
+func assertHashed(t *testing.T, stored, password string) {
+	t.Helper()
+
+	if stored == password {
+		t.Fatal("password is stored in plaintext")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+		t.Errorf("stored hash does not match password: %v", err)
+	}
+}
+
+func TestRegisterUserHashesPassword(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, time.Minute)
+
+	userId, err := service.RegisterUser("User", strongPassword, "+79990000000", "user@example.com", "jobseeker")
+	if err != nil {
+		t.Fatalf("RegisterUser() error = %v", err)
+	}
+
+	assertHashed(t, repository.passwords[userId], strongPassword)
+}
+
+func TestRegisterUserRejectsWeakPassword(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, time.Minute)
+
+	if _, err := service.RegisterUser("User", "qwerty", "+79990000000", "user@example.com", "jobseeker"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("RegisterUser(weak password) error = %v, want %v", err, ErrBadPassword)
+	}
+
+	if _, err := service.RegisterUser("User", strongPassword, "+79990000000", "user@example.com", "employer"); !errors.Is(err, ErrRoleNotAllowed) {
+		t.Errorf("RegisterUser(employer) error = %v, want %v", err, ErrRoleNotAllowed)
+	}
+
+	if len(repository.passwords) != 0 {
+		t.Errorf("saved users = %d, want 0", len(repository.passwords))
+	}
+}
+
+func TestRegisterEmployerValidatesInput(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, time.Minute)
+
+	company := models.Company{Name: "Company", Inn: validInn, Ogrn: validOgrn}
+
+	if _, err := service.RegisterEmployer("User", "qwerty", "+79990000000", "user@example.com", company); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("RegisterEmployer(weak password) error = %v, want %v", err, ErrBadPassword)
+	}
+
+	invalidInn := company
+	invalidInn.Inn = "7707083890"
+
+	if _, err := service.RegisterEmployer("User", strongPassword, "+79990000000", "user@example.com", invalidInn); !errors.Is(err, ErrInvalidInn) {
+		t.Errorf("RegisterEmployer(invalid inn) error = %v, want %v", err, ErrInvalidInn)
+	}
+
+	userId, err := service.RegisterEmployer("User", strongPassword, "+79990000000", "user@example.com", company)
+	if err != nil {
+		t.Fatalf("RegisterEmployer() error = %v", err)
+	}
+
+	assertHashed(t, repository.passwords[userId], strongPassword)
+}
+
+func TestUpdatePasswordHashesAndRevokesSessions(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, time.Minute)
+
+	if err := service.UpdatePassword(1, "qwerty"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("UpdatePassword(weak password) error = %v, want %v", err, ErrBadPassword)
+	}
+
+	if err := service.UpdatePassword(1, strongPassword); err != nil {
+		t.Fatalf("UpdatePassword() error = %v", err)
+	}
+
+	assertHashed(t, repository.passwords[1], strongPassword)
+
+	if len(repository.revokedSessions) != 1 {
+		t.Errorf("revoked sessions = %v, want one", repository.revokedSessions)
+	}
+}
+
+func TestUpdateProfileReportsChanges(t *testing.T) {
+	repository := newFakeRepository()
+	repository.users[1] = &models.User{Id: 1, FullName: "User", Email: "user@example.com", Phone: "+79990000000", PendingPhone: "+79990000001", Version: 1}
+	repository.users[2] = &models.User{Id: 2, FullName: "Other", Email: "other@example.com", Phone: "+79990000002", Version: 1}
+	service := New(repository, time.Minute)
+
+	ptr := func(value string) *string {
+		return &value
+	}
+
+	tests := []struct {
+		name    string
+		update  models.ProfileUpdate
+		changes models.ProfileChanges
+	}{
+		{name: "same values", update: models.ProfileUpdate{FullName: ptr("User"), Email: ptr("user@example.com")}},
+		{name: "pending phone resubmitted", update: models.ProfileUpdate{Phone: ptr("+79990000001")}},
+		{name: "full name", update: models.ProfileUpdate{FullName: ptr("New Name")}, changes: models.ProfileChanges{FullName: true}},
+		{name: "email", update: models.ProfileUpdate{Email: ptr("new@example.com")}, changes: models.ProfileChanges{Email: true}},
+		{name: "pending phone cancelled", update: models.ProfileUpdate{Phone: ptr("+79990000000")}},
+		{name: "phone", update: models.ProfileUpdate{Phone: ptr("+79990000003")}, changes: models.ProfileChanges{Phone: true}},
+	}
+
+	for _, tt := range tests {
+		_, changes, err := service.UpdateProfile(1, 0, tt.update)
+		if err != nil {
+			t.Errorf("%s: UpdateProfile() error = %v", tt.name, err)
+
+			continue
+		}
+
+		if changes != tt.changes {
+			t.Errorf("%s: changes = %+v, want %+v", tt.name, changes, tt.changes)
+		}
+	}
+
+	user := repository.users[1]
+	if user.FullName != "New Name" || user.PendingEmail != "new@example.com" || user.PendingPhone != "+79990000003" || user.Email != "user@example.com" {
+		t.Errorf("user = %+v, want pending contact changes", user)
+	}
+
+	if _, _, err := service.UpdateProfile(1, 0, models.ProfileUpdate{Email: ptr("other@example.com")}); !errors.Is(err, storage.ErrUserExist) {
+		t.Errorf("taken email: error = %v, want %v", err, storage.ErrUserExist)
+	}
+
+	if _, _, err := service.UpdateProfile(1, 1, models.ProfileUpdate{FullName: ptr("Stale")}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("stale version: error = %v, want %v", err, ErrVersionConflict)
+	}
+}
+
+func TestPhoneLoginRequiresVerifiedPhone(t *testing.T) {
+	verifiedAt := time.Now()
+
+	repository := newFakeRepository()
+	repository.users[1] = &models.User{Id: 1, Email: "user@example.com", Phone: "+79990000000", RoleString: "applicant"}
+	repository.users[2] = &models.User{Id: 2, Email: "other@example.com", Phone: "+79990000001", PhoneVerifiedAt: &verifiedAt, RoleString: "applicant"}
+	service := New(repository, time.Minute)
+
+	tests := []struct {
+		name        string
+		contactInfo string
+		err         error
+	}{
+		{name: "unverified phone", contactInfo: "+79990000000", err: ErrPhoneNotVerified},
+		{name: "unverified phone with padding", contactInfo: "+79990000000  ", err: ErrPhoneNotVerified},
+		{name: "email of unverified phone", contactInfo: "user@example.com"},
+		{name: "verified phone", contactInfo: "+79990000001"},
+	}
+
+	for _, tt := range tests {
+		user, err := service.UserByContactInfo(tt.contactInfo)
+		if err != nil {
+			t.Errorf("%s: UserByContactInfo() error = %v", tt.name, err)
+
+			continue
+		}
+
+		if err := service.CheckContactInfo(user, tt.contactInfo); !errors.Is(err, tt.err) {
+			t.Errorf("%s: CheckContactInfo() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package authn
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	EmptyTokenErr   = errors.New("token is empty")
+	ErrTokenRevoked = errors.New("token is revoked")
+)
+
+type SessionValidator interface {
+	Validate(sessionId string) error
+}
+
+type RevocationChecker interface {
+	TokenRevoked(jti string) (bool, error)
+}
+
+type PermissionProvider interface {
+	RolePermissions(role models.UserRole) ([]string, error)
+	ClientPermissions(clientId string) ([]string, error)
+}
+
+type Service struct {
+	keys               jwt.KeyProvider
+	sessionValidator   SessionValidator
+	revocationChecker  RevocationChecker
+	permissionProvider PermissionProvider
+}
+
+func New(keys jwt.KeyProvider, sessionValidator SessionValidator, revocationChecker RevocationChecker, permissionProvider PermissionProvider) *Service {
+	return &Service{
+		keys:               keys,
+		sessionValidator:   sessionValidator,
+		revocationChecker:  revocationChecker,
+		permissionProvider: permissionProvider,
+	}
+}
+
+func (s *Service) Authenticate(token string) (*models.Principal, error) {
+	if token == "" {
+		return nil, EmptyTokenErr
+	}
+
+	claims, err := jwt.ParseToken(token, s.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Id != "" {
+		revoked, err := s.revocationChecker.TokenRevoked(claims.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	if claims.ServiceAccount {
+		return s.servicePrincipal(claims)
+	}
+
+	if err := s.sessionValidator.Validate(claims.SessionId); err != nil {
+		return nil, err
+	}
+
+	var permissions []string
+	if claims.ClientId == "" {
+		permissions, err = s.permissionProvider.RolePermissions(claims.Role)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Principal{
+		UserId:         claims.UserId,
+		Role:           claims.Role,
+		SessionId:      claims.SessionId,
+		EmployerStatus: claims.EmployerStatus,
+		ClientId:       claims.ClientId,
+		Scope:          claims.Scope,
+		Permissions:    permissions,
+	}, nil
+}
+
+func (s *Service) servicePrincipal(claims *jwt.Claims) (*models.Principal, error) {
+	granted, err := s.permissionProvider.ClientPermissions(claims.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []string
+	for _, permission := range strings.Fields(claims.Scope) {
+		if slices.Contains(granted, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return &models.Principal{
+		ClientId:       claims.ClientId,
+		Scope:          claims.Scope,
+		ServiceAccount: true,
+		Permissions:    permissions,
+	}, nil
+}
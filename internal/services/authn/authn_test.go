@@ -0,0 +1,172 @@
+package authn
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func newStaticKeys(t *testing.T) *staticKeys {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &staticKeys{key: &jwt.Key{Kid: "test", Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	if kid != k.key.Kid {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return k.key, nil
+}
+
+type fakeSessions struct{}
+
+func (fakeSessions) Validate(sessionId string) error {
+	return nil
+}
+
+type fakeRevocations struct {
+	revoked map[string]bool
+}
+
+func (r fakeRevocations) TokenRevoked(jti string) (bool, error) {
+	return r.revoked[jti], nil
+}
+
+type fakePermissions struct{}
+
+func (fakePermissions) RolePermissions(role models.UserRole) ([]string, error) {
+	return []string{models.PermissionUsersReadSelf, models.PermissionUsersUpdateSelf}, nil
+}
+
+func (fakePermissions) ClientPermissions(clientId string) ([]string, error) {
+	return []string{models.PermissionUsersReadAny}, nil
+}
+
+func TestAuthenticateFirstPartyToken(t *testing.T) {
+	keys := newStaticKeys(t)
+	service := New(keys, fakeSessions{}, fakeRevocations{}, fakePermissions{})
+
+	token, err := jwt.NewToken(models.User{Id: 1, Role: models.JobSeeker}, "session", nil, keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if principal.Delegated() {
+		t.Error("first-party principal is delegated")
+	}
+
+	if !principal.HasPermission(models.PermissionUsersUpdateSelf) {
+		t.Errorf("permissions = %v, want role permissions", principal.Permissions)
+	}
+}
+
+func TestAuthenticateClientTokenHasNoRolePermissions(t *testing.T) {
+	keys := newStaticKeys(t)
+	service := New(keys, fakeSessions{}, fakeRevocations{}, fakePermissions{})
+
+	token, err := jwt.NewClientToken(models.User{Id: 1, Role: models.Admin}, "session", "partner", "openid", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if !principal.Delegated() {
+		t.Error("client principal is not delegated")
+	}
+
+	if len(principal.Permissions) != 0 {
+		t.Errorf("permissions = %v, want none", principal.Permissions)
+	}
+
+	if principal.Scope != "openid" {
+		t.Errorf("scope = %q, want openid", principal.Scope)
+	}
+}
+
+func TestAuthenticateServiceToken(t *testing.T) {
+	keys := newStaticKeys(t)
+	service := New(keys, fakeSessions{}, fakeRevocations{}, fakePermissions{})
+
+	scope := models.PermissionUsersReadAny + " " + models.PermissionUsersDeleteAny
+
+	token, err := jwt.NewServiceToken("worker", scope, nil, keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if !principal.ServiceAccount || principal.Delegated() {
+		t.Errorf("principal = %+v, want a service account", principal)
+	}
+
+	if len(principal.Permissions) != 1 || !principal.HasPermission(models.PermissionUsersReadAny) {
+		t.Errorf("permissions = %v, want scope limited to granted permissions", principal.Permissions)
+	}
+
+	token, err = jwt.NewServiceToken("worker", "", nil, keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err = service.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if len(principal.Permissions) != 0 {
+		t.Errorf("permissions = %v, want none for an empty scope", principal.Permissions)
+	}
+}
+
+func TestAuthenticateRejectsRevokedToken(t *testing.T) {
+	keys := newStaticKeys(t)
+
+	token, err := jwt.NewToken(models.User{Id: 1, Role: models.JobSeeker}, "session", nil, keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jwt.ParseToken(token, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := New(keys, fakeSessions{}, fakeRevocations{revoked: map[string]bool{claims.Id: true}}, fakePermissions{})
+
+	if _, err := service.Authenticate(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Authenticate(revoked) error = %v, want %v", err, ErrTokenRevoked)
+	}
+}
@@ -0,0 +1,147 @@
+package emailverification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/enums"
+	"auth/internal/lib/opaque"
+	"auth/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	emailSubject = "Email Verification"
+)
+
+var (
+	ErrEmailNotVerified  = errors.New("email is not verified")
+	ErrAlreadyVerified   = errors.New("email is already verified")
+	ErrInvalidToken      = errors.New("invalid verification token")
+	ErrTokenExpired      = errors.New("verification token expired")
+	ErrTooManyRequests   = errors.New("verification requested too often")
+	EmptyTokenErr        = errors.New("verification token is empty")
+	EmptyUserErr         = errors.New("empty user")
+	EmptyEmailErr        = errors.New("email is empty")
+	ErrUnknownPolicyRole = errors.New("unknown role in email verification policy")
+)
+
+type Repository interface {
+	SaveEmailVerification(tokenHash string, userId int64, expiration time.Time) error
+	EmailVerification(tokenHash string) (*models.EmailVerification, error)
+	LastEmailVerificationAt(userId int64) (*time.Time, error)
+	ConfirmEmail(userId int64) error
+}
+
+type EmailSender interface {
+	Send(recipientEmail string, subject string, body string) error
+}
+
+type Service struct {
+	repository       Repository
+	emailSender      EmailSender
+	verificationUrl  string
+	ttl              time.Duration
+	resendInterval   time.Duration
+	requiredForLogin bool
+	requiredRoles    []models.UserRole
+}
+
+func New(repository Repository, emailSender EmailSender, verificationUrl string, ttl, resendInterval time.Duration, requiredForLogin bool, requiredRoles []string) (*Service, error) {
+	roles := make([]models.UserRole, 0, len(requiredRoles))
+	for _, requiredRole := range requiredRoles {
+		role := enums.RoleConvertFromString(requiredRole)
+		if role == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPolicyRole, requiredRole)
+		}
+
+		roles = append(roles, role)
+	}
+
+	return &Service{
+		repository:       repository,
+		emailSender:      emailSender,
+		verificationUrl:  verificationUrl,
+		ttl:              ttl,
+		resendInterval:   resendInterval,
+		requiredForLogin: requiredForLogin,
+		requiredRoles:    roles,
+	}, nil
+}
+
+func (s *Service) Send(user *models.User) error {
+	if user.Id == 0 {
+		return EmptyUserErr
+	}
+
+	email := user.Email
+	if user.PendingEmail != "" {
+		email = user.PendingEmail
+	}
+
+	if email == "" {
+		return EmptyEmailErr
+	}
+
+	if user.EmailVerifiedAt != nil && user.PendingEmail == "" {
+		return ErrAlreadyVerified
+	}
+
+	lastSentAt, err := s.repository.LastEmailVerificationAt(user.Id)
+	if err != nil {
+		return err
+	}
+
+	if lastSentAt != nil && time.Since(*lastSentAt) < s.resendInterval {
+		return ErrTooManyRequests
+	}
+
+	token, err := opaque.New()
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.SaveEmailVerification(opaque.Hash(token), user.Id, time.Now().Add(s.ttl)); err != nil {
+		return err
+	}
+
+	return s.emailSender.Send(email, emailSubject, fmt.Sprintf("%s?token=%s", s.verificationUrl, token))
+}
+
+func (s *Service) Confirm(token string) error {
+	if token == "" {
+		return EmptyTokenErr
+	}
+
+	verification, err := s.repository.EmailVerification(opaque.Hash(token))
+	if errors.Is(err, storage.ErrVerificationNotFound) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Now().After(verification.Expiration) {
+		return ErrTokenExpired
+	}
+
+	return s.repository.ConfirmEmail(verification.UserId)
+}
+
+func (s *Service) CheckPolicy(user *models.User) error {
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if s.requiredForLogin {
+		return ErrEmailNotVerified
+	}
+
+	for _, role := range s.requiredRoles {
+		if role == user.Role {
+			return ErrEmailNotVerified
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package introspection
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/opaque"
+	"auth/internal/storage"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrUnauthorizedClient = errors.New("client is not allowed to introspect tokens")
+)
+
+type Repository interface {
+	RefreshToken(tokenHash string) (*models.RefreshToken, error)
+	RevokeRefreshTokenFamily(familyId string) error
+	RevokeToken(jti string, expiration time.Time) error
+	TokenRevoked(jti string) (bool, error)
+	DeleteExpiredRevokedTokens() error
+}
+
+type ClientAuthenticator interface {
+	AuthenticateClient(clientId, clientSecret string) (*models.OAuthClient, error)
+}
+
+type SessionValidator interface {
+	Validate(sessionId string) error
+}
+
+type Service struct {
+	repository          Repository
+	clientAuthenticator ClientAuthenticator
+	sessionValidator    SessionValidator
+	keys                jwt.KeyProvider
+}
+
+func New(repository Repository, clientAuthenticator ClientAuthenticator, sessionValidator SessionValidator, keys jwt.KeyProvider) *Service {
+	return &Service{
+		repository:          repository,
+		clientAuthenticator: clientAuthenticator,
+		sessionValidator:    sessionValidator,
+		keys:                keys,
+	}
+}
+
+func (s *Service) Introspect(clientId, clientSecret, token, tokenTypeHint string) (*models.Introspection, error) {
+	client, err := s.clientAuthenticator.AuthenticateClient(clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Public {
+		return nil, ErrUnauthorizedClient
+	}
+
+	result, err := s.introspect(token, tokenTypeHint)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ClientId != client.Id && !slices.Contains(client.Permissions, models.PermissionTokensIntrospectAny) {
+		return &models.Introspection{}, nil
+	}
+
+	return result, nil
+}
+
+func (s *Service) introspect(token, tokenTypeHint string) (*models.Introspection, error) {
+	if tokenTypeHint == models.TokenTypeRefresh {
+		if result, err := s.introspectRefreshToken(token); err != nil || result.Active {
+			return result, err
+		}
+
+		return s.introspectAccessToken(token)
+	}
+
+	if result, err := s.introspectAccessToken(token); err != nil || result.Active {
+		return result, err
+	}
+
+	return s.introspectRefreshToken(token)
+}
+
+func (s *Service) Revoke(clientId, clientSecret, token, tokenTypeHint string) error {
+	client, err := s.clientAuthenticator.AuthenticateClient(clientId, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	if tokenTypeHint == models.TokenTypeRefresh {
+		if revoked, err := s.revokeRefreshToken(client.Id, token); err != nil || revoked {
+			return err
+		}
+
+		_, err := s.revokeAccessToken(client.Id, token)
+
+		return err
+	}
+
+	if revoked, err := s.revokeAccessToken(client.Id, token); err != nil || revoked {
+		return err
+	}
+
+	_, err = s.revokeRefreshToken(client.Id, token)
+
+	return err
+}
+
+func (s *Service) introspectAccessToken(token string) (*models.Introspection, error) {
+	claims, err := jwt.ParseToken(token, s.keys)
+	if err != nil {
+		return &models.Introspection{}, nil
+	}
+
+	if claims.Id != "" {
+		revoked, err := s.repository.TokenRevoked(claims.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return &models.Introspection{}, nil
+		}
+	}
+
+	result := &models.Introspection{
+		Active:    true,
+		TokenType: models.TokenTypeAccess,
+		Id:        claims.Id,
+		ClientId:  claims.ClientId,
+		Scope:     claims.Scope,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+	}
+
+	if claims.ServiceAccount {
+		result.Subject = claims.ClientId
+
+		return result, nil
+	}
+
+	if err := s.sessionValidator.Validate(claims.SessionId); err != nil {
+		return &models.Introspection{}, nil
+	}
+
+	result.Subject = strconv.FormatInt(claims.UserId, 10)
+	result.UserId = claims.UserId
+	result.Role = claims.Role
+
+	return result, nil
+}
+
+func (s *Service) introspectRefreshToken(token string) (*models.Introspection, error) {
+	stored, err := s.repository.RefreshToken(opaque.Hash(token))
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return &models.Introspection{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Used || stored.Revoked || time.Now().After(stored.Expiration) {
+		return &models.Introspection{}, nil
+	}
+
+	return &models.Introspection{
+		Active:    true,
+		TokenType: models.TokenTypeRefresh,
+		Subject:   strconv.FormatInt(stored.UserId, 10),
+		UserId:    stored.UserId,
+		ClientId:  stored.ClientId,
+		Scope:     stored.Scope,
+		ExpiresAt: stored.Expiration,
+	}, nil
+}
+
+func (s *Service) revokeAccessToken(clientId, token string) (bool, error) {
+	claims, err := jwt.ParseToken(token, s.keys)
+	if err != nil || claims.Id == "" {
+		return false, nil
+	}
+
+	if claims.ClientId != clientId {
+		return false, nil
+	}
+
+	if err := s.repository.RevokeToken(claims.Id, claims.ExpiresAt); err != nil {
+		return false, err
+	}
+
+	if err := s.repository.DeleteExpiredRevokedTokens(); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) revokeRefreshToken(clientId, token string) (bool, error) {
+	stored, err := s.repository.RefreshToken(opaque.Hash(token))
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if stored.ClientId != clientId {
+		return false, nil
+	}
+
+	if err := s.repository.RevokeRefreshTokenFamily(stored.FamilyId); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
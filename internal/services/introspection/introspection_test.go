@@ -0,0 +1,198 @@
+package introspection
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/opaque"
+	"auth/internal/services/oauth"
+	"auth/internal/storage"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRepository struct {
+	refreshTokens map[string]*models.RefreshToken
+	revoked       map[string]bool
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		refreshTokens: make(map[string]*models.RefreshToken),
+		revoked:       make(map[string]bool),
+	}
+}
+
+func (r *fakeRepository) RefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	token, ok := r.refreshTokens[tokenHash]
+	if !ok {
+		return nil, storage.ErrRefreshTokenNotFound
+	}
+
+	return token, nil
+}
+
+func (r *fakeRepository) RevokeRefreshTokenFamily(familyId string) error {
+	for _, token := range r.refreshTokens {
+		if token.FamilyId == familyId {
+			token.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) RevokeToken(jti string, expiration time.Time) error {
+	r.revoked[jti] = true
+
+	return nil
+}
+
+func (r *fakeRepository) TokenRevoked(jti string) (bool, error) {
+	return r.revoked[jti], nil
+}
+
+func (r *fakeRepository) DeleteExpiredRevokedTokens() error {
+	return nil
+}
+
+type fakeClients map[string]*models.OAuthClient
+
+func (c fakeClients) AuthenticateClient(clientId, clientSecret string) (*models.OAuthClient, error) {
+	client, ok := c[clientId]
+	if !ok || clientSecret != "secret" {
+		return nil, oauth.ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+type fakeSessions struct{}
+
+func (fakeSessions) Validate(sessionId string) error {
+	return nil
+}
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func newService(t *testing.T, repository *fakeRepository) (*Service, *staticKeys) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := &staticKeys{key: &jwt.Key{Kid: "test", Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+	clients := fakeClients{
+		"partner":  {Id: "partner"},
+		"other":    {Id: "other"},
+		"gateway":  {Id: "gateway", ServiceAccount: true, Permissions: []string{models.PermissionTokensIntrospectAny}},
+		"mobile":   {Id: "mobile", Public: true},
+		"resource": {Id: "resource", ServiceAccount: true},
+	}
+
+	return New(repository, clients, fakeSessions{}, keys), keys
+}
+
+func TestIntrospectIsLimitedToTokenAudience(t *testing.T) {
+	repository := newFakeRepository()
+	service, keys := newService(t, repository)
+
+	partnerToken, err := jwt.NewClientToken(models.User{Id: 1, Role: models.JobSeeker}, "session", "partner", "openid", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	firstPartyToken, err := jwt.NewToken(models.User{Id: 1, Role: models.JobSeeker}, "session", nil, keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		clientId string
+		token    string
+		active   bool
+	}{
+		{name: "own token", clientId: "partner", token: partnerToken, active: true},
+		{name: "other client token", clientId: "other", token: partnerToken, active: false},
+		{name: "first party token without permission", clientId: "resource", token: firstPartyToken, active: false},
+		{name: "first party token with permission", clientId: "gateway", token: firstPartyToken, active: true},
+		{name: "client token with permission", clientId: "gateway", token: partnerToken, active: true},
+	}
+
+	for _, tt := range tests {
+		result, err := service.Introspect(tt.clientId, "secret", tt.token, "")
+		if err != nil {
+			t.Fatalf("%s: Introspect() error = %v", tt.name, err)
+		}
+
+		if result.Active != tt.active {
+			t.Errorf("%s: active = %v, want %v", tt.name, result.Active, tt.active)
+		}
+
+		if !result.Active && result.UserId != 0 {
+			t.Errorf("%s: inactive result leaks user %d", tt.name, result.UserId)
+		}
+	}
+}
+
+func TestIntrospectRejectsPublicAndUnknownClients(t *testing.T) {
+	repository := newFakeRepository()
+	service, _ := newService(t, repository)
+
+	if _, err := service.Introspect("mobile", "secret", "token", ""); !errors.Is(err, ErrUnauthorizedClient) {
+		t.Errorf("Introspect(public client) error = %v, want %v", err, ErrUnauthorizedClient)
+	}
+
+	if _, err := service.Introspect("partner", "wrong", "token", ""); !errors.Is(err, oauth.ErrInvalidClient) {
+		t.Errorf("Introspect(wrong secret) error = %v, want %v", err, oauth.ErrInvalidClient)
+	}
+}
+
+func TestRevokeOnlyAffectsOwnTokens(t *testing.T) {
+	repository := newFakeRepository()
+	service, _ := newService(t, repository)
+
+	repository.refreshTokens[opaque.Hash("refresh")] = &models.RefreshToken{
+		UserId:     1,
+		FamilyId:   "family",
+		ClientId:   "partner",
+		Expiration: time.Now().Add(time.Hour),
+	}
+
+	if err := service.Revoke("other", "secret", "refresh", models.TokenTypeRefresh); err != nil {
+		t.Fatalf("Revoke(other client) error = %v", err)
+	}
+
+	if repository.refreshTokens[opaque.Hash("refresh")].Revoked {
+		t.Fatal("another client revoked the refresh token")
+	}
+
+	if err := service.Revoke("partner", "secret", "refresh", models.TokenTypeRefresh); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	result, err := service.Introspect("partner", "secret", "refresh", models.TokenTypeRefresh)
+	if err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+
+	if result.Active {
+		t.Error("revoked refresh token is still active")
+	}
+}
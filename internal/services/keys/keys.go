@@ -0,0 +1,251 @@
+package keys
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwk"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/storage"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	rsaKeySize = 2048
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidKeyTtl        = errors.New("key ttl must cover rotation interval, refresh interval, publish delay and token ttl")
+	ErrInvalidPublishDelay  = errors.New("publish delay must cover jwks cache max age and refresh interval")
+)
+
+type Repository interface {
+	RotateSigningKey(kid, algorithm string, privateKey []byte, createdAt, expiresAt, rotatedAfter time.Time) error
+	SigningKeys() ([]models.SigningKey, error)
+}
+
+type Sealer interface {
+	Seal(plaintext, additionalData []byte) ([]byte, error)
+	Open(sealed, additionalData []byte) ([]byte, error)
+}
+
+type signingKey struct {
+	key       *jwt.Key
+	createdAt time.Time
+}
+
+type Service struct {
+	repository       Repository
+	sealer           Sealer
+	algorithm        string
+	rotationInterval time.Duration
+	refreshInterval  time.Duration
+	publishDelay     time.Duration
+	keyTtl           time.Duration
+
+	mu          sync.RWMutex
+	signingKeys []signingKey
+	keys        map[string]*jwt.Key
+}
+
+func New(repository Repository, sealer Sealer, algorithm string, rotationInterval, refreshInterval, publishDelay, keyTtl, tokenTtl time.Duration) (*Service, error) {
+	switch algorithm {
+	case jwt.AlgorithmRS256, jwt.AlgorithmES256, jwt.AlgorithmEdDSA:
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	if publishDelay < jwk.CacheMaxAge || publishDelay < refreshInterval {
+		return nil, ErrInvalidPublishDelay
+	}
+
+	if keyTtl <= rotationInterval+refreshInterval+publishDelay+tokenTtl {
+		return nil, ErrInvalidKeyTtl
+	}
+
+	return &Service{
+		repository:       repository,
+		sealer:           sealer,
+		algorithm:        algorithm,
+		rotationInterval: rotationInterval,
+		refreshInterval:  refreshInterval,
+		publishDelay:     publishDelay,
+		keyTtl:           keyTtl,
+		keys:             make(map[string]*jwt.Key),
+	}, nil
+}
+
+func (s *Service) Run(log *slog.Logger) {
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.Load(); err != nil {
+			log.Error("failed to refresh signing keys", sl.Err(err))
+		}
+	}
+}
+
+func (s *Service) Load() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	rotate := len(s.signingKeys) == 0 || time.Since(s.signingKeys[0].createdAt) >= s.rotationInterval
+	s.mu.RUnlock()
+
+	if rotate {
+		return s.Rotate()
+	}
+
+	return nil
+}
+
+func (s *Service) Rotate() error {
+	privateKey, err := generateKey(s.algorithm)
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	kid := uuid.New().String()
+
+	sealed, err := s.sealer.Seal(der, []byte(kid))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.repository.RotateSigningKey(kid, s.algorithm, sealed, now, now.Add(s.keyTtl), now.Add(-s.rotationInterval))
+	if err != nil && !errors.Is(err, storage.ErrSigningKeyRotated) {
+		return err
+	}
+
+	return s.load()
+}
+
+func (s *Service) SigningKey() (*jwt.Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.signingKeys) == 0 {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	publishedBefore := time.Now().Add(-s.publishDelay)
+	for _, key := range s.signingKeys {
+		if !key.createdAt.After(publishedBefore) {
+			return key.key, nil
+		}
+	}
+
+	return s.signingKeys[len(s.signingKeys)-1].key, nil
+}
+
+func (s *Service) VerificationKey(kid string) (*jwt.Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return key, nil
+}
+
+func (s *Service) PublicKeys() (*jwk.Set, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := &jwk.Set{Keys: make([]jwk.Key, 0, len(s.keys))}
+	for _, key := range s.keys {
+		publicKey, err := jwk.FromPublicKey(key.Kid, key.Algorithm, key.PrivateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+
+		set.Keys = append(set.Keys, publicKey)
+	}
+
+	return set, nil
+}
+
+func (s *Service) load() error {
+	stored, err := s.repository.SigningKeys()
+	if err != nil {
+		return err
+	}
+
+	var signingKeys []signingKey
+
+	keys := make(map[string]*jwt.Key, len(stored))
+	for _, storedKey := range stored {
+		der, err := s.sealer.Open(storedKey.PrivateKey, []byte(storedKey.Kid))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt signing key %s: %w", storedKey.Kid, err)
+		}
+
+		privateKey, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return fmt.Errorf("failed to parse signing key %s: %w", storedKey.Kid, err)
+		}
+
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("failed to parse signing key %s: %w", storedKey.Kid, ErrUnsupportedAlgorithm)
+		}
+
+		key := &jwt.Key{Kid: storedKey.Kid, Algorithm: storedKey.Algorithm, PrivateKey: signer}
+		keys[key.Kid] = key
+
+		if storedKey.Algorithm == s.algorithm {
+			signingKeys = append(signingKeys, signingKey{key: key, createdAt: storedKey.CreatedAt})
+		}
+	}
+
+	sort.Slice(signingKeys, func(i, j int) bool {
+		return signingKeys[i].createdAt.After(signingKeys[j].createdAt)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+	s.signingKeys = signingKeys
+
+	return nil
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case jwt.AlgorithmRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case jwt.AlgorithmES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case jwt.AlgorithmEdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+		return privateKey, err
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
@@ -0,0 +1,182 @@
+package keys
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/sealer"
+	"auth/internal/storage"
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	rotationInterval = 720 * time.Hour
+	refreshInterval  = time.Minute
+	publishDelay     = 10 * time.Minute
+	keyTtl           = 1440 * time.Hour
+	tokenTtl         = 15 * time.Minute
+)
+
+type fakeRepository struct {
+	keys []models.SigningKey
+}
+
+func (r *fakeRepository) RotateSigningKey(kid, algorithm string, privateKey []byte, createdAt, expiresAt, rotatedAfter time.Time) error {
+	for _, key := range r.keys {
+		if key.Algorithm == algorithm && key.CreatedAt.After(rotatedAfter) {
+			return storage.ErrSigningKeyRotated
+		}
+	}
+
+	r.keys = append(r.keys, models.SigningKey{Kid: kid, Algorithm: algorithm, PrivateKey: privateKey, CreatedAt: createdAt, ExpiresAt: expiresAt})
+
+	return nil
+}
+
+func (r *fakeRepository) SigningKeys() ([]models.SigningKey, error) {
+	return r.keys, nil
+}
+
+func (r *fakeRepository) age(kid string, d time.Duration) {
+	for i := range r.keys {
+		if r.keys[i].Kid == kid {
+			r.keys[i].CreatedAt = r.keys[i].CreatedAt.Add(-d)
+		}
+	}
+}
+
+func newService(t *testing.T, repository *fakeRepository) *Service {
+	t.Helper()
+
+	keysSealer, err := sealer.New(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := New(repository, keysSealer, jwt.AlgorithmES256, rotationInterval, refreshInterval, publishDelay, keyTtl, tokenTtl)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return service
+}
+
+func TestNewValidatesIntervals(t *testing.T) {
+	if _, err := New(&fakeRepository{}, nil, jwt.AlgorithmES256, rotationInterval, refreshInterval, time.Minute, keyTtl, tokenTtl); !errors.Is(err, ErrInvalidPublishDelay) {
+		t.Errorf("New(short publish delay) error = %v, want %v", err, ErrInvalidPublishDelay)
+	}
+
+	if _, err := New(&fakeRepository{}, nil, jwt.AlgorithmES256, rotationInterval, refreshInterval, publishDelay, rotationInterval+publishDelay, tokenTtl); !errors.Is(err, ErrInvalidKeyTtl) {
+		t.Errorf("New(key ttl without token ttl) error = %v, want %v", err, ErrInvalidKeyTtl)
+	}
+
+	if _, err := New(&fakeRepository{}, nil, "HS256", rotationInterval, refreshInterval, publishDelay, keyTtl, tokenTtl); !errors.Is(err, ErrUnsupportedAlgorithm) {
+		t.Errorf("New(HS256) error = %v, want %v", err, ErrUnsupportedAlgorithm)
+	}
+}
+
+func TestLoadStoresEncryptedKey(t *testing.T) {
+	repository := &fakeRepository{}
+	service := newService(t, repository)
+
+	if err := service.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(repository.keys) != 1 {
+		t.Fatalf("stored keys = %d, want 1", len(repository.keys))
+	}
+
+	if _, err := x509.ParsePKCS8PrivateKey(repository.keys[0].PrivateKey); err == nil {
+		t.Error("signing key is stored unencrypted")
+	}
+
+	key, err := service.SigningKey()
+	if err != nil {
+		t.Fatalf("SigningKey() error = %v", err)
+	}
+
+	if key.Kid != repository.keys[0].Kid {
+		t.Errorf("SigningKey() kid = %s, want %s", key.Kid, repository.keys[0].Kid)
+	}
+}
+
+func TestRotationPublishesBeforeSigning(t *testing.T) {
+	repository := &fakeRepository{}
+	service := newService(t, repository)
+
+	if err := service.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	oldKid := repository.keys[0].Kid
+	repository.age(oldKid, rotationInterval)
+
+	if err := service.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(repository.keys) != 2 {
+		t.Fatalf("stored keys = %d, want 2", len(repository.keys))
+	}
+
+	newKid := repository.keys[1].Kid
+
+	if _, err := service.VerificationKey(newKid); err != nil {
+		t.Errorf("new key is not published: %v", err)
+	}
+
+	set, err := service.PublicKeys()
+	if err != nil {
+		t.Fatalf("PublicKeys() error = %v", err)
+	}
+
+	if len(set.Keys) != 2 {
+		t.Errorf("jwks keys = %d, want 2", len(set.Keys))
+	}
+
+	key, err := service.SigningKey()
+	if err != nil {
+		t.Fatalf("SigningKey() error = %v", err)
+	}
+
+	if key.Kid != oldKid {
+		t.Errorf("SigningKey() kid = %s before publish delay, want old key %s", key.Kid, oldKid)
+	}
+
+	repository.age(newKid, publishDelay)
+
+	if err := service.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	key, err = service.SigningKey()
+	if err != nil {
+		t.Fatalf("SigningKey() error = %v", err)
+	}
+
+	if key.Kid != newKid {
+		t.Errorf("SigningKey() kid = %s after publish delay, want new key %s", key.Kid, newKid)
+	}
+}
+
+func TestRotateSkipsWhenAlreadyRotated(t *testing.T) {
+	repository := &fakeRepository{}
+	service := newService(t, repository)
+
+	if err := service.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if err := service.Rotate(); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+
+	if len(repository.keys) != 1 {
+		t.Errorf("stored keys = %d, want 1", len(repository.keys))
+	}
+}
@@ -0,0 +1,173 @@
+package lockout
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	userKeyPrefix = "user:"
+	ipKeyPrefix   = "ip:"
+)
+
+var (
+	ErrAccountLocked   = errors.New("account is locked")
+	ErrTooManyAttempts = errors.New("too many login attempts")
+	EmptyUserErr       = errors.New("empty user")
+)
+
+type LockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return e.Err
+}
+
+type Repository interface {
+	LoginFailure(key string) (*models.LoginFailure, error)
+	IncrementLoginFailures(key string, failedAt, windowStart time.Time) (int, error)
+	LockLoginKey(key string, lockedUntil time.Time) error
+	DeleteLoginFailure(key string) error
+}
+
+type Service struct {
+	repository          Repository
+	maxFailedAttempts   int
+	ipMaxFailedAttempts int
+	failureWindow       time.Duration
+	lockoutDuration     time.Duration
+	progressiveDelay    time.Duration
+}
+
+func New(repository Repository, maxFailedAttempts, ipMaxFailedAttempts int, failureWindow, lockoutDuration, progressiveDelay time.Duration) *Service {
+	return &Service{
+		repository:          repository,
+		maxFailedAttempts:   maxFailedAttempts,
+		ipMaxFailedAttempts: ipMaxFailedAttempts,
+		failureWindow:       failureWindow,
+		lockoutDuration:     lockoutDuration,
+		progressiveDelay:    progressiveDelay,
+	}
+}
+
+func (s *Service) Check(userId int64, ip string) error {
+	if userId != 0 {
+		if err := s.check(userKey(userId), ErrAccountLocked); err != nil {
+			return err
+		}
+	}
+
+	if ip != "" {
+		if err := s.check(ipKey(ip), ErrTooManyAttempts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) RegisterFailure(userId int64, ip string) error {
+	if userId != 0 {
+		if err := s.registerFailure(userKey(userId), s.maxFailedAttempts); err != nil {
+			return err
+		}
+	}
+
+	if ip != "" {
+		if err := s.registerFailure(ipKey(ip), s.ipMaxFailedAttempts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) RegisterSuccess(userId int64) error {
+	return s.Unlock(userId)
+}
+
+func (s *Service) Unlock(userId int64) error {
+	if userId == 0 {
+		return EmptyUserErr
+	}
+
+	return s.repository.DeleteLoginFailure(userKey(userId))
+}
+
+func (s *Service) check(key string, lockedErr error) error {
+	loginFailure, err := s.repository.LoginFailure(key)
+	if errors.Is(err, storage.ErrLoginFailureNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if loginFailure.LockedUntil != nil && now.Before(*loginFailure.LockedUntil) {
+		return &LockedError{Err: lockedErr, RetryAfter: loginFailure.LockedUntil.Sub(now)}
+	}
+
+	if now.Sub(loginFailure.LastFailureAt) > s.failureWindow {
+		return nil
+	}
+
+	allowedAt := loginFailure.LastFailureAt.Add(s.delay(loginFailure.Failures))
+	if now.Before(allowedAt) {
+		return &LockedError{Err: ErrTooManyAttempts, RetryAfter: allowedAt.Sub(now)}
+	}
+
+	return nil
+}
+
+func (s *Service) registerFailure(key string, maxFailedAttempts int) error {
+	now := time.Now()
+
+	failures, err := s.repository.IncrementLoginFailures(key, now, now.Add(-s.failureWindow))
+	if err != nil {
+		return err
+	}
+
+	if failures >= maxFailedAttempts {
+		if err := s.repository.LockLoginKey(key, now.Add(s.lockoutDuration)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) delay(failures int) time.Duration {
+	if failures <= 0 || s.progressiveDelay <= 0 {
+		return 0
+	}
+
+	delay := s.progressiveDelay
+	for i := 1; i < failures && delay < s.lockoutDuration; i++ {
+		delay *= 2
+	}
+
+	if delay > s.lockoutDuration {
+		return s.lockoutDuration
+	}
+
+	return delay
+}
+
+func userKey(userId int64) string {
+	return fmt.Sprintf("%s%d", userKeyPrefix, userId)
+}
+
+func ipKey(ip string) string {
+	return ipKeyPrefix + ip
+}
@@ -0,0 +1,137 @@
+package lockout
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRepository struct {
+	failures map[string]*models.LoginFailure
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{failures: make(map[string]*models.LoginFailure)}
+}
+
+func (r *fakeRepository) LoginFailure(key string) (*models.LoginFailure, error) {
+	failure, ok := r.failures[key]
+	if !ok {
+		return nil, storage.ErrLoginFailureNotFound
+	}
+
+	copied := *failure
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) IncrementLoginFailures(key string, failedAt, windowStart time.Time) (int, error) {
+	failure, ok := r.failures[key]
+	if !ok || failure.LastFailureAt.Before(windowStart) {
+		failure = &models.LoginFailure{Key: key}
+		r.failures[key] = failure
+	}
+
+	failure.Failures++
+	failure.LastFailureAt = failedAt
+
+	return failure.Failures, nil
+}
+
+func (r *fakeRepository) LockLoginKey(key string, lockedUntil time.Time) error {
+	r.failures[key].LockedUntil = &lockedUntil
+
+	return nil
+}
+
+func (r *fakeRepository) DeleteLoginFailure(key string) error {
+	delete(r.failures, key)
+
+	return nil
+}
+
+func TestAccountLocksAfterMaxFailures(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, 3, 100, 15*time.Minute, 15*time.Minute, 0)
+
+	for i := 0; i < 3; i++ {
+		if err := service.Check(1, "203.0.113.5"); err != nil {
+			t.Fatalf("Check() before lockout error = %v", err)
+		}
+
+		if err := service.RegisterFailure(1, "203.0.113.5"); err != nil {
+			t.Fatalf("RegisterFailure() error = %v", err)
+		}
+	}
+
+	err := service.Check(1, "198.51.100.1")
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Check() error = %v, want %v", err, ErrAccountLocked)
+	}
+
+	var lockedErr *LockedError
+	if !errors.As(err, &lockedErr) || lockedErr.RetryAfter <= 0 {
+		t.Errorf("Check() retry after is not set: %v", err)
+	}
+
+	if err := service.Check(2, "198.51.100.1"); err != nil {
+		t.Errorf("Check(other account) error = %v", err)
+	}
+
+	if err := service.RegisterSuccess(1); err != nil {
+		t.Fatalf("RegisterSuccess() error = %v", err)
+	}
+
+	if err := service.Check(1, "198.51.100.1"); err != nil {
+		t.Errorf("Check() after success error = %v", err)
+	}
+}
+
+func TestIpLocksAfterMaxFailures(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, 100, 2, 15*time.Minute, 15*time.Minute, 0)
+
+	for userId := int64(1); userId <= 2; userId++ {
+		if err := service.RegisterFailure(userId, "203.0.113.5"); err != nil {
+			t.Fatalf("RegisterFailure() error = %v", err)
+		}
+	}
+
+	if err := service.Check(3, "203.0.113.5"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Check() error = %v, want %v", err, ErrTooManyAttempts)
+	}
+
+	if err := service.Check(3, "198.51.100.1"); err != nil {
+		t.Errorf("Check(other ip) error = %v", err)
+	}
+}
+
+func TestProgressiveDelay(t *testing.T) {
+	repository := newFakeRepository()
+	service := New(repository, 10, 100, 15*time.Minute, 15*time.Minute, time.Minute)
+
+	if err := service.RegisterFailure(1, ""); err != nil {
+		t.Fatalf("RegisterFailure() error = %v", err)
+	}
+
+	var lockedErr *LockedError
+	if err := service.Check(1, ""); !errors.As(err, &lockedErr) || !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("Check() error = %v, want delay", err)
+	}
+
+	repository.failures[userKey(1)].LastFailureAt = time.Now().Add(-time.Minute)
+
+	if err := service.Check(1, ""); err != nil {
+		t.Errorf("Check() after delay error = %v", err)
+	}
+
+	if got := service.delay(3); got != 4*time.Minute {
+		t.Errorf("delay(3) = %s, want %s", got, 4*time.Minute)
+	}
+
+	if got := service.delay(10); got != 15*time.Minute {
+		t.Errorf("delay(10) = %s, want lockout duration", got)
+	}
+}
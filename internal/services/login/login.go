@@ -0,0 +1,204 @@
+package login
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/sl"
+	"auth/internal/services/mfa"
+	"auth/internal/storage"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+type UserService interface {
+	UserByContactInfo(contactInfo string) (*models.User, error)
+	UserByUserId(userId int64) (*models.User, error)
+	CheckContactInfo(user *models.User, contactInfo string) error
+	Authorize(user *models.User, password string) error
+}
+
+type TokenService interface {
+	Issue(user *models.User, device, ip string) (*models.TokenPair, error)
+	Refresh(refreshToken string) (*models.TokenPair, error)
+}
+
+type MfaService interface {
+	Enabled(userId int64) (bool, error)
+	Challenge(userId int64) (string, error)
+	ResolveChallenge(mfaToken string) (*models.MfaChallenge, error)
+	RedeemChallenge(challenge *models.MfaChallenge) error
+	VerifyCode(userId int64, code string) error
+	VerifyRecoveryCode(userId int64, recoveryCode string) error
+}
+
+type EmailVerifier interface {
+	CheckPolicy(user *models.User) error
+}
+
+type LoginGuard interface {
+	Check(userId int64, ip string) error
+	RegisterFailure(userId int64, ip string) error
+	RegisterSuccess(userId int64) error
+}
+
+type Result struct {
+	Tokens   *models.TokenPair
+	MfaToken string
+}
+
+type Service struct {
+	log           *slog.Logger
+	userService   UserService
+	tokenService  TokenService
+	mfaService    MfaService
+	emailVerifier EmailVerifier
+	loginGuard    LoginGuard
+}
+
+func New(log *slog.Logger, userService UserService, tokenService TokenService, mfaService MfaService, emailVerifier EmailVerifier, loginGuard LoginGuard) *Service {
+	return &Service{
+		log:           log,
+		userService:   userService,
+		tokenService:  tokenService,
+		mfaService:    mfaService,
+		emailVerifier: emailVerifier,
+		loginGuard:    loginGuard,
+	}
+}
+
+func (s *Service) Login(contactInfo, password, device, ip string) (*Result, error) {
+	const op = "services.login.Login"
+
+	if err := s.loginGuard.Check(0, ip); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userService.UserByContactInfo(contactInfo)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		s.registerFailure(0, ip)
+
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidCredentials, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.loginGuard.Check(user.Id, ""); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.userService.Authorize(user, password); err != nil {
+		s.registerFailure(user.Id, ip)
+
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidCredentials, err)
+	}
+
+	if err := s.userService.CheckContactInfo(user, contactInfo); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.emailVerifier.CheckPolicy(user); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mfaEnabled, err := s.mfaService.Enabled(user.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if mfaEnabled {
+		mfaToken, err := s.mfaService.Challenge(user.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return &Result{MfaToken: mfaToken}, nil
+	}
+
+	tokens, err := s.issue(user, device, ip)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Result{Tokens: tokens}, nil
+}
+
+func (s *Service) LoginMfa(mfaToken, code, recoveryCode, device, ip string) (*models.TokenPair, error) {
+	const op = "services.login.LoginMfa"
+
+	challenge, err := s.mfaService.ResolveChallenge(mfaToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.loginGuard.Check(challenge.UserId, ip); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if code != "" {
+		err = s.mfaService.VerifyCode(challenge.UserId, code)
+	} else {
+		err = s.mfaService.VerifyRecoveryCode(challenge.UserId, recoveryCode)
+	}
+
+	if errors.Is(err, mfa.ErrInvalidCode) || errors.Is(err, mfa.ErrTotpNotEnrolled) {
+		s.registerFailure(challenge.UserId, ip)
+
+		return nil, fmt.Errorf("%s: %w: %w", op, mfa.ErrInvalidCode, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.mfaService.RedeemChallenge(challenge); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.userService.UserByUserId(challenge.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := s.issue(user, device, ip)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (s *Service) Refresh(refreshToken string) (*models.TokenPair, error) {
+	const op = "services.login.Refresh"
+
+	tokens, err := s.tokenService.Refresh(refreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (s *Service) issue(user *models.User, device, ip string) (*models.TokenPair, error) {
+	tokens, err := s.tokenService.Issue(user, device, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.RegisterSuccess(user.Id); err != nil {
+		s.log.Error("failed to reset login failures", slog.Int64("user_id", user.Id), sl.Err(err))
+	}
+
+	return tokens, nil
+}
+
+func (s *Service) registerFailure(userId int64, ip string) {
+	if err := s.loginGuard.RegisterFailure(userId, ip); err != nil {
+		s.log.Error("failed to register login failure", slog.Int64("user_id", userId), sl.Err(err))
+	}
+}
@@ -0,0 +1,279 @@
+package login
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"auth/internal/services/auth"
+	"auth/internal/services/lockout"
+	"auth/internal/services/mfa"
+	"auth/internal/storage"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errInvalidPassword = errors.New("invalid password")
+
+type fakeUsers struct {
+	users map[string]*models.User
+}
+
+func (u *fakeUsers) UserByContactInfo(contactInfo string) (*models.User, error) {
+	user, ok := u.users[contactInfo]
+	if !ok {
+		return nil, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (u *fakeUsers) UserByUserId(userId int64) (*models.User, error) {
+	for _, user := range u.users {
+		if user.Id == userId {
+			return user, nil
+		}
+	}
+
+	return nil, storage.ErrUserNotFound
+}
+
+func (u *fakeUsers) CheckContactInfo(user *models.User, contactInfo string) error {
+	if contactInfo == user.Phone && user.PhoneVerifiedAt == nil {
+		return auth.ErrPhoneNotVerified
+	}
+
+	return nil
+}
+
+func (u *fakeUsers) Authorize(user *models.User, password string) error {
+	if string(user.PassHash) != password {
+		return errInvalidPassword
+	}
+
+	return nil
+}
+
+type fakeTokens struct {
+	issued int
+}
+
+func (t *fakeTokens) Issue(user *models.User, device, ip string) (*models.TokenPair, error) {
+	t.issued++
+
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func (t *fakeTokens) Refresh(refreshToken string) (*models.TokenPair, error) {
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+type fakeMfa struct {
+	enabled  bool
+	redeemed bool
+}
+
+func (m *fakeMfa) Enabled(userId int64) (bool, error) {
+	return m.enabled, nil
+}
+
+func (m *fakeMfa) Challenge(userId int64) (string, error) {
+	return "challenge", nil
+}
+
+func (m *fakeMfa) ResolveChallenge(mfaToken string) (*models.MfaChallenge, error) {
+	if mfaToken != "challenge" || m.redeemed {
+		return nil, mfa.ErrInvalidMfaToken
+	}
+
+	return &models.MfaChallenge{Id: "jti", UserId: 1}, nil
+}
+
+func (m *fakeMfa) RedeemChallenge(challenge *models.MfaChallenge) error {
+	if m.redeemed {
+		return mfa.ErrInvalidMfaToken
+	}
+
+	m.redeemed = true
+
+	return nil
+}
+
+func (m *fakeMfa) VerifyCode(userId int64, code string) error {
+	if code != "123456" {
+		return mfa.ErrInvalidCode
+	}
+
+	return nil
+}
+
+func (m *fakeMfa) VerifyRecoveryCode(userId int64, recoveryCode string) error {
+	return mfa.ErrTotpNotEnrolled
+}
+
+type fakeEmailVerifier struct{}
+
+func (fakeEmailVerifier) CheckPolicy(user *models.User) error {
+	return nil
+}
+
+type fakeGuard struct {
+	failures  int
+	successes int
+	locked    bool
+}
+
+func (g *fakeGuard) Check(userId int64, ip string) error {
+	if g.locked {
+		return &lockout.LockedError{Err: lockout.ErrAccountLocked, RetryAfter: time.Minute}
+	}
+
+	return nil
+}
+
+func (g *fakeGuard) RegisterFailure(userId int64, ip string) error {
+	g.failures++
+
+	return nil
+}
+
+func (g *fakeGuard) RegisterSuccess(userId int64) error {
+	g.successes++
+
+	return nil
+}
+
+func newService(mfaService *fakeMfa, tokens *fakeTokens, guard *fakeGuard) *Service {
+	users := &fakeUsers{users: map[string]*models.User{
+		"user@example.com": {Id: 1, Email: "user@example.com", PassHash: []byte("secret")},
+		"+79990000000":     {Id: 2, Phone: "+79990000000", PassHash: []byte("secret")},
+	}}
+
+	return New(slogdiscard.NewDiscardLogger(), users, tokens, mfaService, fakeEmailVerifier{}, guard)
+}
+
+func TestLoginHidesUnknownContacts(t *testing.T) {
+	guard := &fakeGuard{}
+	service := newService(&fakeMfa{}, &fakeTokens{}, guard)
+
+	tests := []struct {
+		name        string
+		contactInfo string
+		password    string
+	}{
+		{name: "unknown contact", contactInfo: "other@example.com", password: "secret"},
+		{name: "wrong password", contactInfo: "user@example.com", password: "wrong"},
+	}
+
+	for _, tt := range tests {
+		if _, err := service.Login(tt.contactInfo, tt.password, "", "127.0.0.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, ErrInvalidCredentials)
+		}
+	}
+
+	if guard.failures != 2 {
+		t.Errorf("failures = %d, want 2", guard.failures)
+	}
+}
+
+func TestLoginChecksPasswordBeforePhoneVerification(t *testing.T) {
+	guard := &fakeGuard{}
+	service := newService(&fakeMfa{}, &fakeTokens{}, guard)
+
+	if _, err := service.Login("+79990000000", "wrong", "", "127.0.0.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	if _, err := service.Login("+79990000000", "secret", "", "127.0.0.1"); !errors.Is(err, auth.ErrPhoneNotVerified) {
+		t.Errorf("unverified phone: error = %v, want %v", err, auth.ErrPhoneNotVerified)
+	}
+
+	if guard.failures != 1 {
+		t.Errorf("failures = %d, want 1", guard.failures)
+	}
+}
+
+func TestLoginResetsFailuresOnlyAfterIssuingTokens(t *testing.T) {
+	guard := &fakeGuard{}
+	tokens := &fakeTokens{}
+	service := newService(&fakeMfa{enabled: true}, tokens, guard)
+
+	result, err := service.Login("user@example.com", "secret", "", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("mfa login: error = %v", err)
+	}
+
+	if result.MfaToken != "challenge" || result.Tokens != nil {
+		t.Errorf("mfa login: result = %+v, want mfa challenge", result)
+	}
+
+	if guard.successes != 0 || tokens.issued != 0 {
+		t.Errorf("mfa pending: successes = %d, issued = %d, want 0 and 0", guard.successes, tokens.issued)
+	}
+
+	guard = &fakeGuard{}
+	service = newService(&fakeMfa{}, tokens, guard)
+
+	result, err = service.Login("user@example.com", "secret", "", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("login: error = %v", err)
+	}
+
+	if result.Tokens == nil || result.MfaToken != "" {
+		t.Errorf("login: result = %+v, want tokens", result)
+	}
+
+	if guard.successes != 1 {
+		t.Errorf("login: successes = %d, want 1", guard.successes)
+	}
+}
+
+func TestLoginRespectsLockout(t *testing.T) {
+	tokens := &fakeTokens{}
+	service := newService(&fakeMfa{}, tokens, &fakeGuard{locked: true})
+
+	_, err := service.Login("user@example.com", "secret", "", "127.0.0.1")
+
+	var lockedErr *lockout.LockedError
+	if !errors.As(err, &lockedErr) {
+		t.Errorf("error = %v, want locked error", err)
+	}
+
+	if tokens.issued != 0 {
+		t.Errorf("issued = %d, want 0", tokens.issued)
+	}
+}
+
+func TestLoginMfa(t *testing.T) {
+	guard := &fakeGuard{}
+	tokens := &fakeTokens{}
+	service := newService(&fakeMfa{enabled: true}, tokens, guard)
+
+	if _, err := service.LoginMfa("challenge", "000000", "", "", "127.0.0.1"); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Errorf("wrong code: error = %v, want %v", err, mfa.ErrInvalidCode)
+	}
+
+	if _, err := service.LoginMfa("challenge", "", "recovery", "", "127.0.0.1"); !errors.Is(err, mfa.ErrInvalidCode) {
+		t.Errorf("not enrolled: error = %v, want %v", err, mfa.ErrInvalidCode)
+	}
+
+	if guard.failures != 2 || guard.successes != 0 {
+		t.Errorf("wrong codes: failures = %d, successes = %d, want 2 and 0", guard.failures, guard.successes)
+	}
+
+	pair, err := service.LoginMfa("challenge", "123456", "", "", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("valid code: error = %v", err)
+	}
+
+	if pair.AccessToken != "access" || tokens.issued != 1 || guard.successes != 1 {
+		t.Errorf("valid code: pair = %+v, issued = %d, successes = %d", pair, tokens.issued, guard.successes)
+	}
+
+	if _, err := service.LoginMfa("challenge", "123456", "", "", "127.0.0.1"); !errors.Is(err, mfa.ErrInvalidMfaToken) {
+		t.Errorf("reused challenge: error = %v, want %v", err, mfa.ErrInvalidMfaToken)
+	}
+
+	if tokens.issued != 1 {
+		t.Errorf("reused challenge: issued = %d, want 1", tokens.issued)
+	}
+}
@@ -0,0 +1,231 @@
+package mfa
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/opaque"
+	"auth/internal/lib/totp"
+	"auth/internal/storage"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeSize = 10
+)
+
+var (
+	ErrTotpAlreadyEnabled = errors.New("totp is already enabled")
+	ErrTotpNotEnrolled    = errors.New("totp is not enrolled")
+	ErrInvalidCode        = errors.New("invalid code")
+	ErrInvalidMfaToken    = errors.New("invalid mfa token")
+	EmptyCodeErr          = errors.New("code is empty")
+	EmptyUserErr          = errors.New("empty user")
+)
+
+var (
+	recoveryCodeEncoding   = base32.StdEncoding.WithPadding(base32.NoPadding)
+	recoveryCodeNormalizer = strings.NewReplacer("-", "", " ", "")
+)
+
+type Repository interface {
+	SaveTotpSecret(userId int64, secret string) error
+	TotpSecret(userId int64) (*models.TotpSecret, error)
+	ConfirmTotp(userId int64, recoveryCodeHashes []string) error
+	UseTotpStep(userId int64, step int64) error
+	UseRecoveryCode(userId int64, codeHash string) error
+	UseMfaChallenge(jti string, expiration time.Time) error
+	MfaChallengeUsed(jti string) (bool, error)
+}
+
+type Service struct {
+	repository         Repository
+	keys               jwt.KeyProvider
+	issuer             string
+	challengeTtl       time.Duration
+	recoveryCodesCount int
+}
+
+func New(repository Repository, keys jwt.KeyProvider, issuer string, challengeTtl time.Duration, recoveryCodesCount int) *Service {
+	return &Service{
+		repository:         repository,
+		keys:               keys,
+		issuer:             issuer,
+		challengeTtl:       challengeTtl,
+		recoveryCodesCount: recoveryCodesCount,
+	}
+}
+
+func (s *Service) Enroll(userId int64, accountName string) (string, string, error) {
+	if userId == 0 {
+		return "", "", EmptyUserErr
+	}
+
+	enabled, err := s.Enabled(userId)
+	if err != nil {
+		return "", "", err
+	}
+
+	if enabled {
+		return "", "", ErrTotpAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := s.repository.SaveTotpSecret(userId, secret); err != nil {
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.issuer, accountName, secret), nil
+}
+
+func (s *Service) Confirm(userId int64, code string) ([]string, error) {
+	if code == "" {
+		return nil, EmptyCodeErr
+	}
+
+	totpSecret, err := s.repository.TotpSecret(userId)
+	if errors.Is(err, storage.ErrTotpNotFound) {
+		return nil, ErrTotpNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if totpSecret.ConfirmedAt != nil {
+		return nil, ErrTotpAlreadyEnabled
+	}
+
+	if err := s.useCode(totpSecret, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, 0, s.recoveryCodesCount)
+	recoveryCodeHashes := make([]string, 0, s.recoveryCodesCount)
+	for i := 0; i < s.recoveryCodesCount; i++ {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		recoveryCodeHashes = append(recoveryCodeHashes, hashRecoveryCode(recoveryCode))
+	}
+
+	if err := s.repository.ConfirmTotp(userId, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *Service) Enabled(userId int64) (bool, error) {
+	totpSecret, err := s.repository.TotpSecret(userId)
+	if errors.Is(err, storage.ErrTotpNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return totpSecret.ConfirmedAt != nil, nil
+}
+
+func (s *Service) Challenge(userId int64) (string, error) {
+	return jwt.NewMfaToken(userId, s.keys, s.challengeTtl)
+}
+
+func (s *Service) ResolveChallenge(mfaToken string) (*models.MfaChallenge, error) {
+	challenge, err := jwt.ParseMfaToken(mfaToken, s.keys)
+	if err != nil {
+		return nil, ErrInvalidMfaToken
+	}
+
+	used, err := s.repository.MfaChallengeUsed(challenge.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if used {
+		return nil, ErrInvalidMfaToken
+	}
+
+	return challenge, nil
+}
+
+func (s *Service) RedeemChallenge(challenge *models.MfaChallenge) error {
+	err := s.repository.UseMfaChallenge(challenge.Id, challenge.ExpiresAt)
+	if errors.Is(err, storage.ErrMfaChallengeUsed) {
+		return ErrInvalidMfaToken
+	}
+
+	return err
+}
+
+func (s *Service) VerifyCode(userId int64, code string) error {
+	if code == "" {
+		return EmptyCodeErr
+	}
+
+	totpSecret, err := s.repository.TotpSecret(userId)
+	if errors.Is(err, storage.ErrTotpNotFound) {
+		return ErrTotpNotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+
+	if totpSecret.ConfirmedAt == nil {
+		return ErrTotpNotEnrolled
+	}
+
+	return s.useCode(totpSecret, code)
+}
+
+func (s *Service) VerifyRecoveryCode(userId int64, recoveryCode string) error {
+	if recoveryCode == "" {
+		return EmptyCodeErr
+	}
+
+	err := s.repository.UseRecoveryCode(userId, hashRecoveryCode(recoveryCode))
+	if errors.Is(err, storage.ErrRecoveryCodeNotFound) {
+		return ErrInvalidCode
+	}
+
+	return err
+}
+
+func (s *Service) useCode(totpSecret *models.TotpSecret, code string) error {
+	step, ok := totp.Validate(totpSecret.Secret, code, time.Now())
+	if !ok || step <= totpSecret.LastUsedStep {
+		return ErrInvalidCode
+	}
+
+	err := s.repository.UseTotpStep(totpSecret.UserId, step)
+	if errors.Is(err, storage.ErrTotpStepUsed) {
+		return ErrInvalidCode
+	}
+
+	return err
+}
+
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:recoveryCodeSize]
+
+	return code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:], nil
+}
+
+func hashRecoveryCode(recoveryCode string) string {
+	return opaque.Hash(strings.ToLower(recoveryCodeNormalizer.Replace(recoveryCode)))
+}
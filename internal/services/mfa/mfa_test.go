@@ -0,0 +1,249 @@
+package mfa
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/totp"
+	"auth/internal/storage"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRepository struct {
+	secrets       map[int64]*models.TotpSecret
+	recoveryCodes map[string]bool
+	challenges    map[string]bool
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		secrets:       make(map[int64]*models.TotpSecret),
+		recoveryCodes: make(map[string]bool),
+		challenges:    make(map[string]bool),
+	}
+}
+
+func (r *fakeRepository) SaveTotpSecret(userId int64, secret string) error {
+	r.secrets[userId] = &models.TotpSecret{UserId: userId, Secret: secret}
+
+	return nil
+}
+
+func (r *fakeRepository) TotpSecret(userId int64) (*models.TotpSecret, error) {
+	secret, ok := r.secrets[userId]
+	if !ok {
+		return nil, storage.ErrTotpNotFound
+	}
+
+	copied := *secret
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) ConfirmTotp(userId int64, recoveryCodeHashes []string) error {
+	now := time.Now()
+	r.secrets[userId].ConfirmedAt = &now
+
+	for _, hash := range recoveryCodeHashes {
+		r.recoveryCodes[hash] = false
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) UseTotpStep(userId int64, step int64) error {
+	if r.secrets[userId].LastUsedStep >= step {
+		return storage.ErrTotpStepUsed
+	}
+
+	r.secrets[userId].LastUsedStep = step
+
+	return nil
+}
+
+func (r *fakeRepository) UseRecoveryCode(userId int64, codeHash string) error {
+	used, ok := r.recoveryCodes[codeHash]
+	if !ok || used {
+		return storage.ErrRecoveryCodeNotFound
+	}
+
+	r.recoveryCodes[codeHash] = true
+
+	return nil
+}
+
+func (r *fakeRepository) UseMfaChallenge(jti string, expiration time.Time) error {
+	if r.challenges[jti] {
+		return storage.ErrMfaChallengeUsed
+	}
+
+	r.challenges[jti] = true
+
+	return nil
+}
+
+func (r *fakeRepository) MfaChallengeUsed(jti string) (bool, error) {
+	return r.challenges[jti], nil
+}
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	if kid != k.key.Kid {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return k.key, nil
+}
+
+func newService(t *testing.T, repository *fakeRepository) *Service {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := &staticKeys{key: &jwt.Key{Kid: "test", Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+
+	return New(repository, keys, "test", time.Minute, 2)
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func enroll(t *testing.T, service *Service, repository *fakeRepository, userId int64) []string {
+	t.Helper()
+
+	secret, _, err := service.Enroll(userId, "user@example.com")
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+
+	recoveryCodes, err := service.Confirm(userId, currentCode(t, secret))
+	if err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+
+	repository.secrets[userId].LastUsedStep = 0
+
+	return recoveryCodes
+}
+
+func TestVerifyCodeRejectsReplay(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+	enroll(t, service, repository, 1)
+
+	code := currentCode(t, repository.secrets[1].Secret)
+
+	if err := service.VerifyCode(1, code); err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+
+	if err := service.VerifyCode(1, code); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("VerifyCode(replayed) error = %v, want %v", err, ErrInvalidCode)
+	}
+
+	if err := service.VerifyCode(1, "not a code"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("VerifyCode(wrong) error = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestVerifyCodeRequiresConfirmedTotp(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	if err := service.VerifyCode(1, "123456"); !errors.Is(err, ErrTotpNotEnrolled) {
+		t.Errorf("VerifyCode(not enrolled) error = %v, want %v", err, ErrTotpNotEnrolled)
+	}
+
+	if _, _, err := service.Enroll(1, "user@example.com"); err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+
+	if err := service.VerifyCode(1, "123456"); !errors.Is(err, ErrTotpNotEnrolled) {
+		t.Errorf("VerifyCode(unconfirmed) error = %v, want %v", err, ErrTotpNotEnrolled)
+	}
+}
+
+func TestRecoveryCodeIsSingleUse(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+	recoveryCodes := enroll(t, service, repository, 1)
+
+	if len(recoveryCodes) != 2 {
+		t.Fatalf("recovery codes = %d, want 2", len(recoveryCodes))
+	}
+
+	if err := service.VerifyRecoveryCode(1, recoveryCodes[0]); err != nil {
+		t.Fatalf("VerifyRecoveryCode() error = %v", err)
+	}
+
+	if err := service.VerifyRecoveryCode(1, recoveryCodes[0]); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("VerifyRecoveryCode(reused) error = %v, want %v", err, ErrInvalidCode)
+	}
+}
+
+func TestChallengeIsSingleUse(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	mfaToken, err := service.Challenge(1)
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+
+	challenge, err := service.ResolveChallenge(mfaToken)
+	if err != nil {
+		t.Fatalf("ResolveChallenge() error = %v", err)
+	}
+
+	if challenge.UserId != 1 {
+		t.Errorf("ResolveChallenge() user = %d, want 1", challenge.UserId)
+	}
+
+	if err := service.RedeemChallenge(challenge); err != nil {
+		t.Fatalf("RedeemChallenge() error = %v", err)
+	}
+
+	if err := service.RedeemChallenge(challenge); !errors.Is(err, ErrInvalidMfaToken) {
+		t.Errorf("RedeemChallenge(redeemed) error = %v, want %v", err, ErrInvalidMfaToken)
+	}
+
+	if _, err := service.ResolveChallenge(mfaToken); !errors.Is(err, ErrInvalidMfaToken) {
+		t.Errorf("ResolveChallenge(redeemed) error = %v, want %v", err, ErrInvalidMfaToken)
+	}
+}
+
+func TestResolveChallengeRejectsOtherTokens(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	accessToken, err := jwt.NewToken(models.User{Id: 1, Role: models.JobSeeker}, "session", nil, service.keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.ResolveChallenge(accessToken); !errors.Is(err, ErrInvalidMfaToken) {
+		t.Errorf("ResolveChallenge(access token) error = %v, want %v", err, ErrInvalidMfaToken)
+	}
+}
@@ -0,0 +1,120 @@
+package moderation
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"fmt"
+)
+
+const (
+	approvedSubject = "Company approved"
+	rejectedSubject = "Company rejected"
+)
+
+var (
+	ErrCompanyNotFound    = errors.New("company not found")
+	ErrAlreadyReviewed    = errors.New("company is already reviewed")
+	EmptyReasonErr        = errors.New("rejection reason is empty")
+	EmptyUserErr          = errors.New("empty user")
+	ErrNotificationFailed = errors.New("failed to notify employer")
+)
+
+type Repository interface {
+	CompaniesByStatus(status models.CompanyStatus) ([]models.Company, error)
+	CompanyByUserId(userId int64) (*models.Company, error)
+	ReviewCompany(userId int64, status models.CompanyStatus, rejectionReason string) error
+}
+
+type UserProvider interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type EmailSender interface {
+	Send(recipientEmail string, subject string, body string) error
+}
+
+type Service struct {
+	repository   Repository
+	userProvider UserProvider
+	emailSender  EmailSender
+}
+
+func New(repository Repository, userProvider UserProvider, emailSender EmailSender) *Service {
+	return &Service{
+		repository:   repository,
+		userProvider: userProvider,
+		emailSender:  emailSender,
+	}
+}
+
+func (s *Service) Pending() ([]models.Company, error) {
+	return s.repository.CompaniesByStatus(models.CompanyPending)
+}
+
+func (s *Service) Approve(userId int64) error {
+	company, err := s.review(userId, models.CompanyApproved, "")
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Your company %q has been approved. You can now publish vacancies.", company.Name)
+
+	return s.notify(userId, approvedSubject, body)
+}
+
+func (s *Service) Reject(userId int64, reason string) error {
+	if reason == "" {
+		return EmptyReasonErr
+	}
+
+	company, err := s.review(userId, models.CompanyRejected, reason)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Your company %q has been rejected. Reason: %s", company.Name, reason)
+
+	return s.notify(userId, rejectedSubject, body)
+}
+
+func (s *Service) review(userId int64, status models.CompanyStatus, reason string) (*models.Company, error) {
+	if userId == 0 {
+		return nil, EmptyUserErr
+	}
+
+	company, err := s.repository.CompanyByUserId(userId)
+	if errors.Is(err, storage.ErrCompanyNotFound) {
+		return nil, ErrCompanyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if company.Status != models.CompanyPending {
+		return nil, ErrAlreadyReviewed
+	}
+
+	err = s.repository.ReviewCompany(userId, status, reason)
+	if errors.Is(err, storage.ErrCompanyNotFound) {
+		return nil, ErrAlreadyReviewed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return company, nil
+}
+
+func (s *Service) notify(userId int64, subject, body string) error {
+	user, err := s.userProvider.UserByUserId(userId)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotificationFailed, err)
+	}
+
+	if err := s.emailSender.Send(user.Email, subject, body); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotificationFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,415 @@
+package oauth
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/oidc"
+	"auth/internal/lib/opaque"
+	"auth/internal/lib/pkce"
+	"auth/internal/storage"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ResponseTypeCode = "code"
+)
+
+var (
+	ErrInvalidClient           = errors.New("invalid client")
+	ErrInvalidRedirectUri      = errors.New("invalid redirect uri")
+	ErrUnsupportedResponseType = errors.New("unsupported response type")
+	ErrInvalidScope            = errors.New("invalid scope")
+	ErrInvalidChallenge        = errors.New("invalid code challenge")
+	ErrConsentRequired         = errors.New("consent required")
+	ErrAccessDenied            = errors.New("access denied")
+	ErrInvalidGrant            = errors.New("invalid grant")
+	ErrInsufficientScope       = errors.New("insufficient scope")
+	ErrUnauthorizedClient      = errors.New("client is not allowed to use this grant")
+	EmptyClientNameErr         = errors.New("client name is empty")
+	EmptyRedirectUrisErr       = errors.New("redirect uris are empty")
+	EmptyScopesErr             = errors.New("scopes are empty")
+)
+
+type Repository interface {
+	SaveOAuthClient(client models.OAuthClient) error
+	OAuthClient(id string) (*models.OAuthClient, error)
+	OAuthClients() ([]models.OAuthClient, error)
+	OAuthConsent(userId int64, clientId string) (*models.OAuthConsent, error)
+	SaveOAuthConsent(userId int64, clientId string, scopes []string) error
+	SaveAuthorizationCode(code models.AuthorizationCode) error
+	UseAuthorizationCode(codeHash string) (*models.AuthorizationCode, error)
+}
+
+type UserProvider interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type TokenIssuer interface {
+	IssueForClient(user *models.User, clientId, scope, device, ip string) (*models.TokenPair, error)
+	RefreshForClient(refreshToken, clientId string) (*models.TokenPair, error)
+}
+
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientId            string
+	RedirectUri         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+type Service struct {
+	repository   Repository
+	userProvider UserProvider
+	tokenIssuer  TokenIssuer
+	keys         jwt.KeyProvider
+	issuer       string
+	codeTtl      time.Duration
+	tokenTtl     time.Duration
+}
+
+func New(repository Repository, userProvider UserProvider, tokenIssuer TokenIssuer, keys jwt.KeyProvider, issuer string, codeTtl, tokenTtl time.Duration) *Service {
+	return &Service{
+		repository:   repository,
+		userProvider: userProvider,
+		tokenIssuer:  tokenIssuer,
+		keys:         keys,
+		issuer:       issuer,
+		codeTtl:      codeTtl,
+		tokenTtl:     tokenTtl,
+	}
+}
+
+func (s *Service) RegisterClient(name string, redirectUris, scopes []string, public bool) (*models.OAuthClient, string, error) {
+	if name == "" {
+		return nil, "", EmptyClientNameErr
+	}
+
+	if len(redirectUris) == 0 {
+		return nil, "", EmptyRedirectUrisErr
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", EmptyScopesErr
+	}
+
+	for _, redirectUri := range redirectUris {
+		parsed, err := url.Parse(redirectUri)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return nil, "", ErrInvalidRedirectUri
+		}
+	}
+
+	client := models.OAuthClient{
+		Id:           uuid.New().String(),
+		Name:         name,
+		RedirectUris: redirectUris,
+		Scopes:       scopes,
+		Public:       public,
+	}
+
+	var secret string
+	if !public {
+		var err error
+
+		secret, err = opaque.New()
+		if err != nil {
+			return nil, "", err
+		}
+
+		client.SecretHash, err = bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := s.repository.SaveOAuthClient(client); err != nil {
+		return nil, "", err
+	}
+
+	return &client, secret, nil
+}
+
+func (s *Service) RegisterServiceAccount(name string) (*models.OAuthClient, string, error) {
+	if name == "" {
+		return nil, "", EmptyClientNameErr
+	}
+
+	secret, err := opaque.New()
+	if err != nil {
+		return nil, "", err
+	}
+
+	secretHash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := models.OAuthClient{
+		Id:             uuid.New().String(),
+		SecretHash:     secretHash,
+		Name:           name,
+		RedirectUris:   []string{},
+		Scopes:         []string{},
+		ServiceAccount: true,
+	}
+
+	if err := s.repository.SaveOAuthClient(client); err != nil {
+		return nil, "", err
+	}
+
+	return &client, secret, nil
+}
+
+func (s *Service) Clients() ([]models.OAuthClient, error) {
+	return s.repository.OAuthClients()
+}
+
+func (s *Service) Client(clientId, redirectUri string) (*models.OAuthClient, error) {
+	client, err := s.repository.OAuthClient(clientId)
+	if errors.Is(err, storage.ErrOAuthClientNotFound) {
+		return nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(client.RedirectUris, redirectUri) {
+		return nil, ErrInvalidRedirectUri
+	}
+
+	return client, nil
+}
+
+func (s *Service) Authorize(userId int64, req AuthorizeRequest) (string, error) {
+	client, scopes, err := s.validate(req)
+	if err != nil {
+		return "", err
+	}
+
+	consent, err := s.repository.OAuthConsent(userId, client.Id)
+	if errors.Is(err, storage.ErrOAuthConsentNotFound) {
+		return "", ErrConsentRequired
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return "", ErrConsentRequired
+		}
+	}
+
+	return s.issueCode(userId, req, scopes)
+}
+
+func (s *Service) Consent(userId int64, req AuthorizeRequest, approve bool) (string, error) {
+	client, scopes, err := s.validate(req)
+	if err != nil {
+		return "", err
+	}
+
+	if !approve {
+		return "", ErrAccessDenied
+	}
+
+	if err := s.repository.SaveOAuthConsent(userId, client.Id, scopes); err != nil {
+		return "", err
+	}
+
+	return s.issueCode(userId, req, scopes)
+}
+
+func (s *Service) Scopes(req AuthorizeRequest) ([]string, error) {
+	_, scopes, err := s.validate(req)
+
+	return scopes, err
+}
+
+func (s *Service) Exchange(clientId, clientSecret, code, redirectUri, codeVerifier, device, ip string) (*models.TokenPair, error) {
+	if _, err := s.AuthenticateClient(clientId, clientSecret); err != nil {
+		return nil, err
+	}
+
+	authorizationCode, err := s.repository.UseAuthorizationCode(opaque.Hash(code))
+	if errors.Is(err, storage.ErrAuthorizationCodeNotFound) {
+		return nil, ErrInvalidGrant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if authorizationCode.ClientId != clientId || authorizationCode.RedirectUri != redirectUri {
+		return nil, ErrInvalidGrant
+	}
+
+	if time.Now().After(authorizationCode.Expiration) {
+		return nil, ErrInvalidGrant
+	}
+
+	if !pkce.Verify(authorizationCode.CodeChallenge, authorizationCode.CodeChallengeMethod, codeVerifier) {
+		return nil, ErrInvalidGrant
+	}
+
+	user, err := s.userProvider.UserByUserId(authorizationCode.UserId)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, ErrInvalidGrant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := s.tokenIssuer.IssueForClient(user, clientId, authorizationCode.Scope, device, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	if oidc.HasScope(authorizationCode.Scope, oidc.ScopeOpenId) {
+		userInfo := oidc.UserInfo(user, authorizationCode.Scope)
+
+		tokenPair.IdToken, err = jwt.NewIdToken(s.issuer, clientId, authorizationCode.Nonce, userInfo, s.keys, s.tokenTtl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tokenPair, nil
+}
+
+func (s *Service) UserInfo(principal *models.Principal) (map[string]any, error) {
+	if !oidc.HasScope(principal.Scope, oidc.ScopeOpenId) {
+		return nil, ErrInsufficientScope
+	}
+
+	user, err := s.userProvider.UserByUserId(principal.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidc.UserInfo(user, principal.Scope), nil
+}
+
+func (s *Service) Refresh(clientId, clientSecret, refreshToken string) (*models.TokenPair, error) {
+	if _, err := s.AuthenticateClient(clientId, clientSecret); err != nil {
+		return nil, err
+	}
+
+	return s.tokenIssuer.RefreshForClient(refreshToken, clientId)
+}
+
+func (s *Service) ClientCredentials(clientId, clientSecret, scope string) (*models.TokenPair, error) {
+	client, err := s.AuthenticateClient(clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.ServiceAccount {
+		return nil, ErrUnauthorizedClient
+	}
+
+	permissions, err := requestedScopes(client.Permissions, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	grantedScope := strings.Join(permissions, " ")
+
+	accessToken, err := jwt.NewServiceToken(client.Id, grantedScope, permissions, s.keys, s.tokenTtl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{AccessToken: accessToken, ExpiresIn: s.tokenTtl, Scope: grantedScope}, nil
+}
+
+func (s *Service) AuthenticateClient(clientId, clientSecret string) (*models.OAuthClient, error) {
+	client, err := s.repository.OAuthClient(clientId)
+	if errors.Is(err, storage.ErrOAuthClientNotFound) {
+		return nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if client.Public {
+		return client, nil
+	}
+
+	if bcrypt.CompareHashAndPassword(client.SecretHash, []byte(clientSecret)) != nil {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+func (s *Service) validate(req AuthorizeRequest) (*models.OAuthClient, []string, error) {
+	client, err := s.Client(req.ClientId, req.RedirectUri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.ResponseType != ResponseTypeCode {
+		return nil, nil, ErrUnsupportedResponseType
+	}
+
+	if req.CodeChallengeMethod != pkce.MethodS256 || !pkce.ValidChallenge(req.CodeChallenge) {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	scopes, err := requestedScopes(client.Scopes, req.Scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, scopes, nil
+}
+
+func requestedScopes(allowed []string, scope string) ([]string, error) {
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		return allowed, nil
+	}
+
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	return scopes, nil
+}
+
+func (s *Service) issueCode(userId int64, req AuthorizeRequest, scopes []string) (string, error) {
+	code, err := opaque.New()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repository.SaveAuthorizationCode(models.AuthorizationCode{
+		CodeHash:            opaque.Hash(code),
+		ClientId:            req.ClientId,
+		UserId:              userId,
+		RedirectUri:         req.RedirectUri,
+		Scope:               strings.Join(scopes, " "),
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+		Expiration:          time.Now().Add(s.codeTtl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
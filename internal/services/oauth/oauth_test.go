@@ -0,0 +1,266 @@
+package oauth
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/pkce"
+	"auth/internal/storage"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+const (
+	clientId    = "web"
+	redirectUri = "https://example.com/callback"
+	verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type fakeRepository struct {
+	clients  map[string]*models.OAuthClient
+	consents map[string]*models.OAuthConsent
+	codes    map[string]*models.AuthorizationCode
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		clients: map[string]*models.OAuthClient{
+			clientId: {Id: clientId, RedirectUris: []string{redirectUri}, Scopes: []string{"openid", "profile"}, Public: true},
+		},
+		consents: make(map[string]*models.OAuthConsent),
+		codes:    make(map[string]*models.AuthorizationCode),
+	}
+}
+
+func (r *fakeRepository) SaveOAuthClient(client models.OAuthClient) error {
+	r.clients[client.Id] = &client
+
+	return nil
+}
+
+func (r *fakeRepository) OAuthClient(id string) (*models.OAuthClient, error) {
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, storage.ErrOAuthClientNotFound
+	}
+
+	return client, nil
+}
+
+func (r *fakeRepository) OAuthClients() ([]models.OAuthClient, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) OAuthConsent(userId int64, clientId string) (*models.OAuthConsent, error) {
+	consent, ok := r.consents[clientId]
+	if !ok || consent.UserId != userId {
+		return nil, storage.ErrOAuthConsentNotFound
+	}
+
+	return consent, nil
+}
+
+func (r *fakeRepository) SaveOAuthConsent(userId int64, clientId string, scopes []string) error {
+	r.consents[clientId] = &models.OAuthConsent{UserId: userId, ClientId: clientId, Scopes: scopes}
+
+	return nil
+}
+
+func (r *fakeRepository) SaveAuthorizationCode(code models.AuthorizationCode) error {
+	r.codes[code.CodeHash] = &code
+
+	return nil
+}
+
+func (r *fakeRepository) UseAuthorizationCode(codeHash string) (*models.AuthorizationCode, error) {
+	code, ok := r.codes[codeHash]
+	if !ok || code.Used {
+		return nil, storage.ErrAuthorizationCodeNotFound
+	}
+
+	code.Used = true
+
+	return code, nil
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) UserByUserId(userId int64) (*models.User, error) {
+	return &models.User{Id: userId, FullName: "User", Role: models.JobSeeker}, nil
+}
+
+type fakeIssuer struct{}
+
+func (fakeIssuer) IssueForClient(user *models.User, clientId, scope, device, ip string) (*models.TokenPair, error) {
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh", Scope: scope}, nil
+}
+
+func (fakeIssuer) RefreshForClient(refreshToken, clientId string) (*models.TokenPair, error) {
+	return nil, nil
+}
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func newService(t *testing.T, repository *fakeRepository) *Service {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := &staticKeys{key: &jwt.Key{Kid: "test", Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+
+	return New(repository, fakeUsers{}, fakeIssuer{}, keys, "https://auth.example.com", time.Minute, time.Minute)
+}
+
+func authorizeRequest() AuthorizeRequest {
+	return AuthorizeRequest{
+		ResponseType:        ResponseTypeCode,
+		ClientId:            clientId,
+		RedirectUri:         redirectUri,
+		Scope:               "openid",
+		CodeChallenge:       pkce.Challenge(verifier),
+		CodeChallengeMethod: pkce.MethodS256,
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	if _, err := service.Authorize(1, authorizeRequest()); !errors.Is(err, ErrConsentRequired) {
+		t.Fatalf("Authorize(without consent) error = %v, want %v", err, ErrConsentRequired)
+	}
+
+	code, err := service.Consent(1, authorizeRequest(), true)
+	if err != nil {
+		t.Fatalf("Consent() error = %v", err)
+	}
+
+	tokenPair, err := service.Exchange(clientId, "", code, redirectUri, verifier, "device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+
+	if tokenPair.IdToken == "" {
+		t.Error("Exchange() did not return an id token for the openid scope")
+	}
+
+	if _, err := service.Exchange(clientId, "", code, redirectUri, verifier, "device", "127.0.0.1"); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Exchange(reused code) error = %v, want %v", err, ErrInvalidGrant)
+	}
+
+	if _, err := service.Authorize(1, authorizeRequest()); err != nil {
+		t.Errorf("Authorize(with consent) error = %v", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifierAndRedirect(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	code, err := service.Consent(1, authorizeRequest(), true)
+	if err != nil {
+		t.Fatalf("Consent() error = %v", err)
+	}
+
+	if _, err := service.Exchange(clientId, "", code, redirectUri, "a-different-verifier-of-sufficient-length-123", "", ""); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Exchange(wrong verifier) error = %v, want %v", err, ErrInvalidGrant)
+	}
+
+	code, err = service.Consent(1, authorizeRequest(), true)
+	if err != nil {
+		t.Fatalf("Consent() error = %v", err)
+	}
+
+	if _, err := service.Exchange(clientId, "", code, "https://evil.example.com/callback", verifier, "", ""); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Exchange(wrong redirect uri) error = %v, want %v", err, ErrInvalidGrant)
+	}
+}
+
+func TestAuthorizeValidatesRequest(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	tests := []struct {
+		name   string
+		modify func(req *AuthorizeRequest)
+		err    error
+	}{
+		{name: "unknown client", modify: func(req *AuthorizeRequest) { req.ClientId = "unknown" }, err: ErrInvalidClient},
+		{name: "unregistered redirect", modify: func(req *AuthorizeRequest) { req.RedirectUri = "https://evil.example.com" }, err: ErrInvalidRedirectUri},
+		{name: "token response type", modify: func(req *AuthorizeRequest) { req.ResponseType = "token" }, err: ErrUnsupportedResponseType},
+		{name: "plain challenge", modify: func(req *AuthorizeRequest) { req.CodeChallengeMethod = "plain" }, err: ErrInvalidChallenge},
+		{name: "missing challenge", modify: func(req *AuthorizeRequest) { req.CodeChallenge = "" }, err: ErrInvalidChallenge},
+		{name: "unknown scope", modify: func(req *AuthorizeRequest) { req.Scope = "openid admin" }, err: ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		req := authorizeRequest()
+		tt.modify(&req)
+
+		if _, err := service.Consent(1, req, true); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Consent() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	if _, err := service.Consent(1, authorizeRequest(), false); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Consent(denied) error = %v, want %v", err, ErrAccessDenied)
+	}
+}
+
+func TestClientCredentialsLimitsScopeToPermissions(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	client, secret, err := service.RegisterServiceAccount("worker")
+	if err != nil {
+		t.Fatalf("RegisterServiceAccount() error = %v", err)
+	}
+
+	repository.clients[client.Id].Permissions = []string{models.PermissionUsersReadAny, models.PermissionUsersUnlockAny}
+
+	tokenPair, err := service.ClientCredentials(client.Id, secret, models.PermissionUsersReadAny)
+	if err != nil {
+		t.Fatalf("ClientCredentials() error = %v", err)
+	}
+
+	if tokenPair.Scope != models.PermissionUsersReadAny {
+		t.Errorf("scope = %q, want %q", tokenPair.Scope, models.PermissionUsersReadAny)
+	}
+
+	tokenPair, err = service.ClientCredentials(client.Id, secret, "")
+	if err != nil {
+		t.Fatalf("ClientCredentials(default scope) error = %v", err)
+	}
+
+	if tokenPair.Scope != models.PermissionUsersReadAny+" "+models.PermissionUsersUnlockAny {
+		t.Errorf("default scope = %q, want all granted permissions", tokenPair.Scope)
+	}
+
+	if _, err := service.ClientCredentials(client.Id, secret, models.PermissionUsersDeleteAny); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("ClientCredentials(ungranted scope) error = %v, want %v", err, ErrInvalidScope)
+	}
+
+	if _, err := service.ClientCredentials(client.Id, "wrong", ""); !errors.Is(err, ErrInvalidClient) {
+		t.Errorf("ClientCredentials(wrong secret) error = %v, want %v", err, ErrInvalidClient)
+	}
+
+	if _, err := service.ClientCredentials(clientId, "", ""); !errors.Is(err, ErrUnauthorizedClient) {
+		t.Errorf("ClientCredentials(user client) error = %v, want %v", err, ErrUnauthorizedClient)
+	}
+}
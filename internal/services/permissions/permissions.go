@@ -0,0 +1,195 @@
+package permissions
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/enums"
+	"auth/internal/storage"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownRole         = errors.New("unknown role")
+	ErrPermissionNotFound  = errors.New("permission not found")
+	ErrProtectedPermission = errors.New("permission can't be revoked from this role")
+	EmptyPermissionErr     = errors.New("permission is empty")
+	ErrClientNotFound      = errors.New("service account not found")
+	ErrScopedPermission    = errors.New("self scoped permission can't be granted to service account")
+)
+
+type Repository interface {
+	Permissions() ([]models.Permission, error)
+	RolePermissions(role string) ([]string, error)
+	GrantPermission(role, permission string) error
+	RevokePermission(role, permission string) error
+	ClientPermissions(clientId string) ([]string, error)
+	SetClientPermissions(clientId string, permissions []string) error
+}
+
+type cacheEntry struct {
+	permissions []string
+	loadedAt    time.Time
+}
+
+type Service struct {
+	repository Repository
+	cacheTtl   time.Duration
+
+	mu          sync.RWMutex
+	cache       map[models.UserRole]cacheEntry
+	clientCache map[string]cacheEntry
+}
+
+func New(repository Repository, cacheTtl time.Duration) *Service {
+	return &Service{
+		repository:  repository,
+		cacheTtl:    cacheTtl,
+		cache:       make(map[models.UserRole]cacheEntry),
+		clientCache: make(map[string]cacheEntry),
+	}
+}
+
+func (s *Service) Permissions() ([]models.Permission, error) {
+	return s.repository.Permissions()
+}
+
+func (s *Service) RolePermissions(role models.UserRole) ([]string, error) {
+	roleName := enums.RoleConvertToString(role)
+	if roleName == "" {
+		return nil, ErrUnknownRole
+	}
+
+	s.mu.RLock()
+	entry, ok := s.cache[role]
+	s.mu.RUnlock()
+
+	if ok && time.Since(entry.loadedAt) < s.cacheTtl {
+		return entry.permissions, nil
+	}
+
+	permissions, err := s.repository.RolePermissions(roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[role] = cacheEntry{permissions: permissions, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (s *Service) Grant(role models.UserRole, permission string) error {
+	roleName, err := s.validate(role, permission)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.GrantPermission(roleName, permission)
+	if errors.Is(err, storage.ErrPermissionNotFound) {
+		return ErrPermissionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.invalidate(role)
+
+	return nil
+}
+
+func (s *Service) Revoke(role models.UserRole, permission string) error {
+	roleName, err := s.validate(role, permission)
+	if err != nil {
+		return err
+	}
+
+	if role == models.Admin && permission == models.PermissionPermissionsManageAny {
+		return ErrProtectedPermission
+	}
+
+	if err := s.repository.RevokePermission(roleName, permission); err != nil {
+		return err
+	}
+
+	s.invalidate(role)
+
+	return nil
+}
+
+func (s *Service) ClientPermissions(clientId string) ([]string, error) {
+	s.mu.RLock()
+	entry, ok := s.clientCache[clientId]
+	s.mu.RUnlock()
+
+	if ok && time.Since(entry.loadedAt) < s.cacheTtl {
+		return entry.permissions, nil
+	}
+
+	permissions, err := s.repository.ClientPermissions(clientId)
+	if errors.Is(err, storage.ErrOAuthClientNotFound) {
+		return nil, ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.clientCache[clientId] = cacheEntry{permissions: permissions, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (s *Service) SetClientPermissions(clientId string, permissions []string) error {
+	known, err := s.repository.Permissions()
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if strings.HasSuffix(permission, ":self") {
+			return ErrScopedPermission
+		}
+
+		if !slices.ContainsFunc(known, func(p models.Permission) bool { return p.Name == permission }) {
+			return ErrPermissionNotFound
+		}
+	}
+
+	err = s.repository.SetClientPermissions(clientId, permissions)
+	if errors.Is(err, storage.ErrOAuthClientNotFound) {
+		return ErrClientNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.clientCache, clientId)
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Service) validate(role models.UserRole, permission string) (string, error) {
+	roleName := enums.RoleConvertToString(role)
+	if roleName == "" {
+		return "", ErrUnknownRole
+	}
+
+	if permission == "" {
+		return "", EmptyPermissionErr
+	}
+
+	return roleName, nil
+}
+
+func (s *Service) invalidate(role models.UserRole) {
+	s.mu.Lock()
+	delete(s.cache, role)
+	s.mu.Unlock()
+}
@@ -0,0 +1,155 @@
+package phoneverification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/opaque"
+	"auth/internal/storage"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	codeDigits  = 6
+	messageText = "Vacancy Tomsk verification code: %s"
+)
+
+var (
+	ErrAlreadyVerified = errors.New("phone is already verified")
+	ErrCodeNotFound    = errors.New("verification code not found")
+	ErrCodeExpired     = errors.New("verification code expired")
+	ErrInvalidCode     = errors.New("invalid verification code")
+	ErrTooManyAttempts = errors.New("too many verification attempts")
+	ErrTooManyRequests = errors.New("verification requested too often")
+	EmptyCodeErr       = errors.New("verification code is empty")
+	EmptyPhoneErr      = errors.New("phone is empty")
+	EmptyUserErr       = errors.New("empty user")
+)
+
+var codeUpperBound = big.NewInt(1_000_000)
+
+type Repository interface {
+	SavePhoneVerification(userId int64, codeHash string, expiration, windowStart time.Time) error
+	PhoneVerification(userId int64) (*models.PhoneVerification, error)
+	IncrementPhoneVerificationAttempts(userId int64) error
+	ConfirmPhone(userId int64) error
+}
+
+type SmsSender interface {
+	Send(phone string, text string) error
+}
+
+type Service struct {
+	repository     Repository
+	smsSender      SmsSender
+	codeTtl        time.Duration
+	resendInterval time.Duration
+	maxAttempts    int
+	attemptsWindow time.Duration
+}
+
+func New(repository Repository, smsSender SmsSender, codeTtl, resendInterval time.Duration, maxAttempts int, attemptsWindow time.Duration) *Service {
+	return &Service{
+		repository:     repository,
+		smsSender:      smsSender,
+		codeTtl:        codeTtl,
+		resendInterval: resendInterval,
+		maxAttempts:    maxAttempts,
+		attemptsWindow: attemptsWindow,
+	}
+}
+
+func (s *Service) Send(user *models.User) error {
+	if user.Id == 0 {
+		return EmptyUserErr
+	}
+
+	phone := user.Phone
+	if user.PendingPhone != "" {
+		phone = user.PendingPhone
+	}
+
+	if phone == "" {
+		return EmptyPhoneErr
+	}
+
+	if user.PhoneVerifiedAt != nil && user.PendingPhone == "" {
+		return ErrAlreadyVerified
+	}
+
+	verification, err := s.repository.PhoneVerification(user.Id)
+	if err != nil && !errors.Is(err, storage.ErrVerificationNotFound) {
+		return err
+	}
+
+	windowStart := time.Now().Add(-s.attemptsWindow)
+
+	if verification != nil {
+		if time.Now().Before(verification.Expiration) && time.Since(verification.CreatedAt) < s.resendInterval {
+			return ErrTooManyRequests
+		}
+
+		if verification.Attempts >= s.maxAttempts && verification.WindowStartedAt.After(windowStart) {
+			return ErrTooManyRequests
+		}
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.SavePhoneVerification(user.Id, opaque.Hash(code), time.Now().Add(s.codeTtl), windowStart); err != nil {
+		return err
+	}
+
+	return s.smsSender.Send(phone, fmt.Sprintf(messageText, code))
+}
+
+func (s *Service) Confirm(userId int64, code string) error {
+	if userId == 0 {
+		return EmptyUserErr
+	}
+
+	if code == "" {
+		return EmptyCodeErr
+	}
+
+	verification, err := s.repository.PhoneVerification(userId)
+	if errors.Is(err, storage.ErrVerificationNotFound) {
+		return ErrCodeNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if verification.Attempts >= s.maxAttempts {
+		return ErrTooManyAttempts
+	}
+
+	if time.Now().After(verification.Expiration) {
+		return ErrCodeExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(opaque.Hash(code))) != 1 {
+		if err := s.repository.IncrementPhoneVerificationAttempts(userId); err != nil {
+			return err
+		}
+
+		return ErrInvalidCode
+	}
+
+	return s.repository.ConfirmPhone(userId)
+}
+
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, codeUpperBound)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", codeDigits, n.Int64()), nil
+}
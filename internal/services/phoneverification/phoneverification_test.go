@@ -0,0 +1,167 @@
+package phoneverification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const (
+	codeTtl        = 5 * time.Minute
+	resendInterval = time.Minute
+	maxAttempts    = 3
+	attemptsWindow = time.Hour
+)
+
+type fakeRepository struct {
+	verification *models.PhoneVerification
+	confirmed    bool
+}
+
+func (r *fakeRepository) SavePhoneVerification(userId int64, codeHash string, expiration, windowStart time.Time) error {
+	if r.verification == nil || !r.verification.WindowStartedAt.After(windowStart) {
+		r.verification = &models.PhoneVerification{UserId: userId, WindowStartedAt: time.Now()}
+	}
+
+	r.verification.CodeHash = codeHash
+	r.verification.Expiration = expiration
+	r.verification.CreatedAt = time.Now()
+
+	return nil
+}
+
+func (r *fakeRepository) PhoneVerification(userId int64) (*models.PhoneVerification, error) {
+	if r.verification == nil {
+		return nil, storage.ErrVerificationNotFound
+	}
+
+	copied := *r.verification
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) IncrementPhoneVerificationAttempts(userId int64) error {
+	r.verification.Attempts++
+
+	return nil
+}
+
+func (r *fakeRepository) ConfirmPhone(userId int64) error {
+	r.confirmed = true
+	r.verification = nil
+
+	return nil
+}
+
+type fakeSender struct {
+	codes []string
+}
+
+func (s *fakeSender) Send(phone string, text string) error {
+	s.codes = append(s.codes, regexp.MustCompile(`\d{6}`).FindString(text))
+
+	return nil
+}
+
+func (s *fakeSender) last() string {
+	return s.codes[len(s.codes)-1]
+}
+
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+
+	return "000000"
+}
+
+func TestConfirm(t *testing.T) {
+	repository := &fakeRepository{}
+	sender := &fakeSender{}
+	service := New(repository, sender, codeTtl, resendInterval, maxAttempts, attemptsWindow)
+	user := &models.User{Id: 1, Phone: "+79990000000"}
+
+	if err := service.Send(user); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if err := service.Send(user); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Send(again) error = %v, want %v", err, ErrTooManyRequests)
+	}
+
+	if err := service.Confirm(1, wrongCode(sender.last())); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("Confirm(wrong) error = %v, want %v", err, ErrInvalidCode)
+	}
+
+	if err := service.Confirm(1, sender.last()); err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+
+	if !repository.confirmed {
+		t.Error("phone is not confirmed")
+	}
+}
+
+func TestResendKeepsAttempts(t *testing.T) {
+	repository := &fakeRepository{}
+	sender := &fakeSender{}
+	service := New(repository, sender, codeTtl, resendInterval, maxAttempts, attemptsWindow)
+	user := &models.User{Id: 1, Phone: "+79990000000"}
+
+	for i := 0; i < maxAttempts; i++ {
+		if err := service.Send(user); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+
+		if err := service.Confirm(1, wrongCode(sender.last())); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("Confirm(wrong) error = %v, want %v", err, ErrInvalidCode)
+		}
+
+		repository.verification.CreatedAt = repository.verification.CreatedAt.Add(-resendInterval)
+	}
+
+	if repository.verification.Attempts != maxAttempts {
+		t.Fatalf("attempts = %d, want %d", repository.verification.Attempts, maxAttempts)
+	}
+
+	if err := service.Send(user); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Send(attempts exhausted) error = %v, want %v", err, ErrTooManyRequests)
+	}
+
+	if err := service.Confirm(1, sender.last()); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("Confirm(attempts exhausted) error = %v, want %v", err, ErrTooManyAttempts)
+	}
+
+	repository.verification.WindowStartedAt = repository.verification.WindowStartedAt.Add(-attemptsWindow)
+
+	if err := service.Send(user); err != nil {
+		t.Fatalf("Send(next window) error = %v", err)
+	}
+
+	if err := service.Confirm(1, sender.last()); err != nil {
+		t.Errorf("Confirm(next window) error = %v", err)
+	}
+}
+
+func TestConfirmRejectsExpiredCode(t *testing.T) {
+	repository := &fakeRepository{}
+	sender := &fakeSender{}
+	service := New(repository, sender, codeTtl, resendInterval, maxAttempts, attemptsWindow)
+
+	if err := service.Send(&models.User{Id: 1, Phone: "+79990000000"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	repository.verification.Expiration = time.Now().Add(-time.Second)
+
+	if err := service.Confirm(1, sender.last()); !errors.Is(err, ErrCodeExpired) {
+		t.Errorf("Confirm(expired) error = %v, want %v", err, ErrCodeExpired)
+	}
+
+	if err := service.Send(&models.User{Id: 1, Phone: "+79990000000"}); err != nil {
+		t.Errorf("Send(after expiry) error = %v", err)
+	}
+}
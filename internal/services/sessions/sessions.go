@@ -0,0 +1,92 @@
+package sessions
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	EmptySessionErr    = errors.New("session is empty")
+	EmptyUserErr       = errors.New("empty user")
+)
+
+type Repository interface {
+	ActiveSession(id string) (*models.Session, error)
+	TouchSession(id string) error
+	SessionsByUserId(userId int64) ([]models.Session, error)
+	RevokeSession(id string, userId int64) error
+	RevokeUserSessions(userId int64) error
+}
+
+type Service struct {
+	repository    Repository
+	touchInterval time.Duration
+}
+
+func New(repository Repository, touchInterval time.Duration) *Service {
+	return &Service{
+		repository:    repository,
+		touchInterval: touchInterval,
+	}
+}
+
+func (s *Service) Validate(sessionId string) error {
+	if sessionId == "" {
+		return EmptySessionErr
+	}
+
+	session, err := s.repository.ActiveSession(sessionId)
+	if errors.Is(err, storage.ErrSessionNotFound) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Since(session.LastSeenAt) < s.touchInterval {
+		return nil
+	}
+
+	err = s.repository.TouchSession(sessionId)
+	if errors.Is(err, storage.ErrSessionNotFound) {
+		return ErrSessionNotFound
+	}
+
+	return err
+}
+
+func (s *Service) Sessions(userId int64) ([]models.Session, error) {
+	if userId == 0 {
+		return nil, EmptyUserErr
+	}
+
+	return s.repository.SessionsByUserId(userId)
+}
+
+func (s *Service) Revoke(userId int64, sessionId string) error {
+	if userId == 0 {
+		return EmptyUserErr
+	}
+
+	if sessionId == "" {
+		return EmptySessionErr
+	}
+
+	err := s.repository.RevokeSession(sessionId, userId)
+	if errors.Is(err, storage.ErrSessionNotFound) {
+		return ErrSessionNotFound
+	}
+
+	return err
+}
+
+func (s *Service) RevokeAll(userId int64) error {
+	if userId == 0 {
+		return EmptyUserErr
+	}
+
+	return s.repository.RevokeUserSessions(userId)
+}
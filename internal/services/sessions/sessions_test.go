@@ -0,0 +1,91 @@
+package sessions
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRepository struct {
+	sessions map[string]*models.Session
+	touched  int
+}
+
+func (r *fakeRepository) ActiveSession(id string) (*models.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok || session.Revoked {
+		return nil, storage.ErrSessionNotFound
+	}
+
+	copied := *session
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) TouchSession(id string) error {
+	r.touched++
+	r.sessions[id].LastSeenAt = time.Now()
+
+	return nil
+}
+
+func (r *fakeRepository) SessionsByUserId(userId int64) ([]models.Session, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) RevokeSession(id string, userId int64) error {
+	return nil
+}
+
+func (r *fakeRepository) RevokeUserSessions(userId int64) error {
+	return nil
+}
+
+func TestValidateThrottlesTouch(t *testing.T) {
+	repository := &fakeRepository{sessions: map[string]*models.Session{
+		"fresh": {Id: "fresh", LastSeenAt: time.Now()},
+		"stale": {Id: "stale", LastSeenAt: time.Now().Add(-2 * time.Minute)},
+	}}
+	service := New(repository, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if err := service.Validate("fresh"); err != nil {
+			t.Fatalf("Validate(fresh) error = %v", err)
+		}
+	}
+
+	if repository.touched != 0 {
+		t.Errorf("fresh session touched %d times, want 0", repository.touched)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := service.Validate("stale"); err != nil {
+			t.Fatalf("Validate(stale) error = %v", err)
+		}
+	}
+
+	if repository.touched != 1 {
+		t.Errorf("stale session touched %d times, want 1", repository.touched)
+	}
+}
+
+func TestValidateRejectsUnknownAndRevokedSessions(t *testing.T) {
+	repository := &fakeRepository{sessions: map[string]*models.Session{
+		"revoked": {Id: "revoked", Revoked: true, LastSeenAt: time.Now()},
+	}}
+	service := New(repository, time.Minute)
+
+	if err := service.Validate(""); !errors.Is(err, EmptySessionErr) {
+		t.Errorf("Validate(empty) error = %v, want %v", err, EmptySessionErr)
+	}
+
+	if err := service.Validate("unknown"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Validate(unknown) error = %v, want %v", err, ErrSessionNotFound)
+	}
+
+	if err := service.Validate("revoked"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Validate(revoked) error = %v, want %v", err, ErrSessionNotFound)
+	}
+}
@@ -0,0 +1,178 @@
+package tokens
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/opaque"
+	"auth/internal/storage"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	EmptyRefreshTokenErr   = errors.New("refresh token is empty")
+)
+
+type Repository interface {
+	SaveRefreshToken(tokenHash string, userId int64, familyId, clientId, scope string, expiration time.Time) error
+	RefreshToken(tokenHash string) (*models.RefreshToken, error)
+	UseRefreshToken(id int64) error
+	RevokeRefreshTokenFamily(familyId string) error
+	SaveSession(id string, userId int64, device, ip string) error
+	CompanyByUserId(userId int64) (*models.Company, error)
+}
+
+type UserProvider interface {
+	UserByUserId(userId int64) (*models.User, error)
+}
+
+type PermissionProvider interface {
+	RolePermissions(role models.UserRole) ([]string, error)
+}
+
+type Service struct {
+	repository         Repository
+	userProvider       UserProvider
+	permissionProvider PermissionProvider
+	keys               jwt.KeyProvider
+	tokenTtl           time.Duration
+	refreshTokenTtl    time.Duration
+}
+
+func New(repository Repository, userProvider UserProvider, permissionProvider PermissionProvider, keys jwt.KeyProvider, tokenTtl, refreshTokenTtl time.Duration) *Service {
+	return &Service{
+		repository:         repository,
+		userProvider:       userProvider,
+		permissionProvider: permissionProvider,
+		keys:               keys,
+		tokenTtl:           tokenTtl,
+		refreshTokenTtl:    refreshTokenTtl,
+	}
+}
+
+func (s *Service) Issue(user *models.User, device, ip string) (*models.TokenPair, error) {
+	sessionId := uuid.New().String()
+
+	if err := s.repository.SaveSession(sessionId, user.Id, device, ip); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, sessionId, "", "")
+}
+
+func (s *Service) IssueForClient(user *models.User, clientId, scope, device, ip string) (*models.TokenPair, error) {
+	sessionId := uuid.New().String()
+
+	if err := s.repository.SaveSession(sessionId, user.Id, device, ip); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, sessionId, clientId, scope)
+}
+
+func (s *Service) Refresh(refreshToken string) (*models.TokenPair, error) {
+	return s.refresh(refreshToken, "")
+}
+
+func (s *Service) RefreshForClient(refreshToken, clientId string) (*models.TokenPair, error) {
+	return s.refresh(refreshToken, clientId)
+}
+
+func (s *Service) refresh(refreshToken, clientId string) (*models.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, EmptyRefreshTokenErr
+	}
+
+	stored, err := s.repository.RefreshToken(opaque.Hash(refreshToken))
+	if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Revoked || stored.ClientId != clientId {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.Used {
+		return nil, s.revokeFamily(stored.FamilyId)
+	}
+
+	if time.Now().After(stored.Expiration) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	err = s.repository.UseRefreshToken(stored.Id)
+	if errors.Is(err, storage.ErrRefreshTokenUsed) {
+		return nil, s.revokeFamily(stored.FamilyId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userProvider.UserByUserId(stored.UserId)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, stored.FamilyId, stored.ClientId, stored.Scope)
+}
+
+func (s *Service) issue(user *models.User, sessionId, clientId, scope string) (*models.TokenPair, error) {
+	if user.Role == models.Employer {
+		company, err := s.repository.CompanyByUserId(user.Id)
+		if err != nil && !errors.Is(err, storage.ErrCompanyNotFound) {
+			return nil, err
+		}
+
+		if company != nil {
+			user.EmployerStatus = company.Status
+		}
+	}
+
+	accessToken, err := s.accessToken(user, sessionId, clientId, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := opaque.New()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.SaveRefreshToken(opaque.Hash(refreshToken), user.Id, sessionId, clientId, scope, time.Now().Add(s.refreshTokenTtl))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresIn: s.tokenTtl, Scope: scope}, nil
+}
+
+func (s *Service) accessToken(user *models.User, sessionId, clientId, scope string) (string, error) {
+	if clientId != "" {
+		return jwt.NewClientToken(*user, sessionId, clientId, scope, s.keys, s.tokenTtl)
+	}
+
+	permissions, err := s.permissionProvider.RolePermissions(user.Role)
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.NewToken(*user, sessionId, permissions, s.keys, s.tokenTtl)
+}
+
+func (s *Service) revokeFamily(familyId string) error {
+	if err := s.repository.RevokeRefreshTokenFamily(familyId); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
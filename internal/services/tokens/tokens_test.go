@@ -0,0 +1,235 @@
+package tokens
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwt"
+	"auth/internal/lib/opaque"
+	"auth/internal/storage"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRepository struct {
+	tokens        map[string]*models.RefreshToken
+	nextId        int64
+	revoked       []string
+	sessions      []string
+	useRefreshErr error
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{tokens: make(map[string]*models.RefreshToken)}
+}
+
+func (r *fakeRepository) SaveRefreshToken(tokenHash string, userId int64, familyId, clientId, scope string, expiration time.Time) error {
+	r.nextId++
+	r.tokens[tokenHash] = &models.RefreshToken{
+		Id:         r.nextId,
+		TokenHash:  tokenHash,
+		UserId:     userId,
+		FamilyId:   familyId,
+		ClientId:   clientId,
+		Scope:      scope,
+		Expiration: expiration,
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) RefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, storage.ErrRefreshTokenNotFound
+	}
+
+	copied := *token
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) UseRefreshToken(id int64) error {
+	if r.useRefreshErr != nil {
+		return r.useRefreshErr
+	}
+
+	for _, token := range r.tokens {
+		if token.Id == id {
+			token.Used = true
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) RevokeRefreshTokenFamily(familyId string) error {
+	r.revoked = append(r.revoked, familyId)
+
+	for _, token := range r.tokens {
+		if token.FamilyId == familyId {
+			token.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) SaveSession(id string, userId int64, device, ip string) error {
+	r.sessions = append(r.sessions, id)
+
+	return nil
+}
+
+func (r *fakeRepository) CompanyByUserId(userId int64) (*models.Company, error) {
+	return nil, storage.ErrCompanyNotFound
+}
+
+type fakeUsers struct{}
+
+func (fakeUsers) UserByUserId(userId int64) (*models.User, error) {
+	return &models.User{Id: userId, Role: models.JobSeeker}, nil
+}
+
+type fakePermissions struct{}
+
+func (fakePermissions) RolePermissions(role models.UserRole) ([]string, error) {
+	return []string{models.PermissionUsersReadSelf}, nil
+}
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func newStaticKeys(t *testing.T) *staticKeys {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &staticKeys{key: &jwt.Key{Kid: "test", Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	if kid != k.key.Kid {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return k.key, nil
+}
+
+func newService(t *testing.T, repository *fakeRepository) *Service {
+	t.Helper()
+
+	return New(repository, fakeUsers{}, fakePermissions{}, newStaticKeys(t), time.Minute, time.Hour)
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	pair, err := service.Issue(&models.User{Id: 1, Role: models.JobSeeker}, "device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	rotated, err := service.Refresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if rotated.RefreshToken == pair.RefreshToken {
+		t.Fatal("Refresh() returned the same refresh token")
+	}
+
+	if !repository.tokens[opaque.Hash(pair.RefreshToken)].Used {
+		t.Error("old refresh token is not marked as used")
+	}
+
+	if repository.tokens[opaque.Hash(rotated.RefreshToken)].FamilyId != repository.tokens[opaque.Hash(pair.RefreshToken)].FamilyId {
+		t.Error("rotated refresh token left the family")
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	pair, err := service.Issue(&models.User{Id: 1, Role: models.JobSeeker}, "device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	rotated, err := service.Refresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if _, err := service.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh(reused) error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	if len(repository.revoked) != 1 {
+		t.Fatalf("revoked families = %v, want one", repository.revoked)
+	}
+
+	if _, err := service.Refresh(rotated.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh(after revocation) error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshConcurrentUseRevokesFamily(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	pair, err := service.Issue(&models.User{Id: 1, Role: models.JobSeeker}, "device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	repository.useRefreshErr = storage.ErrRefreshTokenUsed
+
+	if _, err := service.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh() error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	if len(repository.revoked) != 1 {
+		t.Errorf("revoked families = %v, want one", repository.revoked)
+	}
+}
+
+func TestRefreshRejectsInvalidTokens(t *testing.T) {
+	repository := newFakeRepository()
+	service := newService(t, repository)
+
+	pair, err := service.Issue(&models.User{Id: 1, Role: models.JobSeeker}, "device", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	if _, err := service.Refresh(""); !errors.Is(err, EmptyRefreshTokenErr) {
+		t.Errorf("Refresh(empty) error = %v, want %v", err, EmptyRefreshTokenErr)
+	}
+
+	if _, err := service.Refresh("unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh(unknown) error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	if _, err := service.RefreshForClient(pair.RefreshToken, "partner"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("RefreshForClient(first party token) error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	repository.tokens[opaque.Hash(pair.RefreshToken)].Expiration = time.Now().Add(-time.Second)
+
+	if _, err := service.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Errorf("Refresh(expired) error = %v, want %v", err, ErrRefreshTokenExpired)
+	}
+}
@@ -0,0 +1,39 @@
+package verification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/sl"
+	"log/slog"
+)
+
+type Verifier interface {
+	Send(user *models.User) error
+}
+
+type Service struct {
+	log           *slog.Logger
+	emailVerifier Verifier
+	phoneVerifier Verifier
+}
+
+func New(log *slog.Logger, emailVerifier Verifier, phoneVerifier Verifier) *Service {
+	return &Service{
+		log:           log,
+		emailVerifier: emailVerifier,
+		phoneVerifier: phoneVerifier,
+	}
+}
+
+func (s *Service) SendChanged(user *models.User, changes models.ProfileChanges) {
+	if changes.Email && user.PendingEmail != "" {
+		if err := s.emailVerifier.Send(user); err != nil {
+			s.log.Error("failed to send verification email", slog.Int64("user_id", user.Id), sl.Err(err))
+		}
+	}
+
+	if changes.Phone && user.PendingPhone != "" {
+		if err := s.phoneVerifier.Send(user); err != nil {
+			s.log.Error("failed to send verification code", slog.Int64("user_id", user.Id), sl.Err(err))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package verification
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/logger/handlers/slogdiscard"
+	"errors"
+	"testing"
+)
+
+type fakeVerifier struct {
+	sent int
+	err  error
+}
+
+func (v *fakeVerifier) Send(user *models.User) error {
+	v.sent++
+
+	return v.err
+}
+
+func TestSendChanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    models.User
+		changes models.ProfileChanges
+		email   int
+		phone   int
+	}{
+		{name: "nothing changed", user: models.User{PendingEmail: "new@example.com", PendingPhone: "+79990000001"}},
+		{name: "full name only", user: models.User{}, changes: models.ProfileChanges{FullName: true}},
+		{name: "email without pending", changes: models.ProfileChanges{Email: true}},
+		{name: "email", user: models.User{PendingEmail: "new@example.com"}, changes: models.ProfileChanges{Email: true}, email: 1},
+		{name: "phone", user: models.User{PendingPhone: "+79990000001"}, changes: models.ProfileChanges{Phone: true}, phone: 1},
+		{name: "email and phone", user: models.User{PendingEmail: "new@example.com", PendingPhone: "+79990000001"}, changes: models.ProfileChanges{Email: true, Phone: true}, email: 1, phone: 1},
+	}
+
+	for _, tt := range tests {
+		emailVerifier := &fakeVerifier{}
+		phoneVerifier := &fakeVerifier{}
+		service := New(slogdiscard.NewDiscardLogger(), emailVerifier, phoneVerifier)
+
+		service.SendChanged(&tt.user, tt.changes)
+
+		if emailVerifier.sent != tt.email || phoneVerifier.sent != tt.phone {
+			t.Errorf("%s: sent email = %d, phone = %d, want %d, %d", tt.name, emailVerifier.sent, phoneVerifier.sent, tt.email, tt.phone)
+		}
+	}
+}
+
+func TestSendChangedContinuesAfterFailure(t *testing.T) {
+	emailVerifier := &fakeVerifier{err: errors.New("smtp is down")}
+	phoneVerifier := &fakeVerifier{}
+	service := New(slogdiscard.NewDiscardLogger(), emailVerifier, phoneVerifier)
+
+	service.SendChanged(&models.User{PendingEmail: "new@example.com", PendingPhone: "+79990000001"}, models.ProfileChanges{Email: true, Phone: true})
+
+	if phoneVerifier.sent != 1 {
+		t.Errorf("phone sent = %d, want 1", phoneVerifier.sent)
+	}
+}
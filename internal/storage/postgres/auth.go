@@ -9,36 +9,40 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 	"log"
+	"strings"
 	"time"
 )
 
 const (
-	UniqueViolationCode = "23505"
+	UniqueViolationCode     = "23505"
+	ForeignKeyViolationCode = "23503"
 )
 
-func (s *Storage) SaveUser(fullName, password, phone, email string, userRole string) error {
+func (s *Storage) SaveUser(fullName, password, phone, email string, userRole string) (int64, error) {
 	const op = "storage.postgres.SaveUser"
 
-	query := s.sqlBuilder.Insert("users").Columns("full_name", "passhash", "phone", "email", "user_role").Values(fullName, password, phone, email, userRole)
-	_, err := query.Exec()
+	var id int64
+
+	query := s.sqlBuilder.Insert("users").Columns("full_name", "passhash", "phone", "email", "user_role").Values(fullName, password, phone, email, userRole).Suffix("RETURNING id")
+	err := query.QueryRow().Scan(&id)
 
 	if err != nil {
 		var pqError *pq.Error
 
 		if errors.As(err, &pqError) && pqError.Code == UniqueViolationCode {
-			return fmt.Errorf("%s: %w", op, storage.ErrUserExist)
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExist)
 		}
 
-		return fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nil
+	return id, nil
 }
 
 func (s *Storage) UserByEmail(email string) (*models.User, error) {
 	const op = "storage.postgres.UserByEmail"
 
-	query := s.sqlBuilder.Select("id", "passhash", "user_role", "deleted").From("users").Where(sq.Eq{"email": email})
+	query := s.sqlBuilder.Select("id", "passhash", "user_role", "deleted", "email_verified_at", "phone", "phone_verified_at", "pending_email", "pending_phone").From("users").Where(sq.Eq{"email": email})
 	rows, err := query.Query()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -55,16 +59,21 @@ func (s *Storage) UserByEmail(email string) (*models.User, error) {
 
 	for rows.Next() {
 		var (
-			id       int64
-			passHash string
-			userRole string
-			deleted  bool
+			id              int64
+			passHash        string
+			userRole        string
+			deleted         bool
+			emailVerifiedAt sql.NullTime
+			phone           string
+			phoneVerifiedAt sql.NullTime
+			pendingEmail    sql.NullString
+			pendingPhone    sql.NullString
 		)
-		if err := rows.Scan(&id, &passHash, &userRole, &deleted); err != nil {
+		if err := rows.Scan(&id, &passHash, &userRole, &deleted, &emailVerifiedAt, &phone, &phoneVerifiedAt, &pendingEmail, &pendingPhone); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		user = &models.User{Id: id, PassHash: []byte(passHash), RoleString: userRole, Email: email, Deleted: deleted}
+		user = &models.User{Id: id, PassHash: []byte(passHash), RoleString: userRole, Email: email, EmailVerifiedAt: nullTime(emailVerifiedAt), Phone: strings.TrimSpace(phone), PhoneVerifiedAt: nullTime(phoneVerifiedAt), PendingEmail: strings.TrimSpace(pendingEmail.String), PendingPhone: strings.TrimSpace(pendingPhone.String), Deleted: deleted}
 	}
 
 	if user == nil || user.Deleted {
@@ -77,7 +86,7 @@ func (s *Storage) UserByEmail(email string) (*models.User, error) {
 func (s *Storage) UserByPhone(phone string) (*models.User, error) {
 	const op = "storage.postgres.UserByPhone"
 
-	query := s.sqlBuilder.Select("id", "passhash", "user_role", "email", "deleted").From("users").Where(sq.Eq{"phone": phone})
+	query := s.sqlBuilder.Select("id", "passhash", "user_role", "email", "deleted", "email_verified_at", "phone", "phone_verified_at").From("users").Where(sq.Eq{"phone": phone})
 	rows, err := query.Query()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -94,17 +103,20 @@ func (s *Storage) UserByPhone(phone string) (*models.User, error) {
 
 	for rows.Next() {
 		var (
-			id       int64
-			passHash string
-			userRole string
-			email    string
-			deleted  bool
+			id              int64
+			passHash        string
+			userRole        string
+			email           string
+			deleted         bool
+			emailVerifiedAt sql.NullTime
+			userPhone       string
+			phoneVerifiedAt sql.NullTime
 		)
-		if err := rows.Scan(&id, &passHash, &userRole, &email, &deleted); err != nil {
+		if err := rows.Scan(&id, &passHash, &userRole, &email, &deleted, &emailVerifiedAt, &userPhone, &phoneVerifiedAt); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		user = &models.User{Id: id, PassHash: []byte(passHash), RoleString: userRole, Email: email, Deleted: deleted}
+		user = &models.User{Id: id, PassHash: []byte(passHash), RoleString: userRole, Email: strings.TrimSpace(email), EmailVerifiedAt: nullTime(emailVerifiedAt), Phone: strings.TrimSpace(userPhone), PhoneVerifiedAt: nullTime(phoneVerifiedAt), Deleted: deleted}
 	}
 
 	if user == nil || user.Deleted {
@@ -182,7 +194,7 @@ func (s *Storage) UpdatePassword(userId int64, newPassword string) error {
 func (s *Storage) UserByUserId(userId int64) (*models.User, error) {
 	const op = "storage.postgres.UserByEmail"
 
-	query := s.sqlBuilder.Select("id", "passhash", "user_role", "email", "deleted").From("users").Where(sq.Eq{"id": userId})
+	query := s.sqlBuilder.Select("id", "full_name", "passhash", "user_role", "email", "deleted", "email_verified_at", "phone", "phone_verified_at", "pending_email", "pending_phone", "version").From("users").Where(sq.Eq{"id": userId})
 	rows, err := query.Query()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -199,17 +211,24 @@ func (s *Storage) UserByUserId(userId int64) (*models.User, error) {
 
 	for rows.Next() {
 		var (
-			id       int64
-			passHash string
-			userRole string
-			email    string
-			deleted  bool
+			id              int64
+			fullName        string
+			passHash        string
+			userRole        string
+			email           string
+			deleted         bool
+			emailVerifiedAt sql.NullTime
+			phone           string
+			phoneVerifiedAt sql.NullTime
+			pendingEmail    sql.NullString
+			pendingPhone    sql.NullString
+			version         int64
 		)
-		if err := rows.Scan(&id, &passHash, &userRole, &email, &deleted); err != nil {
+		if err := rows.Scan(&id, &fullName, &passHash, &userRole, &email, &deleted, &emailVerifiedAt, &phone, &phoneVerifiedAt, &pendingEmail, &pendingPhone, &version); err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		user = &models.User{Id: id, PassHash: []byte(passHash), RoleString: userRole}
+		user = &models.User{Id: id, FullName: strings.TrimSpace(fullName), PassHash: []byte(passHash), RoleString: userRole, Email: strings.TrimSpace(email), EmailVerifiedAt: nullTime(emailVerifiedAt), Phone: strings.TrimSpace(phone), PhoneVerifiedAt: nullTime(phoneVerifiedAt), PendingEmail: strings.TrimSpace(pendingEmail.String), PendingPhone: strings.TrimSpace(pendingPhone.String), Version: version}
 	}
 
 	if user == nil || user.Deleted {
@@ -238,11 +257,49 @@ func (s *Storage) SaveLink(link string, linkTtl time.Duration, userId int64) err
 	return nil
 }
 
-func (s *Storage) UpdateUser(newFullName, newPhone, newEmail string, userId int64) error {
-	const op = "storage.postgres.UpdateUser"
+func (s *Storage) UpdateProfile(userId, version int64, fullName, pendingEmail, pendingPhone *string) error {
+	const op = "storage.postgres.UpdateProfile"
 
-	query := s.sqlBuilder.Update("users").Set("full_name", newFullName).Set("phone", newPhone).Set("email", newEmail).Where(sq.Eq{"id": userId})
-	_, err := query.Exec()
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		query := builder.Update("users").Set("version", sq.Expr("version + 1")).Where(sq.Eq{"id": userId, "version": version})
+		if fullName != nil {
+			query = query.Set("full_name", *fullName)
+		}
+		if pendingEmail != nil {
+			query = query.Set("pending_email", nullString(*pendingEmail))
+		}
+		if pendingPhone != nil {
+			query = query.Set("pending_phone", nullString(*pendingPhone))
+		}
+
+		result, err := query.Exec()
+		if err != nil {
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return storage.ErrVersionConflict
+		}
+
+		if pendingEmail != nil {
+			if _, err := builder.Delete("email_verifications").Where(sq.Eq{"user_id": userId}).Exec(); err != nil {
+				return err
+			}
+		}
+
+		if pendingPhone != nil {
+			if _, err := builder.Update("phone_verifications").Set("expiration", time.Now()).Where(sq.Eq{"user_id": userId}).Exec(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
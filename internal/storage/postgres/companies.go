@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/enums"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"log"
+)
+
+const (
+	companiesTable = "companies"
+)
+
+func (s *Storage) SaveEmployer(fullName, password, phone, email string, company models.Company) (int64, error) {
+	const op = "storage.postgres.SaveEmployer"
+
+	var userId int64
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		err := builder.Insert("users").Columns("full_name", "passhash", "phone", "email", "user_role").Values(fullName, password, phone, email, enums.RoleEmployer).
+			Suffix("RETURNING id").QueryRow().Scan(&userId)
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Insert("companies").Columns("user_id", "name", "inn", "ogrn", "legal_address", "contact_person").
+			Values(userId, company.Name, company.Inn, company.Ogrn, company.LegalAddress, company.ContactPerson).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		var pqError *pq.Error
+
+		if errors.As(err, &pqError) && pqError.Code == UniqueViolationCode {
+			if pqError.Table == companiesTable {
+				return 0, fmt.Errorf("%s: %w", op, storage.ErrCompanyExist)
+			}
+
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExist)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userId, nil
+}
+
+func (s *Storage) CompanyByUserId(userId int64) (*models.Company, error) {
+	const op = "storage.postgres.CompanyByUserId"
+
+	companies, err := s.companies(sq.Eq{"user_id": userId})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(companies) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrCompanyNotFound)
+	}
+
+	return &companies[0], nil
+}
+
+func (s *Storage) CompaniesByStatus(status models.CompanyStatus) ([]models.Company, error) {
+	const op = "storage.postgres.CompaniesByStatus"
+
+	companies, err := s.companies(sq.Eq{"status": string(status)})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return companies, nil
+}
+
+func (s *Storage) ReviewCompany(userId int64, status models.CompanyStatus, rejectionReason string) error {
+	const op = "storage.postgres.ReviewCompany"
+
+	res, err := s.sqlBuilder.Update("companies").
+		Set("status", string(status)).
+		Set("rejection_reason", rejectionReason).
+		Set("reviewed_at", sq.Expr("now()")).
+		Where(sq.Eq{"user_id": userId, "status": string(models.CompanyPending)}).Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCompanyNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) companies(where sq.Eq) ([]models.Company, error) {
+	query := s.sqlBuilder.Select("id", "user_id", "name", "inn", "ogrn", "legal_address", "contact_person", "status", "rejection_reason", "created_at", "reviewed_at").
+		From("companies").Where(where).OrderBy("created_at")
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	companies := make([]models.Company, 0)
+
+	for rows.Next() {
+		var (
+			company    models.Company
+			status     string
+			reviewedAt sql.NullTime
+		)
+		err := rows.Scan(&company.Id, &company.UserId, &company.Name, &company.Inn, &company.Ogrn, &company.LegalAddress,
+			&company.ContactPerson, &status, &company.RejectionReason, &company.CreatedAt, &reviewedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		company.Status = models.CompanyStatus(status)
+		company.ReviewedAt = nullTime(reviewedAt)
+
+		companies = append(companies, company)
+	}
+
+	return companies, nil
+}
@@ -7,6 +7,7 @@ import (
 	_ "github.com/lib/pq"
 	"log"
 	"os"
+	"time"
 )
 
 type Storage struct {
@@ -47,3 +48,30 @@ func New(path string) (*Storage, error) {
 
 	return &Storage{db: db, sqlBuilder: &sqlBuilder}, nil
 }
+
+func (s *Storage) withTx(fn func(builder sq.StatementBuilderType) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(s.sqlBuilder.RunWith(tx)); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+const (
+	signingKeysLockId = 7301
+)
+
+func (s *Storage) RotateSigningKey(kid, algorithm string, privateKey []byte, createdAt, expiresAt, rotatedAfter time.Time) error {
+	const op = "storage.postgres.RotateSigningKey"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		if _, err := builder.Select().Column(sq.Expr("pg_advisory_xact_lock(?)", signingKeysLockId)).Exec(); err != nil {
+			return err
+		}
+
+		var rotated int
+		err := builder.Select("count(*)").From("signing_keys").Where(sq.Eq{"algorithm": algorithm}).Where(sq.Gt{"created_at": rotatedAfter}).QueryRow().Scan(&rotated)
+		if err != nil {
+			return err
+		}
+
+		if rotated > 0 {
+			return storage.ErrSigningKeyRotated
+		}
+
+		_, err = builder.Insert("signing_keys").Columns("kid", "algorithm", "private_key", "created_at", "expires_at").Values(kid, algorithm, privateKey, createdAt, expiresAt).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Delete("signing_keys").Where(sq.LtOrEq{"expires_at": time.Now()}).Exec()
+
+		return err
+	})
+
+	if errors.Is(err, storage.ErrSigningKeyRotated) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SigningKeys() ([]models.SigningKey, error) {
+	const op = "storage.postgres.SigningKeys"
+
+	query := s.sqlBuilder.Select("kid", "algorithm", "private_key", "created_at", "expires_at").From("signing_keys").Where(sq.Gt{"expires_at": time.Now()}).OrderBy("created_at DESC")
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	keys := make([]models.SigningKey, 0)
+
+	for rows.Next() {
+		var (
+			kid        string
+			algorithm  string
+			privateKey []byte
+			createdAt  time.Time
+			expiresAt  time.Time
+		)
+		if err := rows.Scan(&kid, &algorithm, &privateKey, &createdAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys = append(keys, models.SigningKey{Kid: kid, Algorithm: algorithm, PrivateKey: privateKey, CreatedAt: createdAt, ExpiresAt: expiresAt})
+	}
+
+	return keys, nil
+}
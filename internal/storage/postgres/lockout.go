@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+func (s *Storage) LoginFailure(key string) (*models.LoginFailure, error) {
+	const op = "storage.postgres.LoginFailure"
+
+	query := s.sqlBuilder.Select("failures", "last_failure_at", "locked_until").From("login_failures").Where(sq.Eq{"key": key})
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	var loginFailure *models.LoginFailure
+
+	for rows.Next() {
+		var (
+			failures      int
+			lastFailureAt time.Time
+			lockedUntil   sql.NullTime
+		)
+		if err := rows.Scan(&failures, &lastFailureAt, &lockedUntil); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		loginFailure = &models.LoginFailure{Key: key, Failures: failures, LastFailureAt: lastFailureAt, LockedUntil: nullTime(lockedUntil)}
+	}
+
+	if loginFailure == nil {
+		return nil, storage.ErrLoginFailureNotFound
+	}
+
+	return loginFailure, nil
+}
+
+func (s *Storage) IncrementLoginFailures(key string, failedAt, windowStart time.Time) (int, error) {
+	const op = "storage.postgres.IncrementLoginFailures"
+
+	var failures int
+
+	query := s.sqlBuilder.Insert("login_failures").Columns("key", "failures", "last_failure_at").Values(key, 1, failedAt).
+		Suffix("ON CONFLICT (key) DO UPDATE SET failures = CASE WHEN login_failures.last_failure_at < ? THEN 1 ELSE login_failures.failures + 1 END, last_failure_at = EXCLUDED.last_failure_at RETURNING failures", windowStart)
+	err := query.QueryRow().Scan(&failures)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+func (s *Storage) LockLoginKey(key string, lockedUntil time.Time) error {
+	const op = "storage.postgres.LockLoginKey"
+
+	query := s.sqlBuilder.Update("login_failures").Set("locked_until", lockedUntil).Where(sq.Eq{"key": key})
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteLoginFailure(key string) error {
+	const op = "storage.postgres.DeleteLoginFailure"
+
+	query := s.sqlBuilder.Delete("login_failures").Where(sq.Eq{"key": key})
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
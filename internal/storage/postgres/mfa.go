@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+func (s *Storage) SaveTotpSecret(userId int64, secret string) error {
+	const op = "storage.postgres.SaveTotpSecret"
+
+	query := s.sqlBuilder.Insert("totp_secrets").Columns("user_id", "secret").Values(userId, secret).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, confirmed_at = NULL, last_used_step = 0, created_at = now()")
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) TotpSecret(userId int64) (*models.TotpSecret, error) {
+	const op = "storage.postgres.TotpSecret"
+
+	query := s.sqlBuilder.Select("secret", "confirmed_at", "last_used_step").From("totp_secrets").Where(sq.Eq{"user_id": userId})
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	var totpSecret *models.TotpSecret
+
+	for rows.Next() {
+		var (
+			secret       string
+			confirmedAt  sql.NullTime
+			lastUsedStep int64
+		)
+		if err := rows.Scan(&secret, &confirmedAt, &lastUsedStep); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		totpSecret = &models.TotpSecret{UserId: userId, Secret: secret, ConfirmedAt: nullTime(confirmedAt), LastUsedStep: lastUsedStep}
+	}
+
+	if totpSecret == nil {
+		return nil, storage.ErrTotpNotFound
+	}
+
+	return totpSecret, nil
+}
+
+func (s *Storage) ConfirmTotp(userId int64, recoveryCodeHashes []string) error {
+	const op = "storage.postgres.ConfirmTotp"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		_, err := builder.Update("totp_secrets").Set("confirmed_at", time.Now()).Where(sq.Eq{"user_id": userId}).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Delete("recovery_codes").Where(sq.Eq{"user_id": userId}).Exec()
+		if err != nil {
+			return err
+		}
+
+		insert := builder.Insert("recovery_codes").Columns("user_id", "code_hash")
+		for _, codeHash := range recoveryCodeHashes {
+			insert = insert.Values(userId, codeHash)
+		}
+
+		_, err = insert.Exec()
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) UseTotpStep(userId int64, step int64) error {
+	const op = "storage.postgres.UseTotpStep"
+
+	query := s.sqlBuilder.Update("totp_secrets").Set("last_used_step", step).Where(sq.Eq{"user_id": userId}).Where(sq.Lt{"last_used_step": step})
+	res, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return storage.ErrTotpStepUsed
+	}
+
+	return nil
+}
+
+func (s *Storage) UseRecoveryCode(userId int64, codeHash string) error {
+	const op = "storage.postgres.UseRecoveryCode"
+
+	query := s.sqlBuilder.Update("recovery_codes").Set("used_at", time.Now()).Where(sq.Eq{"user_id": userId, "code_hash": codeHash, "used_at": nil})
+	res, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return storage.ErrRecoveryCodeNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) UseMfaChallenge(jti string, expiration time.Time) error {
+	const op = "storage.postgres.UseMfaChallenge"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		res, err := builder.Insert("used_mfa_challenges").Columns("jti", "expiration").Values(jti, expiration).Suffix("ON CONFLICT DO NOTHING").Exec()
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return storage.ErrMfaChallengeUsed
+		}
+
+		_, err = builder.Delete("used_mfa_challenges").Where(sq.Lt{"expiration": time.Now()}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) MfaChallengeUsed(jti string) (bool, error) {
+	const op = "storage.postgres.MfaChallengeUsed"
+
+	var found int
+	err := s.sqlBuilder.Select("1").From("used_mfa_challenges").Where(sq.Eq{"jti": jti}).QueryRow().Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"log"
+	"time"
+)
+
+func (s *Storage) SaveOAuthClient(client models.OAuthClient) error {
+	const op = "storage.postgres.SaveOAuthClient"
+
+	query := s.sqlBuilder.Insert("oauth_clients").Columns("id", "secret_hash", "name", "redirect_uris", "scopes", "public", "service_account").
+		Values(client.Id, string(client.SecretHash), client.Name, pq.Array(client.RedirectUris), pq.Array(client.Scopes), client.Public, client.ServiceAccount)
+	_, err := query.Exec()
+
+	if err != nil {
+		var pqError *pq.Error
+
+		if errors.As(err, &pqError) && pqError.Code == UniqueViolationCode {
+			return fmt.Errorf("%s: %w", op, storage.ErrOAuthClientExist)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) OAuthClient(id string) (*models.OAuthClient, error) {
+	const op = "storage.postgres.OAuthClient"
+
+	clients, err := s.oauthClients(sq.Eq{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(clients) == 0 {
+		return nil, storage.ErrOAuthClientNotFound
+	}
+
+	return &clients[0], nil
+}
+
+func (s *Storage) OAuthClients() ([]models.OAuthClient, error) {
+	const op = "storage.postgres.OAuthClients"
+
+	clients, err := s.oauthClients(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return clients, nil
+}
+
+func (s *Storage) ClientPermissions(clientId string) ([]string, error) {
+	const op = "storage.postgres.ClientPermissions"
+
+	query := s.sqlBuilder.Select("permissions").From("oauth_clients").Where(sq.Eq{"id": clientId, "service_account": true})
+
+	var permissions []string
+	err := query.QueryRow().Scan(pq.Array(&permissions))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrOAuthClientNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return permissions, nil
+}
+
+func (s *Storage) SetClientPermissions(clientId string, permissions []string) error {
+	const op = "storage.postgres.SetClientPermissions"
+
+	res, err := s.sqlBuilder.Update("oauth_clients").Set("permissions", pq.Array(permissions)).
+		Where(sq.Eq{"id": clientId, "service_account": true}).Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return storage.ErrOAuthClientNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) OAuthConsent(userId int64, clientId string) (*models.OAuthConsent, error) {
+	const op = "storage.postgres.OAuthConsent"
+
+	query := s.sqlBuilder.Select("scopes", "created_at").From("oauth_consents").Where(sq.Eq{"user_id": userId, "client_id": clientId})
+
+	var (
+		scopes    []string
+		createdAt time.Time
+	)
+	err := query.QueryRow().Scan(pq.Array(&scopes), &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrOAuthConsentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.OAuthConsent{UserId: userId, ClientId: clientId, Scopes: scopes, CreatedAt: createdAt}, nil
+}
+
+func (s *Storage) SaveOAuthConsent(userId int64, clientId string, scopes []string) error {
+	const op = "storage.postgres.SaveOAuthConsent"
+
+	query := s.sqlBuilder.Insert("oauth_consents").Columns("user_id", "client_id", "scopes").Values(userId, clientId, pq.Array(scopes)).
+		Suffix("ON CONFLICT (user_id, client_id) DO UPDATE SET scopes = EXCLUDED.scopes, created_at = now()")
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveAuthorizationCode(code models.AuthorizationCode) error {
+	const op = "storage.postgres.SaveAuthorizationCode"
+
+	query := s.sqlBuilder.Insert("oauth_codes").
+		Columns("code_hash", "client_id", "user_id", "redirect_uri", "scope", "code_challenge", "code_challenge_method", "nonce", "expiration").
+		Values(code.CodeHash, code.ClientId, code.UserId, code.RedirectUri, code.Scope, code.CodeChallenge, code.CodeChallengeMethod, code.Nonce, code.Expiration)
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) UseAuthorizationCode(codeHash string) (*models.AuthorizationCode, error) {
+	const op = "storage.postgres.UseAuthorizationCode"
+
+	query := s.sqlBuilder.Update("oauth_codes").Set("used", true).Where(sq.Eq{"code_hash": codeHash, "used": false}).
+		Suffix("RETURNING client_id, user_id, redirect_uri, scope, code_challenge, code_challenge_method, nonce, expiration")
+
+	code := models.AuthorizationCode{CodeHash: codeHash, Used: true}
+	err := query.QueryRow().Scan(&code.ClientId, &code.UserId, &code.RedirectUri, &code.Scope, &code.CodeChallenge, &code.CodeChallengeMethod, &code.Nonce, &code.Expiration)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrAuthorizationCodeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &code, nil
+}
+
+func (s *Storage) oauthClients(where sq.Sqlizer) ([]models.OAuthClient, error) {
+	query := s.sqlBuilder.Select("id", "secret_hash", "name", "redirect_uris", "scopes", "public", "service_account", "permissions", "created_at").From("oauth_clients").OrderBy("created_at")
+	if where != nil {
+		query = query.Where(where)
+	}
+
+	rows, err := query.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	clients := make([]models.OAuthClient, 0)
+
+	for rows.Next() {
+		var (
+			client     models.OAuthClient
+			secretHash string
+		)
+		err := rows.Scan(&client.Id, &secretHash, &client.Name, pq.Array(&client.RedirectUris), pq.Array(&client.Scopes), &client.Public, &client.ServiceAccount, pq.Array(&client.Permissions), &client.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		client.SecretHash = []byte(secretHash)
+
+		clients = append(clients, client)
+	}
+
+	return clients, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"log"
+	"time"
+)
+
+func (s *Storage) Permissions() ([]models.Permission, error) {
+	const op = "storage.postgres.Permissions"
+
+	query := s.sqlBuilder.Select("name", "description", "created_at").From("permissions").OrderBy("name")
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	permissions := make([]models.Permission, 0)
+
+	for rows.Next() {
+		var (
+			name        string
+			description string
+			createdAt   time.Time
+		)
+		if err := rows.Scan(&name, &description, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		permissions = append(permissions, models.Permission{Name: name, Description: description, CreatedAt: createdAt})
+	}
+
+	return permissions, nil
+}
+
+func (s *Storage) RolePermissions(role string) ([]string, error) {
+	const op = "storage.postgres.RolePermissions"
+
+	query := s.sqlBuilder.Select("permission").From("role_permissions").Where(sq.Eq{"role": role}).OrderBy("permission")
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	permissions := make([]string, 0)
+
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
+
+func (s *Storage) GrantPermission(role, permission string) error {
+	const op = "storage.postgres.GrantPermission"
+
+	_, err := s.sqlBuilder.Insert("role_permissions").Columns("role", "permission").Values(role, permission).
+		Suffix("ON CONFLICT DO NOTHING").Exec()
+	if err != nil {
+		var pqError *pq.Error
+
+		if errors.As(err, &pqError) && pqError.Code == ForeignKeyViolationCode {
+			return fmt.Errorf("%s: %w", op, storage.ErrPermissionNotFound)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokePermission(role, permission string) error {
+	const op = "storage.postgres.RevokePermission"
+
+	_, err := s.sqlBuilder.Delete("role_permissions").Where(sq.Eq{"role": role, "permission": permission}).Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"log"
+	"time"
+)
+
+func (s *Storage) SavePhoneVerification(userId int64, codeHash string, expiration, windowStart time.Time) error {
+	const op = "storage.postgres.SavePhoneVerification"
+
+	query := s.sqlBuilder.Insert("phone_verifications").Columns("user_id", "code_hash", "expiration").Values(userId, codeHash, expiration).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET code_hash = EXCLUDED.code_hash, expiration = EXCLUDED.expiration, "+
+			"attempts = CASE WHEN phone_verifications.window_started_at <= ? THEN 0 ELSE phone_verifications.attempts END, "+
+			"window_started_at = CASE WHEN phone_verifications.window_started_at <= ? THEN now() ELSE phone_verifications.window_started_at END, "+
+			"created_at = now()", windowStart, windowStart)
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) PhoneVerification(userId int64) (*models.PhoneVerification, error) {
+	const op = "storage.postgres.PhoneVerification"
+
+	query := s.sqlBuilder.Select("code_hash", "expiration", "attempts", "window_started_at", "created_at").From("phone_verifications").Where(sq.Eq{"user_id": userId})
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	var verification *models.PhoneVerification
+
+	for rows.Next() {
+		var (
+			codeHash        string
+			expiration      time.Time
+			attempts        int
+			windowStartedAt time.Time
+			createdAt       time.Time
+		)
+		if err := rows.Scan(&codeHash, &expiration, &attempts, &windowStartedAt, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		verification = &models.PhoneVerification{UserId: userId, CodeHash: codeHash, Expiration: expiration, Attempts: attempts, WindowStartedAt: windowStartedAt, CreatedAt: createdAt}
+	}
+
+	if verification == nil {
+		return nil, storage.ErrVerificationNotFound
+	}
+
+	return verification, nil
+}
+
+func (s *Storage) IncrementPhoneVerificationAttempts(userId int64) error {
+	const op = "storage.postgres.IncrementPhoneVerificationAttempts"
+
+	query := s.sqlBuilder.Update("phone_verifications").Set("attempts", sq.Expr("attempts + 1")).Where(sq.Eq{"user_id": userId})
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ConfirmPhone(userId int64) error {
+	const op = "storage.postgres.ConfirmPhone"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		_, err := builder.Update("users").
+			Set("phone", sq.Expr("COALESCE(pending_phone, phone)")).
+			Set("pending_phone", nil).
+			Set("phone_verified_at", time.Now()).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": userId}).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Delete("phone_verifications").Where(sq.Eq{"user_id": userId}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		var pqError *pq.Error
+
+		if errors.As(err, &pqError) && pqError.Code == UniqueViolationCode {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserExist)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
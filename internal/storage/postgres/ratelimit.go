@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"time"
+)
+
+const (
+	refilledTokens = "LEAST(?::double precision, rate_limit_buckets.tokens + EXTRACT(EPOCH FROM (EXCLUDED.updated_at - rate_limit_buckets.updated_at)) * ?::double precision)"
+)
+
+func (s *Storage) TakeRateLimitToken(key string, rate float64, burst int) (bool, float64, error) {
+	const op = "storage.postgres.TakeRateLimitToken"
+
+	var (
+		allowed bool
+		tokens  float64
+	)
+
+	query := s.sqlBuilder.Insert("rate_limit_buckets").Columns("key", "tokens", "allowed", "updated_at").Values(key, float64(burst)-1, burst >= 1, time.Now()).
+		Suffix(fmt.Sprintf("ON CONFLICT (key) DO UPDATE SET tokens = CASE WHEN %[1]s >= 1 THEN %[1]s - 1 ELSE %[1]s END, allowed = %[1]s >= 1, updated_at = EXCLUDED.updated_at RETURNING allowed, tokens", refilledTokens),
+			burst, rate, burst, rate, burst, rate, burst, rate)
+	err := query.QueryRow().Scan(&allowed, &tokens)
+
+	if err != nil {
+		return false, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, tokens, nil
+}
+
+func (s *Storage) DeleteRateLimitBuckets(updatedBefore time.Time) error {
+	const op = "storage.postgres.DeleteRateLimitBuckets"
+
+	query := s.sqlBuilder.Delete("rate_limit_buckets").Where(sq.Lt{"updated_at": updatedBefore})
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"time"
+)
+
+func (s *Storage) RevokeToken(jti string, expiration time.Time) error {
+	const op = "storage.postgres.RevokeToken"
+
+	query := s.sqlBuilder.Insert("revoked_tokens").Columns("jti", "expiration").Values(jti, expiration).Suffix("ON CONFLICT DO NOTHING")
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) TokenRevoked(jti string) (bool, error) {
+	const op = "storage.postgres.TokenRevoked"
+
+	var found int
+	err := s.sqlBuilder.Select("1").From("revoked_tokens").Where(sq.Eq{"jti": jti}).QueryRow().Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
+
+func (s *Storage) DeleteExpiredRevokedTokens() error {
+	const op = "storage.postgres.DeleteExpiredRevokedTokens"
+
+	_, err := s.sqlBuilder.Delete("revoked_tokens").Where(sq.Lt{"expiration": time.Now()}).Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+func (s *Storage) SaveSession(id string, userId int64, device, ip string) error {
+	const op = "storage.postgres.SaveSession"
+
+	query := s.sqlBuilder.Insert("sessions").Columns("id", "user_id", "device", "ip").Values(id, userId, device, ip)
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ActiveSession(id string) (*models.Session, error) {
+	const op = "storage.postgres.ActiveSession"
+
+	query := s.sqlBuilder.Select("user_id", "device", "ip", "created_at", "last_seen_at").From("sessions").Where(sq.Eq{"id": id, "revoked": false})
+
+	session := models.Session{Id: id}
+	err := query.QueryRow().Scan(&session.UserId, &session.Device, &session.Ip, &session.CreatedAt, &session.LastSeenAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &session, nil
+}
+
+func (s *Storage) TouchSession(id string) error {
+	const op = "storage.postgres.TouchSession"
+
+	query := s.sqlBuilder.Update("sessions").Set("last_seen_at", time.Now()).Where(sq.Eq{"id": id, "revoked": false})
+	res, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return storage.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) SessionsByUserId(userId int64) ([]models.Session, error) {
+	const op = "storage.postgres.SessionsByUserId"
+
+	query := s.sqlBuilder.Select("id", "device", "ip", "created_at", "last_seen_at").From("sessions").Where(sq.Eq{"user_id": userId, "revoked": false}).OrderBy("last_seen_at DESC")
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	sessions := make([]models.Session, 0)
+
+	for rows.Next() {
+		var (
+			id         string
+			device     string
+			ip         string
+			createdAt  time.Time
+			lastSeenAt time.Time
+		)
+		if err := rows.Scan(&id, &device, &ip, &createdAt, &lastSeenAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		sessions = append(sessions, models.Session{Id: id, UserId: userId, Device: device, Ip: ip, CreatedAt: createdAt, LastSeenAt: lastSeenAt})
+	}
+
+	return sessions, nil
+}
+
+func (s *Storage) RevokeSession(id string, userId int64) error {
+	const op = "storage.postgres.RevokeSession"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		res, err := builder.Update("sessions").Set("revoked", true).Where(sq.Eq{"id": id, "user_id": userId, "revoked": false}).Exec()
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return storage.ErrSessionNotFound
+		}
+
+		_, err = builder.Update("refresh_tokens").Set("revoked", true).Where(sq.Eq{"family_id": id}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeUserSessions(userId int64) error {
+	const op = "storage.postgres.RevokeUserSessions"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		_, err := builder.Update("sessions").Set("revoked", true).Where(sq.Eq{"user_id": userId, "revoked": false}).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Update("refresh_tokens").Set("revoked", true).Where(sq.Eq{"user_id": userId, "revoked": false}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
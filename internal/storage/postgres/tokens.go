@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"log"
+	"time"
+)
+
+func (s *Storage) SaveRefreshToken(tokenHash string, userId int64, familyId, clientId, scope string, expiration time.Time) error {
+	const op = "storage.postgres.SaveRefreshToken"
+
+	query := s.sqlBuilder.Insert("refresh_tokens").Columns("token_hash", "user_id", "family_id", "client_id", "scope", "expiration").Values(tokenHash, userId, familyId, clientId, scope, expiration)
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	const op = "storage.postgres.RefreshToken"
+
+	query := s.sqlBuilder.Select("id", "user_id", "family_id", "client_id", "scope", "expiration", "used", "revoked").From("refresh_tokens").Where(sq.Eq{"token_hash": tokenHash})
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	var refreshToken *models.RefreshToken
+
+	for rows.Next() {
+		var (
+			id         int64
+			userId     int64
+			familyId   string
+			clientId   string
+			scope      string
+			expiration time.Time
+			used       bool
+			revoked    bool
+		)
+		if err := rows.Scan(&id, &userId, &familyId, &clientId, &scope, &expiration, &used, &revoked); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		refreshToken = &models.RefreshToken{Id: id, TokenHash: tokenHash, UserId: userId, FamilyId: familyId, ClientId: clientId, Scope: scope, Expiration: expiration, Used: used, Revoked: revoked}
+	}
+
+	if refreshToken == nil {
+		return nil, storage.ErrRefreshTokenNotFound
+	}
+
+	return refreshToken, nil
+}
+
+func (s *Storage) UseRefreshToken(id int64) error {
+	const op = "storage.postgres.UseRefreshToken"
+
+	query := s.sqlBuilder.Update("refresh_tokens").Set("used", true).Where(sq.Eq{"id": id, "used": false})
+	res, err := query.Exec()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenUsed)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeRefreshTokenFamily(familyId string) error {
+	const op = "storage.postgres.RevokeRefreshTokenFamily"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		_, err := builder.Update("refresh_tokens").Set("revoked", true).Where(sq.Eq{"family_id": familyId}).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Update("sessions").Set("revoked", true).Where(sq.Eq{"id": familyId}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
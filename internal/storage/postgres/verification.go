@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/storage"
+	"database/sql"
+	"errors"
+	"fmt"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+	"log"
+	"time"
+)
+
+func (s *Storage) SaveEmailVerification(tokenHash string, userId int64, expiration time.Time) error {
+	const op = "storage.postgres.SaveEmailVerification"
+
+	query := s.sqlBuilder.Insert("email_verifications").Columns("token_hash", "user_id", "expiration").Values(tokenHash, userId, expiration)
+	_, err := query.Exec()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) EmailVerification(tokenHash string) (*models.EmailVerification, error) {
+	const op = "storage.postgres.EmailVerification"
+
+	query := s.sqlBuilder.Select("id", "user_id", "expiration", "created_at").From("email_verifications").Where(sq.Eq{"token_hash": tokenHash})
+	rows, err := query.Query()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}(rows)
+
+	var verification *models.EmailVerification
+
+	for rows.Next() {
+		var (
+			id         int64
+			userId     int64
+			expiration time.Time
+			createdAt  time.Time
+		)
+		if err := rows.Scan(&id, &userId, &expiration, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		verification = &models.EmailVerification{Id: id, UserId: userId, Expiration: expiration, CreatedAt: createdAt}
+	}
+
+	if verification == nil {
+		return nil, storage.ErrVerificationNotFound
+	}
+
+	return verification, nil
+}
+
+func (s *Storage) LastEmailVerificationAt(userId int64) (*time.Time, error) {
+	const op = "storage.postgres.LastEmailVerificationAt"
+
+	var createdAt sql.NullTime
+
+	query := s.sqlBuilder.Select("max(created_at)").From("email_verifications").Where(sq.Eq{"user_id": userId})
+	err := query.QueryRow().Scan(&createdAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nullTime(createdAt), nil
+}
+
+func (s *Storage) ConfirmEmail(userId int64) error {
+	const op = "storage.postgres.ConfirmEmail"
+
+	err := s.withTx(func(builder sq.StatementBuilderType) error {
+		_, err := builder.Update("users").
+			Set("email", sq.Expr("COALESCE(pending_email, email)")).
+			Set("pending_email", nil).
+			Set("email_verified_at", time.Now()).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"id": userId}).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = builder.Delete("email_verifications").Where(sq.Eq{"user_id": userId}).Exec()
+
+		return err
+	})
+
+	if err != nil {
+		var pqError *pq.Error
+
+		if errors.As(err, &pqError) && pqError.Code == UniqueViolationCode {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserExist)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -5,7 +5,25 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
-	ErrUserExist    = errors.New("user exists")
-	LinkNotFound    = errors.New("link not found")
+	ErrUserNotFound              = errors.New("user not found")
+	ErrUserExist                 = errors.New("user exists")
+	LinkNotFound                 = errors.New("link not found")
+	ErrRefreshTokenNotFound      = errors.New("refresh token not found")
+	ErrRefreshTokenUsed          = errors.New("refresh token already used")
+	ErrSessionNotFound           = errors.New("session not found")
+	ErrTotpNotFound              = errors.New("totp secret not found")
+	ErrTotpStepUsed              = errors.New("totp code already used")
+	ErrRecoveryCodeNotFound      = errors.New("recovery code not found")
+	ErrVerificationNotFound      = errors.New("verification not found")
+	ErrLoginFailureNotFound      = errors.New("login failure not found")
+	ErrCompanyExist              = errors.New("company exists")
+	ErrCompanyNotFound           = errors.New("company not found")
+	ErrPermissionNotFound        = errors.New("permission not found")
+	ErrOAuthClientExist          = errors.New("oauth client exists")
+	ErrOAuthClientNotFound       = errors.New("oauth client not found")
+	ErrOAuthConsentNotFound      = errors.New("oauth consent not found")
+	ErrAuthorizationCodeNotFound = errors.New("authorization code not found")
+	ErrVersionConflict           = errors.New("version conflict")
+	ErrMfaChallengeUsed          = errors.New("mfa challenge already used")
+	ErrSigningKeyRotated         = errors.New("signing key already rotated")
 )
@@ -30,4 +30,264 @@ CREATE TABLE IF NOT EXISTS forget_password_info(
     created_at timestamp not null default now()
 );
 
-CREATE INDEX IF NOT EXISTS idx_link ON forget_password_info(link);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_link ON forget_password_info(link);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+    id bigserial PRIMARY KEY,
+    token_hash text NOT NULL UNIQUE,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    family_id text NOT NULL,
+    expiration timestamp NOT NULL,
+    used bool NOT NULL default false,
+    revoked bool NOT NULL default false,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_hash ON refresh_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_family ON refresh_tokens(family_id);
+
+CREATE TABLE IF NOT EXISTS sessions(
+    id text PRIMARY KEY,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    device text NOT NULL default '',
+    ip text NOT NULL default '',
+    revoked bool NOT NULL default false,
+    created_at timestamp not null default now(),
+    last_seen_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_user ON sessions(user_id);
+
+CREATE TABLE IF NOT EXISTS signing_keys(
+    kid text PRIMARY KEY,
+    algorithm text NOT NULL,
+    private_key bytea NOT NULL,
+    created_at timestamp not null default now(),
+    expires_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS totp_secrets(
+    user_id bigint PRIMARY KEY,
+    foreign key (user_id) references users(id),
+    secret text NOT NULL,
+    confirmed_at timestamp,
+    last_used_step bigint NOT NULL default 0,
+    created_at timestamp not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes(
+    id bigserial PRIMARY KEY,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    code_hash text NOT NULL,
+    used_at timestamp,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_code_user ON recovery_codes(user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamp;
+
+CREATE TABLE IF NOT EXISTS email_verifications(
+    id bigserial PRIMARY KEY,
+    token_hash text NOT NULL UNIQUE,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    expiration timestamp NOT NULL,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verification_user ON email_verifications(user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at timestamp;
+
+CREATE TABLE IF NOT EXISTS phone_verifications(
+    user_id bigint PRIMARY KEY,
+    foreign key (user_id) references users(id),
+    code_hash text NOT NULL,
+    expiration timestamp NOT NULL,
+    attempts int NOT NULL default 0,
+    created_at timestamp not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS login_failures(
+    key text PRIMARY KEY,
+    failures int NOT NULL default 0,
+    last_failure_at timestamp NOT NULL,
+    locked_until timestamp
+);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets(
+    key text PRIMARY KEY,
+    tokens double precision NOT NULL,
+    allowed bool NOT NULL default true,
+    updated_at timestamp NOT NULL
+);
+
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'company_statuses') THEN
+            CREATE TYPE company_statuses AS ENUM ('pending', 'approved', 'rejected');
+        END IF;
+END$$;
+
+CREATE TABLE IF NOT EXISTS companies(
+    id bigserial PRIMARY KEY,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL UNIQUE,
+    name text NOT NULL,
+    inn text NOT NULL UNIQUE,
+    ogrn text NOT NULL UNIQUE,
+    legal_address text NOT NULL,
+    contact_person text NOT NULL,
+    status company_statuses NOT NULL default 'pending',
+    rejection_reason text NOT NULL default '',
+    reviewed_at timestamp,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_status ON companies(status);
+
+CREATE TABLE IF NOT EXISTS permissions(
+    name text PRIMARY KEY,
+    description text NOT NULL default '',
+    created_at timestamp not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions(
+    role user_roles NOT NULL,
+    foreign key (permission) references permissions(name) ON DELETE CASCADE,
+    permission text NOT NULL,
+    created_at timestamp not null default now(),
+    PRIMARY KEY (role, permission)
+);
+
+WITH inserted AS (
+    INSERT INTO permissions(name, description) VALUES
+        ('users:read:self', 'Read own profile'),
+        ('users:read:any', 'Read any user profile'),
+        ('users:update:self', 'Update own profile'),
+        ('users:update:any', 'Update any user profile'),
+        ('users:delete:self', 'Delete own account'),
+        ('users:delete:any', 'Delete any account'),
+        ('users:restore:self', 'Restore own account'),
+        ('users:restore:any', 'Restore any account'),
+        ('users:unlock:any', 'Unlock locked accounts'),
+        ('employers:moderate:any', 'Approve or reject employers'),
+        ('permissions:manage:any', 'Manage role permissions')
+    ON CONFLICT DO NOTHING
+    RETURNING name
+)
+INSERT INTO role_permissions(role, permission)
+SELECT seed.role::user_roles, seed.permission
+FROM (VALUES
+    ('jobseeker', 'users:read:self'),
+    ('jobseeker', 'users:update:self'),
+    ('jobseeker', 'users:delete:self'),
+    ('jobseeker', 'users:restore:self'),
+    ('employer', 'users:read:self'),
+    ('employer', 'users:update:self'),
+    ('employer', 'users:delete:self'),
+    ('employer', 'users:restore:self'),
+    ('admin', 'users:read:any'),
+    ('admin', 'users:update:any'),
+    ('admin', 'users:delete:any'),
+    ('admin', 'users:restore:any'),
+    ('admin', 'users:unlock:any'),
+    ('admin', 'employers:moderate:any'),
+    ('admin', 'permissions:manage:any')
+) AS seed(role, permission)
+JOIN inserted ON inserted.name = seed.permission
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS oauth_clients(
+    id text PRIMARY KEY,
+    secret_hash text NOT NULL default '',
+    name text NOT NULL,
+    redirect_uris text[] NOT NULL,
+    scopes text[] NOT NULL,
+    public bool NOT NULL default false,
+    created_at timestamp not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS oauth_consents(
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    foreign key (client_id) references oauth_clients(id) ON DELETE CASCADE,
+    client_id text NOT NULL,
+    scopes text[] NOT NULL,
+    created_at timestamp not null default now(),
+    PRIMARY KEY (user_id, client_id)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_codes(
+    code_hash text PRIMARY KEY,
+    foreign key (client_id) references oauth_clients(id) ON DELETE CASCADE,
+    client_id text NOT NULL,
+    foreign key (user_id) references users(id),
+    user_id bigint NOT NULL,
+    redirect_uri text NOT NULL,
+    scope text NOT NULL,
+    code_challenge text NOT NULL,
+    code_challenge_method text NOT NULL,
+    expiration timestamp NOT NULL,
+    used bool NOT NULL default false,
+    created_at timestamp not null default now()
+);
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_id text NOT NULL default '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS scope text NOT NULL default '';
+
+WITH inserted AS (
+    INSERT INTO permissions(name, description) VALUES
+        ('oauth_clients:manage:any', 'Register and list OAuth clients')
+    ON CONFLICT DO NOTHING
+    RETURNING name
+)
+INSERT INTO role_permissions(role, permission)
+SELECT 'admin', inserted.name FROM inserted
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE oauth_codes ADD COLUMN IF NOT EXISTS nonce text NOT NULL default '';
+
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS service_account bool NOT NULL default false;
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS permissions text[] NOT NULL default '{}';
+
+WITH inserted AS (
+    INSERT INTO permissions(name, description) VALUES
+        ('service_accounts:manage:any', 'Register service accounts and manage their permissions')
+    ON CONFLICT DO NOTHING
+    RETURNING name
+)
+INSERT INTO role_permissions(role, permission)
+SELECT 'admin', inserted.name FROM inserted
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS revoked_tokens(
+    jti text PRIMARY KEY,
+    expiration timestamp NOT NULL,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_token_expiration ON revoked_tokens(expiration);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email char(100);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_phone char(50);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version bigint not null default 1;
+
+CREATE TABLE IF NOT EXISTS used_mfa_challenges(
+    jti text PRIMARY KEY,
+    expiration timestamp NOT NULL,
+    created_at timestamp not null default now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_mfa_challenge_expiration ON used_mfa_challenges(expiration);
+
+ALTER TABLE phone_verifications ADD COLUMN IF NOT EXISTS window_started_at timestamp not null default now();
+
+INSERT INTO permissions(name, description) VALUES
+    ('tokens:introspect:any', 'Introspect tokens issued to other clients')
+ON CONFLICT DO NOTHING;
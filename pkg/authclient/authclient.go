@@ -0,0 +1,262 @@
+package authclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	BearerSchema = "Bearer "
+
+	RoleJobSeeker = "jobseeker"
+	RoleEmployer  = "employer"
+	RoleAdmin     = "admin"
+
+	typeService = "service"
+)
+
+var (
+	ErrTokenNotFound = errors.New("token doesn't exist")
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrAccessDenied  = errors.New("access not allowed")
+)
+
+type Principal struct {
+	UserId         int64
+	Role           string
+	SessionId      string
+	EmployerStatus string
+	ClientId       string
+	Scopes         []string
+	ServiceAccount bool
+	Permissions    []string
+	TokenId        string
+	IssuedAt       time.Time
+	ExpiresAt      time.Time
+}
+
+func (p *Principal) Delegated() bool {
+	return p.ClientId != "" && !p.ServiceAccount
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	if p.ServiceAccount || p.Delegated() {
+		return false
+	}
+
+	return containsAny([]string{p.Role}, roles)
+}
+
+func (p *Principal) HasPermission(permissions ...string) bool {
+	return containsAny(p.Permissions, permissions)
+}
+
+func (p *Principal) HasScope(scopes ...string) bool {
+	return containsAny(p.Scopes, scopes)
+}
+
+type Verifier struct {
+	keys *KeySet
+}
+
+func New(client *http.Client, jwksUrl string, refreshInterval time.Duration) *Verifier {
+	return &Verifier{keys: NewKeySet(client, jwksUrl, refreshInterval)}
+}
+
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Principal, error) {
+	const op = "authclient.Verifier.Verify"
+
+	if tokenString == "" {
+		return nil, ErrTokenNotFound
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+
+		algorithm, key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+
+		if algorithm != "" && algorithm != token.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+
+		return key, nil
+	}, jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidToken, err)
+	}
+
+	principal, err := principalFromClaims(claims)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return principal, nil
+}
+
+func (v *Verifier) VerifyRequest(r *http.Request) (*Principal, error) {
+	tokenString, ok := BearerToken(r.Header.Get("Authorization"))
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return v.Verify(r.Context(), tokenString)
+}
+
+func BearerToken(header string) (string, bool) {
+	if len(header) < len(BearerSchema) || !strings.EqualFold(header[:len(BearerSchema)], BearerSchema) {
+		return "", false
+	}
+
+	tokenString := strings.TrimSpace(header[len(BearerSchema):])
+	if tokenString == "" {
+		return "", false
+	}
+
+	return tokenString, true
+}
+
+type Requirement func(principal *Principal) error
+
+func RequireRole(roles ...string) Requirement {
+	return func(principal *Principal) error {
+		if !principal.HasRole(roles...) {
+			return ErrAccessDenied
+		}
+
+		return nil
+	}
+}
+
+func RequirePermission(permissions ...string) Requirement {
+	return func(principal *Principal) error {
+		if !principal.HasPermission(permissions...) {
+			return ErrAccessDenied
+		}
+
+		return nil
+	}
+}
+
+func RequireScope(scopes ...string) Requirement {
+	return func(principal *Principal) error {
+		if !principal.HasScope(scopes...) {
+			return ErrAccessDenied
+		}
+
+		return nil
+	}
+}
+
+func Check(principal *Principal, requirements ...Requirement) error {
+	for _, requirement := range requirements {
+		if err := requirement(principal); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type ctxKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(ctxKey{}).(*Principal)
+
+	return principal, ok
+}
+
+func principalFromClaims(claims jwt.MapClaims) (*Principal, error) {
+	principal := &Principal{}
+
+	principal.TokenId, _ = claims["jti"].(string)
+	principal.ClientId, _ = claims["client_id"].(string)
+	principal.Permissions = stringSlice(claims["permissions"])
+
+	if scope, _ := claims["scope"].(string); scope != "" {
+		principal.Scopes = strings.Fields(scope)
+	}
+
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		principal.IssuedAt = iat.Time
+	}
+
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		principal.ExpiresAt = exp.Time
+	}
+
+	typ, hasTyp := claims["typ"]
+	if typ == typeService {
+		if principal.ClientId == "" {
+			return nil, ErrInvalidToken
+		}
+
+		principal.ServiceAccount = true
+
+		return principal, nil
+	}
+
+	if hasTyp {
+		return nil, ErrInvalidToken
+	}
+
+	userId, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	sessionId, ok := claims["sid"].(string)
+	if !ok || sessionId == "" {
+		return nil, ErrInvalidToken
+	}
+
+	principal.UserId = int64(userId)
+	principal.SessionId = sessionId
+	principal.Role, _ = claims["role"].(string)
+	principal.EmployerStatus, _ = claims["employer_status"].(string)
+
+	return principal, nil
+}
+
+func stringSlice(value any) []string {
+	values, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+func containsAny(values []string, wanted []string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if v == w {
+				return true
+			}
+		}
+	}
+
+	return false
+}
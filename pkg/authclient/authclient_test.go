@@ -0,0 +1,262 @@
+package authclient
+
+import (
+	"auth/internal/domain/models"
+	"auth/internal/lib/jwk"
+	"auth/internal/lib/jwt"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type staticKeys struct {
+	key *jwt.Key
+}
+
+func (k *staticKeys) SigningKey() (*jwt.Key, error) {
+	return k.key, nil
+}
+
+func (k *staticKeys) VerificationKey(kid string) (*jwt.Key, error) {
+	if kid != k.key.Kid {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	return k.key, nil
+}
+
+func newKeys(t *testing.T, kid string) *staticKeys {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &staticKeys{key: &jwt.Key{Kid: kid, Algorithm: jwt.AlgorithmES256, PrivateKey: privateKey}}
+}
+
+type jwksServer struct {
+	mu      sync.Mutex
+	keys    []*staticKeys
+	fetches int
+}
+
+func (s *jwksServer) publish(keys ...*staticKeys) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = keys
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetches++
+
+	set := jwk.Set{}
+	for _, keys := range s.keys {
+		key, err := jwk.FromPublicKey(keys.key.Kid, keys.key.Algorithm, keys.key.PrivateKey.Public())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		set.Keys = append(set.Keys, key)
+	}
+
+	_ = json.NewEncoder(w).Encode(set)
+}
+
+func newVerifier(t *testing.T, keys ...*staticKeys) (*Verifier, *jwksServer) {
+	t.Helper()
+
+	jwks := &jwksServer{}
+	jwks.publish(keys...)
+
+	server := httptest.NewServer(jwks)
+	t.Cleanup(server.Close)
+
+	return New(server.Client(), server.URL, time.Hour), jwks
+}
+
+func userToken(t *testing.T, keys *staticKeys, permissions []string, duration time.Duration) string {
+	t.Helper()
+
+	token, err := jwt.NewToken(models.User{Id: 1, Role: models.Employer}, "session", permissions, keys, duration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestVerifyUserToken(t *testing.T) {
+	keys := newKeys(t, "first")
+	verifier, _ := newVerifier(t, keys)
+
+	principal, err := verifier.Verify(context.Background(), userToken(t, keys, []string{"vacancies:write"}, time.Minute))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if principal.UserId != 1 || principal.SessionId != "session" || principal.Role != RoleEmployer {
+		t.Errorf("Verify() principal = %+v", principal)
+	}
+
+	if err := Check(principal, RequireRole(RoleEmployer), RequirePermission("vacancies:write")); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+
+	if err := Check(principal, RequirePermission("vacancies:delete")); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Check(missing permission) error = %v, want %v", err, ErrAccessDenied)
+	}
+}
+
+func TestClientTokenHasNoPermissions(t *testing.T) {
+	keys := newKeys(t, "first")
+	verifier, _ := newVerifier(t, keys)
+
+	token, err := jwt.NewClientToken(models.User{Id: 1, Role: models.Employer}, "session", "partner", "openid profile", keys, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	if principal.ClientId != "partner" || len(principal.Permissions) != 0 {
+		t.Errorf("Verify() principal = %+v, want client token without permissions", principal)
+	}
+
+	if err := Check(principal, RequireScope("profile")); err != nil {
+		t.Errorf("Check(scope) error = %v", err)
+	}
+
+	if err := Check(principal, RequirePermission(models.PermissionUsersReadSelf)); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Check(permission) error = %v, want %v", err, ErrAccessDenied)
+	}
+
+	if !principal.Delegated() {
+		t.Errorf("Delegated() = false for a third-party client token")
+	}
+
+	if err := Check(principal, RequireRole(RoleEmployer)); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("Check(role) error = %v, want %v", err, ErrAccessDenied)
+	}
+}
+
+func TestVerifyLooksUpKeyByKid(t *testing.T) {
+	first := newKeys(t, "first")
+	second := newKeys(t, "second")
+	verifier, jwks := newVerifier(t, first, second)
+
+	for _, keys := range []*staticKeys{first, second, first} {
+		if _, err := verifier.Verify(context.Background(), userToken(t, keys, nil, time.Minute)); err != nil {
+			t.Errorf("Verify(%s) error = %v", keys.key.Kid, err)
+		}
+	}
+
+	if jwks.fetches != 1 {
+		t.Errorf("jwks fetches = %d, want 1", jwks.fetches)
+	}
+}
+
+func TestVerifyRefreshesOnUnknownKid(t *testing.T) {
+	first := newKeys(t, "first")
+	second := newKeys(t, "second")
+	verifier, jwks := newVerifier(t, first)
+
+	if _, err := verifier.Verify(context.Background(), userToken(t, first, nil, time.Minute)); err != nil {
+		t.Fatalf("Verify(first) error = %v", err)
+	}
+
+	jwks.publish(first, second)
+	token := userToken(t, second, nil, time.Minute)
+
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Verify(unknown kid right after fetch) error = %v, want %v", err, ErrKeyNotFound)
+	}
+
+	if jwks.fetches != 1 {
+		t.Errorf("jwks fetches = %d, want 1 within %s", jwks.fetches, minRefreshInterval)
+	}
+
+	verifier.keys.mu.Lock()
+	verifier.keys.fetchedAt = verifier.keys.fetchedAt.Add(-minRefreshInterval)
+	verifier.keys.mu.Unlock()
+
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Errorf("Verify(second) error = %v", err)
+	}
+
+	if jwks.fetches != 2 {
+		t.Errorf("jwks fetches = %d, want 2", jwks.fetches)
+	}
+}
+
+func TestVerifyRejectsInvalidTokens(t *testing.T) {
+	keys := newKeys(t, "first")
+	verifier, _ := newVerifier(t, keys)
+
+	if _, err := verifier.Verify(context.Background(), ""); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("Verify(empty) error = %v, want %v", err, ErrTokenNotFound)
+	}
+
+	if _, err := verifier.Verify(context.Background(), userToken(t, keys, nil, -time.Minute)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(expired) error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	forged := newKeys(t, "first")
+	if _, err := verifier.Verify(context.Background(), userToken(t, forged, nil, time.Minute)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(wrong signature) error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	keys := newKeys(t, "first")
+	verifier, _ := newVerifier(t, keys)
+
+	handler := Middleware(verifier, RequirePermission("vacancies:write"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := PrincipalFromContext(r.Context()); !ok {
+			t.Error("principal is missing from the request context")
+		}
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		code          int
+	}{
+		{name: "missing token", code: http.StatusUnauthorized},
+		{name: "expired token", authorization: BearerSchema + userToken(t, keys, []string{"vacancies:write"}, -time.Minute), code: http.StatusUnauthorized},
+		{name: "missing permission", authorization: BearerSchema + userToken(t, keys, nil, time.Minute), code: http.StatusForbidden},
+		{name: "allowed", authorization: BearerSchema + userToken(t, keys, []string{"vacancies:write"}, time.Minute), code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package authclient
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	metadataAuthorization = "authorization"
+)
+
+func UnaryServerInterceptor(verifier *Verifier, requirements ...Requirement) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, verifier, requirements)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(verifier *Verifier, requirements ...Requirement) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), verifier, requirements)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func RequireContext(ctx context.Context, requirements ...Requirement) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, ErrTokenNotFound.Error())
+	}
+
+	if err := Check(principal, requirements...); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return nil
+}
+
+func authenticate(ctx context.Context, verifier *Verifier, requirements []Requirement) (context.Context, error) {
+	var tokenString string
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(metadataAuthorization) {
+		if t, ok := BearerToken(value); ok {
+			tokenString = t
+
+			break
+		}
+	}
+
+	principal, err := verifier.Verify(ctx, tokenString)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if err := Check(principal, requirements...); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return WithPrincipal(ctx, principal), nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
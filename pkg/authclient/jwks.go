@@ -0,0 +1,234 @@
+package authclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	minRefreshInterval = 10 * time.Second
+)
+
+var (
+	ErrKeyNotFound    = errors.New("signing key not found")
+	ErrUnsupportedKey = errors.New("unsupported key type")
+)
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type publicKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
+type KeySet struct {
+	client          *http.Client
+	url             string
+	refreshInterval time.Duration
+
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func NewKeySet(client *http.Client, url string, refreshInterval time.Duration) *KeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &KeySet{
+		client:          client,
+		url:             url,
+		refreshInterval: refreshInterval,
+		keys:            make(map[string]publicKey),
+	}
+}
+
+func (k *KeySet) Key(ctx context.Context, kid string) (string, crypto.PublicKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	fetchedAt := k.fetchedAt
+	k.mu.RUnlock()
+
+	stale := time.Since(fetchedAt) > k.refreshInterval
+	recent := time.Since(fetchedAt) < minRefreshInterval
+
+	if ok && !stale {
+		return key.algorithm, key.key, nil
+	}
+
+	if !ok && recent {
+		return "", nil, ErrKeyNotFound
+	}
+
+	if err := k.refresh(ctx, fetchedAt); err != nil {
+		if ok {
+			return key.algorithm, key.key, nil
+		}
+
+		return "", nil, err
+	}
+
+	k.mu.RLock()
+	key, ok = k.keys[kid]
+	k.mu.RUnlock()
+
+	if !ok {
+		return "", nil, ErrKeyNotFound
+	}
+
+	return key.algorithm, key.key, nil
+}
+
+func (k *KeySet) Refresh(ctx context.Context) error {
+	k.mu.RLock()
+	fetchedAt := k.fetchedAt
+	k.mu.RUnlock()
+
+	return k.refresh(ctx, fetchedAt)
+}
+
+func (k *KeySet) refresh(ctx context.Context, seen time.Time) error {
+	const op = "authclient.KeySet.Refresh"
+
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+
+	k.mu.RLock()
+	refreshed := k.fetchedAt.After(seen)
+	k.mu.RUnlock()
+
+	if refreshed {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", op, res.StatusCode)
+	}
+
+	var set jsonWebKeySet
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := parseKey(jwk)
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = publicKey{algorithm: jwk.Alg, key: key}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func parseKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKey
+		}
+
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, ErrUnsupportedKey
+		}
+
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
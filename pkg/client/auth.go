@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	RoleJobSeeker = "jobseeker"
+	RoleEmployer  = "employer"
+)
+
+type RegisterRequest struct {
+	FullName string `json:"full_name"`
+	Password string `json:"password"`
+	Phone    string `json:"phone"`
+	Email    string `json:"email"`
+	Role     string `json:"user_role"`
+}
+
+type LoginRequest struct {
+	ContactInfo string `json:"contact_info"`
+	Password    string `json:"password"`
+}
+
+type LoginResult struct {
+	Tokens
+	MfaRequired bool
+	MfaToken    string
+}
+
+type LoginMfaRequest struct {
+	MfaToken     string `json:"mfa_token"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+type RestorePasswordRequest struct {
+	Link        string `json:"link"`
+	NewPassword string `json:"new_password"`
+}
+
+type tokensResponse struct {
+	Token        string
+	RefreshToken string
+	MfaRequired  bool
+	MfaToken     string
+}
+
+func (c *Client) Register(ctx context.Context, req RegisterRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/auth/register", req, nil)
+}
+
+func (c *Client) Login(ctx context.Context, req LoginRequest) (*LoginResult, error) {
+	var res tokensResponse
+	if err := c.do(ctx, http.MethodGet, "/api/auth/login", req, &res); err != nil {
+		return nil, err
+	}
+
+	result := &LoginResult{
+		Tokens:      Tokens{AccessToken: res.Token, RefreshToken: res.RefreshToken},
+		MfaRequired: res.MfaRequired,
+		MfaToken:    res.MfaToken,
+	}
+
+	if !result.MfaRequired {
+		c.SetTokens(result.Tokens)
+	}
+
+	return result, nil
+}
+
+func (c *Client) LoginMfa(ctx context.Context, req LoginMfaRequest) (*Tokens, error) {
+	var res tokensResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auth/login/mfa", req, &res); err != nil {
+		return nil, err
+	}
+
+	tokens := &Tokens{AccessToken: res.Token, RefreshToken: res.RefreshToken}
+	c.SetTokens(*tokens)
+
+	return tokens, nil
+}
+
+func (c *Client) Refresh(ctx context.Context) (*Tokens, error) {
+	if err := c.refreshAfter(ctx, c.Tokens().AccessToken); err != nil {
+		return nil, err
+	}
+
+	tokens := c.Tokens()
+
+	return &tokens, nil
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.doAuthenticated(ctx, http.MethodPost, "/api/auth/logout", nil, nil); err != nil {
+		return err
+	}
+
+	c.SetTokens(Tokens{})
+
+	return nil
+}
+
+func (c *Client) ForgotPassword(ctx context.Context, contactInfo string) error {
+	return c.do(ctx, http.MethodPost, "/api/auth/forgot-password", map[string]string{"contact_info": contactInfo}, nil)
+}
+
+func (c *Client) RestorePassword(ctx context.Context, req RestorePasswordRequest) error {
+	return c.do(ctx, http.MethodPut, "/api/auth/restore-password", req, nil)
+}
+
+func (c *Client) refreshTokens(ctx context.Context, refreshToken string) (*Tokens, error) {
+	var res tokensResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auth/refresh-tokens", map[string]string{"refresh_token": refreshToken}, &res); err != nil {
+		return nil, err
+	}
+
+	return &Tokens{AccessToken: res.Token, RefreshToken: res.RefreshToken}, nil
+}
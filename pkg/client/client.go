@@ -0,0 +1,233 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	statusOk           = "OK"
+	contentTypeProblem = "application/problem+json"
+	headerETag         = "ETag"
+	headerIfMatch      = "If-Match"
+)
+
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type envelope struct {
+	Status string `json:"status"`
+}
+
+type problem struct {
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail"`
+	Code   string       `json:"code"`
+	Errors []FieldError `json:"errors"`
+}
+
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+
+	mu             sync.Mutex
+	tokens         Tokens
+	onTokenRefresh func(Tokens)
+	refreshing     *refreshCall
+}
+
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+func New(baseUrl string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: httpClient,
+	}
+}
+
+func (c *Client) SetTokens(tokens Tokens) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens = tokens
+}
+
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tokens
+}
+
+func (c *Client) OnTokenRefresh(fn func(Tokens)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onTokenRefresh = fn
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, out any) error {
+	_, err := c.send(ctx, method, path, nil, body, out, "")
+
+	return err
+}
+
+func (c *Client) doAuthenticated(ctx context.Context, method, path string, body any, out any) error {
+	_, err := c.exchangeAuthenticated(ctx, method, path, nil, body, out)
+
+	return err
+}
+
+func (c *Client) exchangeAuthenticated(ctx context.Context, method, path string, header http.Header, body any, out any) (http.Header, error) {
+	accessToken := c.Tokens().AccessToken
+
+	resHeader, err := c.send(ctx, method, path, header, body, out, accessToken)
+	if !errors.Is(err, ErrUnauthorized) {
+		return resHeader, err
+	}
+
+	if refreshErr := c.refreshAfter(ctx, accessToken); refreshErr != nil {
+		return resHeader, err
+	}
+
+	return c.send(ctx, method, path, header, body, out, c.Tokens().AccessToken)
+}
+
+func (c *Client) refreshAfter(ctx context.Context, staleAccessToken string) error {
+	c.mu.Lock()
+
+	if c.tokens.AccessToken != staleAccessToken {
+		c.mu.Unlock()
+
+		return nil
+	}
+
+	if call := c.refreshing; call != nil {
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if c.tokens.RefreshToken == "" {
+		c.mu.Unlock()
+
+		return ErrNoRefreshToken
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	c.refreshing = call
+	refreshToken := c.tokens.RefreshToken
+	c.mu.Unlock()
+
+	tokens, err := c.refreshTokens(ctx, refreshToken)
+
+	c.mu.Lock()
+	c.refreshing = nil
+	if err == nil && c.tokens.RefreshToken == refreshToken {
+		c.tokens = *tokens
+	}
+	onTokenRefresh := c.onTokenRefresh
+	c.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+
+	if err != nil {
+		return err
+	}
+
+	if onTokenRefresh != nil {
+		onTokenRefresh(*tokens)
+	}
+
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body any, out any, accessToken string) (http.Header, error) {
+	const op = "client.send"
+
+	var reader io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		reader = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		p := problem{Status: res.StatusCode, Title: http.StatusText(res.StatusCode)}
+		if strings.HasPrefix(res.Header.Get("Content-Type"), contentTypeProblem) {
+			_ = json.Unmarshal(data, &p)
+		}
+
+		return res.Header, newError(p)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Status != statusOk {
+		return res.Header, fmt.Errorf("%s: %w", op, ErrUnexpectedResponse)
+	}
+
+	if out == nil {
+		return res.Header, nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return res.Header, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res.Header, nil
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeJSON(w http.ResponseWriter, status int, contentType string, body any) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeOk(w http.ResponseWriter, body map[string]any) {
+	body["status"] = statusOk
+	writeJSON(w, http.StatusOK, "application/json", body)
+}
+
+func writeProblem(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, contentTypeProblem, problem{Title: http.StatusText(status), Status: status, Code: code, Detail: code})
+}
+
+func TestProblemMapsToTypedError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        any
+		want        error
+		code        string
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, contentType: contentTypeProblem, body: problem{Status: 401, Code: "unauthorized"}, want: ErrUnauthorized, code: "unauthorized"},
+		{name: "version conflict", status: http.StatusPreconditionFailed, contentType: contentTypeProblem, body: problem{Status: 412, Code: "version_conflict"}, want: ErrVersionConflict, code: "version_conflict"},
+		{name: "account locked", status: http.StatusTooManyRequests, contentType: contentTypeProblem, body: problem{Status: 429, Code: "account_locked"}, want: ErrAccountLocked, code: "account_locked"},
+		{name: "unknown code", status: http.StatusBadRequest, contentType: contentTypeProblem, body: problem{Status: 400, Code: "something_new"}, want: ErrUnexpectedResponse, code: "something_new"},
+		{name: "not a problem", status: http.StatusBadGateway, contentType: "text/html", body: "bad gateway", want: ErrUnexpectedResponse},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, tt.status, tt.contentType, tt.body)
+		}))
+
+		err := New(server.URL, server.Client()).Register(context.Background(), RegisterRequest{})
+		server.Close()
+
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s: error %v is not *Error", tt.name, err)
+		}
+
+		if apiErr.StatusCode != tt.status || apiErr.Code != tt.code {
+			t.Errorf("%s: got status %d code %q, want %d %q", tt.name, apiErr.StatusCode, apiErr.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestValidationProblemKeepsFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusBadRequest, contentTypeProblem, problem{
+			Status: http.StatusBadRequest,
+			Code:   "validation_failed",
+			Errors: []FieldError{{Field: "email", Rule: "email", Message: "field email is not a valid email"}},
+		})
+	}))
+	defer server.Close()
+
+	err := New(server.URL, server.Client()).Register(context.Background(), RegisterRequest{})
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrValidation) {
+		t.Fatalf("error = %v, want validation *Error", err)
+	}
+
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "email" {
+		t.Errorf("fields = %+v, want email field", apiErr.Fields)
+	}
+}
+
+type refreshServer struct {
+	refreshes atomic.Int32
+	release   chan struct{}
+}
+
+func (s *refreshServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/auth/refresh-tokens":
+		s.refreshes.Add(1)
+
+		if s.release != nil {
+			<-s.release
+		}
+
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req["refresh_token"] != "refresh" {
+			writeProblem(w, http.StatusUnauthorized, "invalid_token")
+
+			return
+		}
+
+		writeOk(w, map[string]any{"Token": "fresh", "RefreshToken": "refresh-2"})
+	case "/api/auth/me":
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			writeProblem(w, http.StatusUnauthorized, "unauthorized")
+
+			return
+		}
+
+		writeOk(w, map[string]any{"id": 1, "full_name": "Ivan"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestRefreshesAndRetriesAfterUnauthorized(t *testing.T) {
+	handler := &refreshServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c := New(server.URL, server.Client())
+	c.SetTokens(Tokens{AccessToken: "stale", RefreshToken: "refresh"})
+
+	var refreshed Tokens
+	c.OnTokenRefresh(func(tokens Tokens) {
+		refreshed = tokens
+	})
+
+	user, err := c.Me(context.Background())
+	if err != nil {
+		t.Fatalf("Me() error = %v", err)
+	}
+
+	if user.Id != 1 {
+		t.Errorf("user id = %d, want 1", user.Id)
+	}
+
+	want := Tokens{AccessToken: "fresh", RefreshToken: "refresh-2"}
+	if got := c.Tokens(); got != want {
+		t.Errorf("tokens = %+v, want %+v", got, want)
+	}
+
+	if refreshed != want {
+		t.Errorf("OnTokenRefresh got %+v, want %+v", refreshed, want)
+	}
+
+	if got := handler.refreshes.Load(); got != 1 {
+		t.Errorf("refreshes = %d, want 1", got)
+	}
+}
+
+func TestFailedRefreshReturnsOriginalError(t *testing.T) {
+	server := httptest.NewServer(&refreshServer{})
+	defer server.Close()
+
+	c := New(server.URL, server.Client())
+	c.SetTokens(Tokens{AccessToken: "stale", RefreshToken: "revoked"})
+
+	if _, err := c.Me(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Me() error = %v, want %v", err, ErrUnauthorized)
+	}
+
+	if got := c.Tokens().AccessToken; got != "stale" {
+		t.Errorf("access token = %q, want stale", got)
+	}
+}
+
+func TestConcurrentRequestsShareRefresh(t *testing.T) {
+	handler := &refreshServer{release: make(chan struct{})}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c := New(server.URL, server.Client())
+	c.SetTokens(Tokens{AccessToken: "stale", RefreshToken: "refresh"})
+
+	const requests = 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := c.Me(context.Background())
+			errs <- err
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for handler.refreshes.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	unblocked := make(chan Tokens)
+	go func() {
+		unblocked <- c.Tokens()
+	}()
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("client lock is held during the refresh request")
+	}
+
+	close(handler.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Me() error = %v", err)
+		}
+	}
+
+	if got := handler.refreshes.Load(); got != 1 {
+		t.Errorf("refreshes = %d, want 1", got)
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	etag := `"1"`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set(headerETag, etag)
+			writeOk(w, map[string]any{"id": 1, "full_name": "Ivan"})
+		case http.MethodPatch:
+			switch r.Header.Get(headerIfMatch) {
+			case "":
+				writeProblem(w, http.StatusPreconditionRequired, "precondition_required")
+			case etag:
+				var req map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&req)
+
+				etag = `"2"`
+				w.Header().Set(headerETag, etag)
+				writeOk(w, map[string]any{"id": 1, "full_name": req["full_name"]})
+			default:
+				writeProblem(w, http.StatusPreconditionFailed, "version_conflict")
+			}
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL, server.Client())
+	c.SetTokens(Tokens{AccessToken: "access"})
+
+	user, err := c.Me(context.Background())
+	if err != nil {
+		t.Fatalf("Me() error = %v", err)
+	}
+
+	if user.ETag != `"1"` {
+		t.Fatalf("ETag = %q, want %q", user.ETag, `"1"`)
+	}
+
+	name := "Petr"
+	if _, err := c.UpdateMe(context.Background(), UpdateProfileRequest{FullName: &name}); !errors.Is(err, ErrPreconditionNeeded) {
+		t.Errorf("UpdateMe() without If-Match error = %v, want %v", err, ErrPreconditionNeeded)
+	}
+
+	updated, err := c.UpdateMe(context.Background(), UpdateProfileRequest{IfMatch: user.ETag, FullName: &name})
+	if err != nil {
+		t.Fatalf("UpdateMe() error = %v", err)
+	}
+
+	if updated.ETag != `"2"` || updated.FullName != name {
+		t.Errorf("updated = %+v, want ETag %q and name %q", updated, `"2"`, name)
+	}
+
+	if _, err := c.UpdateMe(context.Background(), UpdateProfileRequest{IfMatch: user.ETag, FullName: &name}); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("UpdateMe() with stale If-Match error = %v, want %v", err, ErrVersionConflict)
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidRequest     = errors.New("invalid request")
+	ErrValidation         = errors.New("request validation failed")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenExpired       = errors.New("token expired")
+	ErrTokenReused        = errors.New("refresh token reused")
+	ErrInvalidCode        = errors.New("invalid code")
+	ErrAccessDenied       = errors.New("access not allowed")
+	ErrRoleNotAllowed     = errors.New("role is not allowed for registration")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrPhoneNotVerified   = errors.New("phone is not verified")
+	ErrBadPassword        = errors.New("bad password")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("user already exists")
+	ErrAlreadyVerified    = errors.New("already verified")
+	ErrAccountLocked      = errors.New("account is temporarily locked")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrRateLimited        = errors.New("too many requests")
+	ErrVersionConflict    = errors.New("resource was modified concurrently")
+	ErrPreconditionNeeded = errors.New("if-match precondition is required")
+	ErrInternal           = errors.New("internal server error")
+	ErrNoRefreshToken     = errors.New("refresh token is not set")
+	ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
+var codeErrors = map[string]error{
+	"invalid_request":       ErrInvalidRequest,
+	"validation_failed":     ErrValidation,
+	"unauthorized":          ErrUnauthorized,
+	"invalid_credentials":   ErrInvalidCredentials,
+	"invalid_token":         ErrInvalidToken,
+	"token_expired":         ErrTokenExpired,
+	"token_reused":          ErrTokenReused,
+	"invalid_code":          ErrInvalidCode,
+	"access_denied":         ErrAccessDenied,
+	"role_not_allowed":      ErrRoleNotAllowed,
+	"email_not_verified":    ErrEmailNotVerified,
+	"phone_not_verified":    ErrPhoneNotVerified,
+	"bad_password":          ErrBadPassword,
+	"user_not_found":        ErrUserNotFound,
+	"user_exists":           ErrUserExists,
+	"already_verified":      ErrAlreadyVerified,
+	"account_locked":        ErrAccountLocked,
+	"too_many_attempts":     ErrTooManyAttempts,
+	"rate_limited":          ErrRateLimited,
+	"version_conflict":      ErrVersionConflict,
+	"precondition_required": ErrPreconditionNeeded,
+	"internal_error":        ErrInternal,
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type Error struct {
+	StatusCode int
+	Code       string
+	Title      string
+	Detail     string
+	Fields     []FieldError
+	err        error
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+
+	if e.Code != "" {
+		return fmt.Sprintf("auth api: %s (%s)", message, e.Code)
+	}
+
+	return fmt.Sprintf("auth api: %s", message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+func newError(p problem) *Error {
+	err, ok := codeErrors[p.Code]
+	if !ok {
+		err = ErrUnexpectedResponse
+	}
+
+	return &Error{
+		StatusCode: p.Status,
+		Code:       p.Code,
+		Title:      p.Title,
+		Detail:     p.Detail,
+		Fields:     p.Errors,
+		err:        err,
+	}
+}
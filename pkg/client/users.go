@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type User struct {
+	Id            int64  `json:"id"`
+	FullName      string `json:"full_name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	PendingEmail  string `json:"pending_email,omitempty"`
+	Phone         string `json:"phone"`
+	PhoneVerified bool   `json:"phone_verified"`
+	PendingPhone  string `json:"pending_phone,omitempty"`
+	Role          string `json:"user_role"`
+	ETag          string `json:"-"`
+}
+
+type UpdateProfileRequest struct {
+	IfMatch  string  `json:"-"`
+	FullName *string `json:"full_name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Phone    *string `json:"phone,omitempty"`
+}
+
+type UpdateUserRequest struct {
+	IfMatch  string `json:"-"`
+	UserId   int64  `json:"user_id"`
+	FullName string `json:"new_full_name"`
+	Phone    string `json:"new_phone"`
+	Email    string `json:"new_email"`
+}
+
+type userIdRequest struct {
+	UserId int64 `json:"user_id"`
+}
+
+func (c *Client) User(ctx context.Context, userId int64) (*User, error) {
+	var user User
+	header, err := c.exchangeAuthenticated(ctx, http.MethodGet, "/api/auth/users/"+strconv.FormatInt(userId, 10), nil, nil, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ETag = header.Get(headerETag)
+
+	return &user, nil
+}
+
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var user User
+	header, err := c.exchangeAuthenticated(ctx, http.MethodGet, "/api/auth/me", nil, nil, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ETag = header.Get(headerETag)
+
+	return &user, nil
+}
+
+func (c *Client) UpdateMe(ctx context.Context, req UpdateProfileRequest) (*User, error) {
+	var user User
+	header, err := c.exchangeAuthenticated(ctx, http.MethodPatch, "/api/auth/me", ifMatch(req.IfMatch), req, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ETag = header.Get(headerETag)
+
+	return &user, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, req UpdateUserRequest) (string, error) {
+	header, err := c.exchangeAuthenticated(ctx, http.MethodPut, "/api/auth/update-user", ifMatch(req.IfMatch), req, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return header.Get(headerETag), nil
+}
+
+func (c *Client) DeleteUser(ctx context.Context, userId int64) error {
+	return c.doAuthenticated(ctx, http.MethodPut, "/api/auth/delete-user", userIdRequest{UserId: userId}, nil)
+}
+
+func (c *Client) RestoreUser(ctx context.Context, userId int64) error {
+	return c.doAuthenticated(ctx, http.MethodPut, "/api/auth/restore-user", userIdRequest{UserId: userId}, nil)
+}
+
+func ifMatch(etag string) http.Header {
+	if etag == "" {
+		return nil
+	}
+
+	return http.Header{headerIfMatch: {etag}}
+}